@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -25,10 +26,15 @@ const (
 	UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/74.0.3729.169 Safari/537.36"
 )
 
+// ErrRateLimited is returned when the instance's rate-limit is exceeded.
+var ErrRateLimited = errors.New("Client: Rate-limit exceeded")
+
 // Client stores information about a client.
 type Client struct {
 	uri *url.URL
 
+	apiAgent, imageAgent string
+
 	rctx, sctx       context.Context
 	rcancel, scancel context.CancelFunc
 
@@ -39,6 +45,9 @@ type Client struct {
 
 var client Client
 
+// imageRequest is the context key to mark requests for images.
+type imageRequest struct{}
+
 // Init intitializes the client.
 func Init() {
 	client = Client{}
@@ -58,6 +67,64 @@ func Init() {
 	client.sctx, client.scancel = context.WithCancel(context.Background())
 }
 
+// ParseProxy parses and validates the provided proxy URL.
+// HTTP, HTTPS and SOCKS5 proxies are supported.
+func ParseProxy(proxy string) (*url.URL, error) {
+	uri, err := url.Parse(proxy)
+	if err != nil || uri.Host == "" {
+		return nil, fmt.Errorf("Client: Invalid proxy URL %s", proxy)
+	}
+
+	switch uri.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return uri, nil
+	}
+
+	return nil, fmt.Errorf("Client: Unsupported proxy scheme %s", uri.Scheme)
+}
+
+// SetProxy sets the proxy through which all requests are sent.
+func SetProxy(proxy string) error {
+	uri, err := ParseProxy(proxy)
+	if err != nil {
+		return err
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		transport.Proxy = http.ProxyURL(uri)
+	}
+
+	return nil
+}
+
+// SetUserAgents sets the user agents for API and image requests.
+// If a user agent is empty, the default user agent is used.
+func SetUserAgents(api, image string) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	client.apiAgent, client.imageAgent = api, image
+}
+
+// userAgent returns the user agent for the request with the provided context.
+func userAgent(ctx context.Context) string {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+
+	if image, _ := ctx.Value(imageRequest{}).(bool); image && client.imageAgent != "" {
+		return client.imageAgent
+	}
+
+	if client.apiAgent != "" {
+		return client.apiAgent
+	}
+
+	return UserAgent
+}
+
 // Host returns the client's host.
 func Host() string {
 	if client.uri == nil {
@@ -72,17 +139,51 @@ func SetHost(host string) *url.URL {
 	client.mutex.Lock()
 	defer client.mutex.Unlock()
 
-	client.uri, _ = url.Parse(host)
-	if client.uri.Scheme == "" {
-		client.uri.Scheme = "https"
-		client.uri, _ = url.Parse(client.uri.String())
-	}
+	client.uri = hostURL(host)
+	defer notifyHealth()
 
 	return client.uri
 }
 
-// Get send a GET request to the host and returns a response
+// hostURL parses the provided host into a URL,
+// using the HTTPS scheme if no scheme is specified.
+func hostURL(host string) *url.URL {
+	uri, _ := url.Parse(host)
+	if uri.Scheme == "" {
+		uri.Scheme = "https"
+		uri, _ = url.Parse(uri.String())
+	}
+
+	return uri
+}
+
+// Get send a GET request to the host and returns a response.
+// If the instance is unavailable and failover is enabled, the request
+// is retried once on the next available instance. Authenticated requests
+// are not retried, since the credentials are specific to the instance.
 func Get(ctx context.Context, param string, token ...string) (*http.Response, error) {
+	host := Instance()
+
+	res, err := get(ctx, param, token...)
+	if err == nil || token != nil || ctx.Err() != nil || !errors.Is(err, ErrUnavailable) {
+		return res, err
+	}
+
+	if _, ferr := SwitchInstance(host); ferr != nil {
+		return nil, err
+	}
+
+	return get(ctx, param)
+}
+
+// GetImage sends a GET request for an image to the host and returns a response.
+// Unlike Get, the request is sent with the user agent set for images.
+func GetImage(ctx context.Context, param string) (*http.Response, error) {
+	return Get(context.WithValue(ctx, imageRequest{}, true), param)
+}
+
+// get sends a GET request to the host and returns a response.
+func get(ctx context.Context, param string, token ...string) (*http.Response, error) {
 	res, err := request(ctx, http.MethodGet, param, nil, token...)
 	if err != nil {
 		return nil, err
@@ -91,6 +192,53 @@ func Get(ctx context.Context, param string, token ...string) (*http.Response, er
 	return checkStatusCode(res, http.StatusOK)
 }
 
+// GetURL sends a GET request to the provided URL, which need not be
+// on the current host, and returns a response.
+func GetURL(ctx context.Context, uri string, codes ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", userAgent(ctx))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, netError(err)
+	}
+
+	if codes == nil {
+		codes = append(codes, http.StatusOK)
+	}
+
+	return checkStatusCode(res, codes...)
+}
+
+// PostURL sends a POST request with the provided headers to the provided URL,
+// which need not be on the current host, and returns a response.
+func PostURL(ctx context.Context, uri, body string, header http.Header, codes ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range header {
+		req.Header.Set(key, header.Get(key))
+	}
+	req.Header.Set("User-Agent", userAgent(ctx))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, netError(err)
+	}
+
+	if codes == nil {
+		codes = append(codes, http.StatusOK)
+	}
+
+	return checkStatusCode(res, codes...)
+}
+
 // Post send a POST request to the host and returns a response.
 func Post(ctx context.Context, param, body string, token ...string) (*http.Response, error) {
 	res, err := request(ctx, http.MethodPost, param, bytes.NewBuffer([]byte(body)), token...)
@@ -185,12 +333,14 @@ func request(ctx context.Context, method, param string, body io.Reader, token ..
 		return nil, fmt.Errorf("Client: Not initialized")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, Host()+param, body)
+	host := Host()
+
+	req, err := http.NewRequestWithContext(ctx, method, host+param, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("User-Agent", userAgent(ctx))
 	if method == http.MethodPost || method == http.MethodPatch {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -207,11 +357,21 @@ func request(ctx context.Context, method, param string, body io.Reader, token ..
 		}
 	}
 
+	start := time.Now()
+
 	res, err := client.Do(req)
 	if err != nil {
-		return nil, netError(err)
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		recordHealth(host, 0, false)
+
+		return nil, unavailableError{netError(err)}
 	}
 
+	recordHealth(host, time.Since(start), !isUnavailable(res))
+
 	return res, nil
 }
 
@@ -219,6 +379,11 @@ func request(ctx context.Context, method, param string, body io.Reader, token ..
 func checkStatusCode(res *http.Response, codes ...int) (*http.Response, error) {
 	var checked int
 
+	if res.StatusCode == http.StatusTooManyRequests {
+		res.Body.Close()
+		return nil, ErrRateLimited
+	}
+
 	for _, code := range codes {
 		if res.StatusCode != code {
 			checked++
@@ -235,6 +400,11 @@ func checkStatusCode(res *http.Response, codes ...int) (*http.Response, error) {
 		if err := utils.JSON().NewDecoder(res.Body).Decode(&responseError); err == nil {
 			message += ": " + responseError.Error
 		}
+		res.Body.Close()
+
+		if isUnavailable(res) {
+			return nil, unavailableError{fmt.Errorf(message, res.StatusCode)}
+		}
 
 		return nil, fmt.Errorf(message, res.StatusCode)
 	}
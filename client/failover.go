@@ -0,0 +1,122 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/darkhz/invidtui/utils"
+)
+
+// ErrUnavailable is matched by errors which indicate that
+// the instance could not be reached, or returned a server error.
+var ErrUnavailable = errors.New("Client: Instance unavailable")
+
+// Failover stores the state for switching to another instance
+// if the current instance is unavailable.
+type Failover struct {
+	enabled   bool
+	instances []string
+	failed    map[string]struct{}
+	handler   func(instance string)
+
+	mutex sync.Mutex
+}
+
+// unavailableError wraps an error, and marks it as an ErrUnavailable error.
+type unavailableError struct {
+	error
+}
+
+var failover Failover
+
+// Is returns whether the target error is ErrUnavailable.
+func (e unavailableError) Is(target error) bool {
+	return target == ErrUnavailable
+}
+
+// SetFailover enables or disables automatically switching to
+// another instance when the current one is unavailable.
+func SetFailover(enabled bool) {
+	failover.mutex.Lock()
+	defer failover.mutex.Unlock()
+
+	failover.enabled = enabled
+}
+
+// SetInstances sets the list of instances to switch between.
+// If it is not set, the list of public instances is used.
+func SetInstances(instances []string) {
+	failover.mutex.Lock()
+	defer failover.mutex.Unlock()
+
+	failover.instances = instances
+}
+
+// SetSwitchHandler sets the handler which is called
+// with the new instance after an instance switch.
+func SetSwitchHandler(handler func(instance string)) {
+	failover.mutex.Lock()
+	defer failover.mutex.Unlock()
+
+	failover.handler = handler
+}
+
+// SwitchInstance marks the provided instance as failed, and switches
+// to the next available instance from the list of instances. If the
+// client has already switched away from the failed instance, for example
+// due to a parallel request, the current instance is returned.
+func SwitchInstance(failed string) (string, error) {
+	failover.mutex.Lock()
+	defer failover.mutex.Unlock()
+
+	if !failover.enabled {
+		return "", fmt.Errorf("Client: Instance failover is disabled")
+	}
+
+	if current := Instance(); current != failed {
+		return current, nil
+	}
+
+	if failover.failed == nil {
+		failover.failed = make(map[string]struct{})
+	}
+	failover.failed[utils.GetHostname(failed)] = struct{}{}
+
+	if failover.instances == nil {
+		instances, err := GetInstances()
+		if err != nil {
+			return "", err
+		}
+
+		failover.instances = instances
+	}
+
+	for _, instance := range failover.instances {
+		if _, ok := failover.failed[utils.GetHostname(instance)]; ok {
+			continue
+		}
+
+		host, err := CheckInstance(instance)
+		if err != nil {
+			failover.failed[utils.GetHostname(instance)] = struct{}{}
+			continue
+		}
+
+		SetHost(host)
+
+		if failover.handler != nil {
+			go failover.handler(host)
+		}
+
+		return host, nil
+	}
+
+	return "", fmt.Errorf("Client: No other instance is available")
+}
+
+// isUnavailable returns whether the response indicates that the instance is unavailable.
+func isUnavailable(res *http.Response) bool {
+	return res.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// Health stores the health of the current instance, as observed
+// from the latest request that was sent to it.
+type Health struct {
+	host      string
+	latency   time.Duration
+	available bool
+	handler   func()
+
+	mutex sync.Mutex
+}
+
+var health Health
+
+// SetHealthHandler sets the handler which is called
+// whenever the health of the current instance is updated.
+func SetHealthHandler(handler func()) {
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	health.handler = handler
+}
+
+// InstanceHealth returns the latency of the latest request to the current instance,
+// and whether the instance was available. If no request has been sent to the current
+// instance yet, its health is unknown, and known is false.
+func InstanceHealth() (latency time.Duration, available, known bool) {
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	if health.host == "" || health.host != Instance() {
+		return 0, false, false
+	}
+
+	return health.latency, health.available, true
+}
+
+// recordHealth stores the health of the provided host, if it is the current instance.
+func recordHealth(host string, latency time.Duration, available bool) {
+	health.mutex.Lock()
+
+	if host != Instance() {
+		health.mutex.Unlock()
+		return
+	}
+
+	health.host, health.latency, health.available = host, latency, available
+	health.mutex.Unlock()
+
+	notifyHealth()
+}
+
+// notifyHealth calls the health handler, if it is set.
+func notifyHealth() {
+	health.mutex.Lock()
+	handler := health.handler
+	health.mutex.Unlock()
+
+	if handler != nil {
+		go handler()
+	}
+}
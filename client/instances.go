@@ -1,10 +1,13 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/darkhz/invidtui/utils"
 )
@@ -19,14 +22,11 @@ func GetInstances() ([]string, error) {
 	var instances [][]interface{}
 	var list []string
 
-	host := Instance()
-
-	dataURI := SetHost(InstanceData)
-
-	res, err := Get(Ctx(), fmt.Sprintf("%s?%s", dataURI.Path, dataURI.RawQuery))
+	res, err := GetURL(Ctx(), InstanceData)
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 
 	err = utils.JSON().NewDecoder(res.Body).Decode(&instances)
 	if err != nil {
@@ -41,8 +41,6 @@ func GetInstances() ([]string, error) {
 		}
 	}
 
-	SetHost(host)
-
 	return list, nil
 }
 
@@ -52,12 +50,23 @@ func CheckInstance(host string) (string, error) {
 		return "", fmt.Errorf("Client: Invalid URL")
 	}
 
-	SetHost(host)
-	host = Instance()
+	uri := hostURL(host)
+	host = uri.Scheme + "://" + uri.Hostname()
+
+	req, err := http.NewRequestWithContext(Ctx(), http.MethodHead, host+API+"search", nil)
+	if err != nil {
+		return "", fmt.Errorf("Client: Cannot select instance")
+	}
+
+	req.Header.Set("User-Agent", userAgent(req.Context()))
+
+	res, err := client.Do(req)
+	if err == nil {
+		res.Body.Close()
 
-	res, err := request(Ctx(), http.MethodHead, API+"search", nil)
-	if err == nil && res.StatusCode == 200 {
-		return host, nil
+		if res.StatusCode == 200 {
+			return host, nil
+		}
 	}
 
 	return "", fmt.Errorf("Client: Cannot select instance")
@@ -96,3 +105,60 @@ func GetBestInstance(custom string) (string, error) {
 
 	return bestInstance, nil
 }
+
+// ProbeInstances requests the statistics of each provided instance in parallel,
+// and returns the healthy instance with the lowest latency, along with its latency.
+func ProbeInstances(instances []string) (string, time.Duration, error) {
+	var best string
+	var latency time.Duration
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, instance := range instances {
+		wg.Add(1)
+
+		go func(instance string) {
+			defer wg.Done()
+
+			host, elapsed, err := ProbeInstance(instance)
+			if err != nil {
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if best == "" || elapsed < latency {
+				best, latency = host, elapsed
+			}
+		}(instance)
+	}
+
+	wg.Wait()
+
+	if best == "" {
+		return "", 0, fmt.Errorf("Client: None of the instances are available")
+	}
+
+	return best, latency, nil
+}
+
+// ProbeInstance requests the statistics of the provided instance,
+// and returns the instance's URL and the time taken for the request.
+func ProbeInstance(instance string) (string, time.Duration, error) {
+	uri := hostURL(instance)
+	host := uri.Scheme + "://" + uri.Hostname()
+
+	ctx, cancel := context.WithTimeout(Ctx(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+
+	res, err := GetURL(ctx, host+API+"stats")
+	if err != nil {
+		return "", 0, err
+	}
+	res.Body.Close()
+
+	return host, time.Since(start), nil
+}
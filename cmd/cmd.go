@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/darkhz/invidtui/client"
 	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/utils"
 )
 
 // Version stores the version information.
@@ -21,13 +23,18 @@ func Init() {
 
 	printVersion()
 	generate()
+	enqueue()
 
 	client.Init()
 	printInstances()
 
 	check()
+	setupLog()
+
+	setupClient()
 
 	loadInstance()
+	importTakeout()
 	loadPlayer()
 
 	printer.Stop()
@@ -40,6 +47,10 @@ func loadInstance() {
 	}
 
 	customInstance := GetOptionValue("force-instance")
+	if customInstance == "" && Instances() != nil {
+		loadInstanceFromList()
+		return
+	}
 
 	msg := "Selecting an instance"
 	if customInstance != "" {
@@ -53,13 +64,83 @@ func loadInstance() {
 		printer.Error(err.Error())
 	}
 
+	client.SetHost(instance)
+	client.SetFailover(customInstance == "")
+}
+
+// loadInstanceFromList selects an instance from the 'instances' option.
+// If the last selected instance is in the list and is available, it is
+// selected without probing the other instances. Otherwise, the instance
+// with the lowest latency is selected.
+func loadInstanceFromList() {
+	instances := Instances()
+
+	client.SetInstances(instances)
+	client.SetFailover(true)
+
+	if last := Settings.LastInstance; last != "" {
+		for _, instance := range instances {
+			if utils.GetHostname(instance) != utils.GetHostname(last) {
+				continue
+			}
+
+			printer.Print("Checking " + utils.GetHostname(last))
+
+			if host, err := client.CheckInstance(last); err == nil {
+				client.SetHost(host)
+				return
+			}
+		}
+	}
+
+	printer.Print("Probing instances")
+
+	instance, _, err := client.ProbeInstances(instances)
+	if err != nil {
+		printer.Error(err.Error())
+	}
+
 	client.SetHost(instance)
 }
 
+// setupLog sets up the log file.
+func setupLog() {
+	level := GetOptionValue("log-level")
+	if level == "" {
+		level = "warn"
+	}
+
+	if err := utils.SetupLog(filepath.Join(config.path, "invidtui.log"), level); err != nil {
+		printer.Error(err.Error())
+	}
+
+	if settingsBackup != "" {
+		utils.LogWarnf("Settings: Cannot parse values, backed up to %s", settingsBackup)
+	}
+}
+
+// setupClient applies the proxy and user agent options to the client.
+func setupClient() {
+	if proxy := GetOptionValue("proxy"); proxy != "" {
+		if err := client.SetProxy(proxy); err != nil {
+			printer.Error(err.Error())
+		}
+	}
+
+	client.SetUserAgents(UserAgent("api-user-agent"), UserAgent("thumbnail-user-agent"))
+}
+
 // loadPlayer loads the media player.
 func loadPlayer() {
 	printer.Print("Starting player")
 
+	mp.ConnectProgress = func(attempt, attempts int) {
+		if attempt > 1 {
+			printer.Print(fmt.Sprintf("Connecting to mpv (attempt %d/%d)", attempt, attempts))
+		}
+	}
+	defer func() { mp.ConnectProgress = nil }()
+
 	socketpath, err := GetPath("socket")
 	if err != nil {
 		printer.Error(err.Error())
@@ -70,14 +151,47 @@ func loadPlayer() {
 		GetOptionValue("mpv-path"),
 		GetOptionValue("ytdl-path"),
 		GetOptionValue("num-retries"),
-		client.UserAgent,
+		GetOptionValue("retry-delay"),
+		UserAgent("user-agent"),
 		socketpath,
+		PlayerArgs()...,
 	)
 	if err != nil {
 		printer.Error(err.Error())
 	}
 }
 
+// enqueue sends the URLs from the play-audio and play-video options to
+// an already running instance. If no instance is running, the application
+// is started and the URLs are queued within it instead.
+func enqueue() {
+	if !IsOptionEnabled("enqueue") {
+		return
+	}
+
+	queries := GetPlayQueries()
+	if len(queries) == 0 {
+		printer.Error("No URLs to queue")
+	}
+
+	printer.Print("Queueing URLs")
+
+	socket := SocketPath()
+
+	for i, query := range queries {
+		if err := mp.SendEnqueue("mpv", socket, query.URL, query.Audio); err != nil {
+			if i == 0 {
+				printer.Print("No running instance, starting")
+				return
+			}
+
+			printer.Error(err.Error())
+		}
+	}
+
+	printer.Print(fmt.Sprintf("Queued %d URLs", len(queries)), 0)
+}
+
 // printVersion prints the version information.
 func printVersion() {
 	if !IsOptionEnabled("version") {
@@ -2,11 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/platform"
 	"github.com/hjson/hjson-go/v4"
 	"github.com/knadh/koanf/v2"
@@ -78,6 +80,23 @@ func (c *Config) setup() {
 	}
 }
 
+// SetupConfig sets up the configuration directory and an empty configuration store,
+// without loading the configuration file or parsing the command-line options, so
+// that the settings can be stored without initializing the application.
+func SetupConfig() {
+	config.setup()
+}
+
+// SocketPath returns the path to the socket of the media player.
+func SocketPath() string {
+	return platform.Socket(filepath.Join(config.path, "socket"))
+}
+
+// StatusSocketPath returns the path to the socket which answers status queries.
+func StatusSocketPath() string {
+	return filepath.Join(config.path, "status.sock")
+}
+
 // GetPath returns the full config path for the provided file type.
 func GetPath(ftype string, nocreate ...struct{}) (string, error) {
 	var cfpath string
@@ -147,6 +166,67 @@ func GetQueryParams(queryType string) (string, string, error) {
 	return "", "", fmt.Errorf("Config: Query type not found")
 }
 
+// PlayQuery describes a URL provided via the play-audio or play-video options.
+type PlayQuery struct {
+	Audio bool
+	URL   string
+}
+
+// GetPlayQueries returns the URLs provided via the play-audio and play-video options,
+// in the order they were specified. Each option can be provided multiple times, and
+// each value can be a comma or newline-separated list of URLs, a path to a file
+// with a URL on each line, or '-' to read the URLs from the standard input.
+func GetPlayQueries() []PlayQuery {
+	var queries []PlayQuery
+
+	config.mutex.Lock()
+	defer config.mutex.Unlock()
+
+	for _, option := range options {
+		if option.Type != "play" {
+			continue
+		}
+
+		values := config.Strings(option.Name)
+		if value, ok := config.Get(option.Name).(string); ok {
+			values = []string{value}
+		}
+
+		for i, value := range values {
+			// Since the standard input can only be read once, its contents
+			// are stored in place of the value, to return the same queries
+			// on subsequent calls.
+			if value == "-" {
+				if data, err := io.ReadAll(os.Stdin); err == nil {
+					values[i] = string(data)
+					config.Set(option.Name, values)
+				}
+
+				value = values[i]
+			}
+
+			if data, err := os.ReadFile(value); err == nil {
+				value = string(data)
+			}
+
+			for _, uri := range strings.FieldsFunc(value, func(r rune) bool {
+				return r == ',' || r == '\n' || r == '\r'
+			}) {
+				if uri = strings.TrimSpace(uri); uri == "" || strings.HasPrefix(uri, "#") {
+					continue
+				}
+
+				queries = append(queries, PlayQuery{
+					Audio: option.Name == "play-audio",
+					URL:   uri,
+				})
+			}
+		}
+	}
+
+	return queries
+}
+
 // GetOptionValue returns a value for an option
 // from the configuration store.
 func GetOptionValue(key string) string {
@@ -165,6 +245,116 @@ func SetOptionValue(key string, value interface{}) {
 	config.Set(key, value)
 }
 
+// Instances returns the list of instances from the 'instances' option.
+func Instances() []string {
+	var instances []string
+
+	for _, instance := range strings.Split(GetOptionValue("instances"), ",") {
+		if instance = strings.TrimSpace(instance); instance != "" {
+			instances = append(instances, instance)
+		}
+	}
+
+	return instances
+}
+
+// PlayerArgs returns the additional arguments for the media player, from the
+// 'mpv-config', 'proxy', 'cookies', 'cache', 'demuxer-max-bytes' and 'mpv-args'
+// options. The arguments from 'mpv-args' are provided last, so that they take
+// precedence over the configuration file.
+func PlayerArgs() []string {
+	var args []string
+
+	if file := GetOptionValue("mpv-config"); file != "" {
+		args = append(args, "--include="+file)
+	}
+
+	args = append(args, proxyArgs(GetOptionValue("proxy"))...)
+
+	if cookies := GetOptionValue("cookies"); cookies != "" {
+		args = append(args, "--ytdl-raw-options-append=cookies="+cookies)
+	}
+
+	if cache := GetOptionValue("cache"); cache != "" {
+		args = append(args, "--cache="+cache)
+	}
+
+	if size := GetOptionValue("demuxer-max-bytes"); size != "" {
+		args = append(args, "--demuxer-max-bytes="+size)
+	}
+
+	return append(args, strings.Fields(GetOptionValue("mpv-args"))...)
+}
+
+// proxyArgs returns the player arguments for the provided proxy.
+// MPV can only stream via plain HTTP proxies by itself, whereas
+// youtube-dl/yt-dlp supports HTTPS and SOCKS5 proxies as well.
+func proxyArgs(proxy string) []string {
+	var args []string
+
+	if proxy == "" {
+		return args
+	}
+
+	if uri, err := client.ParseProxy(proxy); err == nil && uri.Scheme == "http" {
+		args = append(args, "--http-proxy="+proxy)
+	}
+
+	return append(args, "--ytdl-raw-options-append=proxy="+proxy)
+}
+
+// ScreenshotDir returns the directory from the 'screenshot-dir' option, with a
+// leading '~' expanded to the home directory. If the option is not set, the
+// "screenshots" directory within the config directory is returned. The
+// directory is created if it does not exist.
+func ScreenshotDir() (string, error) {
+	dir := GetOptionValue("screenshot-dir")
+	if dir == "" {
+		dir = filepath.Join(config.path, "screenshots")
+	}
+
+	if dir == "~" || strings.HasPrefix(dir, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("Config: Cannot expand %s", dir)
+		}
+
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("Config: Cannot create screenshot directory at %s", dir)
+	}
+
+	return dir, nil
+}
+
+// LyricsDir returns the directory within the config directory where the fetched
+// lyrics are cached. The directory is created if it does not exist.
+func LyricsDir() (string, error) {
+	dir := filepath.Join(config.path, "lyrics")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("Config: Cannot create lyrics directory at %s", dir)
+	}
+
+	return dir, nil
+}
+
+// UserAgent returns the user agent from the provided option.
+// If the option is not set, the value of the 'user-agent' option is returned.
+func UserAgent(option string) string {
+	if agent := GetOptionValue(option); agent != "" {
+		return agent
+	}
+
+	if agent := GetOptionValue("user-agent"); agent != "" {
+		return agent
+	}
+
+	return client.UserAgent
+}
+
 // IsOptionEnabled returns if an option is enabled.
 func IsOptionEnabled(key string) bool {
 	config.mutex.Lock()
@@ -181,9 +371,67 @@ func generateConfig() {
 	for _, option := range options {
 		for _, name := range []string{
 			"force-instance",
+			"instances",
 			"download-dir",
+			"screenshot-dir",
 			"num-retries",
+			"retry-delay",
 			"video-res",
+			"audio-format",
+			"resume-mode",
+			"skip-count",
+			"history-limit",
+			"load-concurrency",
+			"rate-limit-retries",
+			"rate-limit-delay",
+			"reconnect-retries",
+			"reconnect-delay",
+			"autosave-interval",
+			"update-interval",
+			"log-level",
+			"notify",
+			"queue-autoclear",
+			"remember-positions",
+			"search-mode",
+			"startup-action",
+			"startup-url",
+			"startup-autoplay",
+			"stop-action",
+			"lock-quality",
+			"downgrade-threshold",
+			"downgrade-window",
+			"debug",
+			"sponsorblock-categories",
+			"progress-fill",
+			"progress-empty",
+			"progress-delimiters",
+			"progress-style",
+			"disable-images",
+			"level-meter",
+			"status-socket",
+			"loop-reshuffle",
+			"image-dithering",
+			"proxy",
+			"user-agent",
+			"api-user-agent",
+			"thumbnail-user-agent",
+			"hwdec",
+			"cache",
+			"cache-secs",
+			"demuxer-max-bytes",
+			"lyrics-provider",
+			"mpv-args",
+			"mpv-config",
+			"cookies",
+			"external-player",
+			"external-player-stop",
+			"status-file",
+			"status-format",
+			"scrobbler",
+			"scrobble-token",
+			"lastfm-api-key",
+			"lastfm-api-secret",
+			"on-track-change",
 		} {
 			if option.Type == "path" || option.Name == name {
 				genMap[option.Name] = config.Get(option.Name)
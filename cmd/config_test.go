@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProxyArgs(t *testing.T) {
+	tests := []struct {
+		proxy string
+		want  []string
+	}{
+		{proxy: "", want: nil},
+		{
+			proxy: "http://127.0.0.1:8080",
+			want: []string{
+				"--http-proxy=http://127.0.0.1:8080",
+				"--ytdl-raw-options-append=proxy=http://127.0.0.1:8080",
+			},
+		},
+		{
+			proxy: "https://proxy.example.com",
+			want:  []string{"--ytdl-raw-options-append=proxy=https://proxy.example.com"},
+		},
+		{
+			proxy: "socks5://127.0.0.1:9050",
+			want:  []string{"--ytdl-raw-options-append=proxy=socks5://127.0.0.1:9050"},
+		},
+	}
+
+	for _, test := range tests {
+		if args := proxyArgs(test.proxy); !reflect.DeepEqual(args, test.want) {
+			t.Errorf("proxyArgs(%q) = %q, want %q", test.proxy, args, test.want)
+		}
+	}
+}
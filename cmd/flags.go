@@ -4,16 +4,21 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	flag "github.com/spf13/pflag"
 
 	"github.com/darkhz/invidtui/client"
+	mp "github.com/darkhz/invidtui/mediaplayer"
 	"github.com/darkhz/invidtui/utils"
 	"github.com/knadh/koanf/parsers/hjson"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
+	"github.com/mattn/go-runewidth"
 )
 
 // Option describes a command-line option.
@@ -53,6 +58,12 @@ var options = []Option{
 		Value:       "",
 		Type:        "path",
 	},
+	{
+		Name:        "screenshot-dir",
+		Description: "Specify directory to save video screenshots into (default is the screenshots directory within the config directory).",
+		Value:       "",
+		Type:        "other",
+	},
 	{
 		Name:        "search-video",
 		Description: "Search for a video.",
@@ -73,28 +84,388 @@ var options = []Option{
 	},
 	{
 		Name:        "play-audio",
-		Description: "Specify video/playlist URL to play audio from.",
+		Description: "Specify video/playlist URLs to play audio from (can be repeated, a comma-separated list, or a file with a URL on each line).",
 		Value:       "",
 		Type:        "play",
 	},
 	{
 		Name:        "play-video",
-		Description: "Specify video/playlist URL to play video from.",
+		Description: "Specify video/playlist URLs to play video from (can be repeated, a comma-separated list, or a file with a URL on each line).",
 		Value:       "",
 		Type:        "play",
 	},
+	{
+		Name:        "import-history",
+		Description: "Import the watch history from a YouTube Takeout watch-history.json or watch-history.html file.",
+		Value:       "",
+		Type:        "import",
+	},
+	{
+		Name:        "import-subscriptions",
+		Description: "Import the subscriptions from a YouTube Takeout subscriptions.csv file.",
+		Value:       "",
+		Type:        "import",
+	},
 	{
 		Name:        "video-res",
 		Description: "Set the default video resolution.",
 		Value:       "720p",
 		Type:        "other",
 	},
+	{
+		Name:        "audio-format",
+		Description: "Set the preferred audio format for audio playback (default, opus, aac, highest-bitrate).",
+		Value:       "default",
+		Type:        "other",
+	},
 	{
 		Name:        "num-retries",
 		Description: "Set the number of retries for connecting to the socket.",
 		Value:       "100",
 		Type:        "other",
 	},
+	{
+		Name:        "retry-delay",
+		Description: "Set the initial delay between attempts to connect to the socket, which doubles after each attempt.",
+		Value:       "250ms",
+		Type:        "other",
+	},
+	{
+		Name:        "reconnect-retries",
+		Description: "Set the number of attempts to relaunch the player if it exits abruptly.",
+		Value:       "3",
+		Type:        "other",
+	},
+	{
+		Name:        "reconnect-delay",
+		Description: "Set the initial delay between attempts to relaunch the player.",
+		Value:       "1s",
+		Type:        "other",
+	},
+	{
+		Name:        "autosave-interval",
+		Description: "Set the interval at which the settings, history and queue are saved while running (0 to disable).",
+		Value:       "5m",
+		Type:        "other",
+	},
+	{
+		Name:        "log-level",
+		Description: "Set the minimum level of messages written to the log file (debug, info, warn, error).",
+		Value:       "warn",
+		Type:        "other",
+	},
+	{
+		Name:        "update-interval",
+		Description: "Set the interval between player updates when no playback changes are observed.",
+		Value:       "5s",
+		Type:        "other",
+	},
+	{
+		Name:        "resume-mode",
+		Description: "Set whether to resume videos from their last position (auto, prompt, off).",
+		Value:       "prompt",
+		Type:        "other",
+	},
+	{
+		Name:        "load-concurrency",
+		Description: "Set the maximum number of videos or playlists that can be loaded in parallel.",
+		Value:       "10",
+		Type:        "other",
+	},
+	{
+		Name:        "rate-limit-retries",
+		Description: "Set the number of retries when the instance's rate-limit is exceeded.",
+		Value:       "3",
+		Type:        "other",
+	},
+	{
+		Name:        "rate-limit-delay",
+		Description: "Set the initial delay between retries when the instance's rate-limit is exceeded.",
+		Value:       "2s",
+		Type:        "other",
+	},
+	{
+		Name:        "progress-fill",
+		Description: "Set the character for the filled part of the progress bar.",
+		Value:       "█",
+		Type:        "other",
+	},
+	{
+		Name:        "progress-empty",
+		Description: "Set the character for the empty part of the progress bar.",
+		Value:       " ",
+		Type:        "other",
+	},
+	{
+		Name:        "progress-delimiters",
+		Description: "Set the two characters that enclose the progress bar.",
+		Value:       "||",
+		Type:        "other",
+	},
+	{
+		Name:        "progress-style",
+		Description: "Set the progress bar style (block, fine).",
+		Value:       "block",
+		Type:        "other",
+	},
+	{
+		Name:        "proxy",
+		Description: "Set the HTTP or SOCKS5 proxy for API requests, thumbnail downloads and the player (for example socks5://127.0.0.1:9050). The player can only stream via HTTPS and SOCKS5 proxies through youtube-dl/yt-dlp.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "user-agent",
+		Description: "Set the user agent for the player, which is also used for API requests and thumbnail downloads by default.",
+		Value:       client.UserAgent,
+		Type:        "other",
+	},
+	{
+		Name:        "api-user-agent",
+		Description: "Set the user agent for API requests, if it should differ from user-agent.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "thumbnail-user-agent",
+		Description: "Set the user agent for thumbnail downloads, if it should differ from user-agent.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "mpv-args",
+		Description: "Set space-separated extra arguments for mpv, which override the defaults and mpv-config, but not the idle, keep-open and input-ipc-server options.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "mpv-config",
+		Description: "Set an mpv configuration file to load in addition to the default one.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "external-player",
+		Description: "Set a command to open videos in an external player with, instead of mpv.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "external-player-stop",
+		Description: "Stop the playback after opening the playing video in an external player.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "cookies",
+		Description: "Set a cookies file in the Netscape format, to play videos which require signing in.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "hwdec",
+		Description: "Set the hardware video decoding mode of the player (for example no, auto-safe, auto).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "cache",
+		Description: "Set the cache mode of the player: auto, yes or no (the player defaults to auto).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "cache-secs",
+		Description: "Set the number of seconds to cache ahead, up to 3600 (30 by default, which is usually enough for slow connections).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "demuxer-max-bytes",
+		Description: "Set the maximum size of the cache, for example 150MiB (the player default).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "lyrics-provider",
+		Description: "Set the LRCLIB-compatible API endpoint to search lyrics from.",
+		Value:       "https://lrclib.net/api/search",
+		Type:        "other",
+	},
+	{
+		Name:        "status-file",
+		Description: "Set a file or named pipe to write the player status to, for use in status bars.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "status-format",
+		Description: "Set the format of the player status written to the status file (plain, json, waybar).",
+		Value:       "plain",
+		Type:        "other",
+	},
+	{
+		Name:        "loop-reshuffle",
+		Description: "Reshuffle the queue each time it is repeated, when it is repeated a set number of times.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "status-socket",
+		Description: "Answer status queries from scripts on a socket in the config directory.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "level-meter",
+		Description: "Show the audio level beside the progress bar.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "disable-images",
+		Description: "Do not show or download thumbnails in the information view.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "image-dithering",
+		Description: "Set the dithering mode for thumbnails in the information view (none, floyd-steinberg, ordered).",
+		Value:       "floyd-steinberg",
+		Type:        "other",
+	},
+	{
+		Name:        "sponsorblock-categories",
+		Description: "Set the comma-separated SponsorBlock segment categories to skip (sponsor, selfpromo, interaction, intro, outro, preview, music_offtopic, filler).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "scrobbler",
+		Description: "Set the service to scrobble played tracks to (lastfm, listenbrainz).",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "scrobble-token",
+		Description: "Set the ListenBrainz user token or the Last.fm session key to scrobble with.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "lastfm-api-key",
+		Description: "Set the Last.fm API key to scrobble with.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "lastfm-api-secret",
+		Description: "Set the Last.fm API secret to scrobble with.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "on-track-change",
+		Description: "Set a command to run when a track starts playing.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "skip-count",
+		Description: "Set the number of tracks to skip in the queue with the skip keys.",
+		Value:       "5",
+		Type:        "other",
+	},
+	{
+		Name:        "history-limit",
+		Description: "Set the maximum number of entries in the play history (0 for no limit).",
+		Value:       "500",
+		Type:        "other",
+	},
+	{
+		Name:        "notify",
+		Description: "Show a desktop notification when the playing track changes.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "enqueue",
+		Description: "Queue the URLs from the play-audio and play-video options in an already running instance.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "queue-autoclear",
+		Description: "Remove tracks from the queue once they finish playing.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "remember-positions",
+		Description: "Remember the positions of the queue tracks within the session, and resume a track from its position when switching back to it.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "search-mode",
+		Description: "Set the matching mode for the history and queue filters (exact, fuzzy).",
+		Value:       "exact",
+		Type:        "other",
+	},
+	{
+		Name:        "startup-action",
+		Description: "Set the action to perform on startup (empty: start with an empty queue, resume: resume the queue from the previous session, play-audio/play-video: play the startup-url).",
+		Value:       "empty",
+		Type:        "other",
+	},
+	{
+		Name:        "startup-url",
+		Description: "Set the video or playlist URL to play on startup, if startup-action is play-audio or play-video.",
+		Value:       "",
+		Type:        "other",
+	},
+	{
+		Name:        "startup-autoplay",
+		Description: "Start playing the resumed queue on startup. By default, the queue is restored paused at the last played track.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "stop-action",
+		Description: "Set the action of the player stop key (close: stop playback, clear the queue and hide the player, stop: stop playback and keep the queue).",
+		Value:       "close",
+		Type:        "other",
+	},
+	{
+		Name:        "lock-quality",
+		Description: "Lock the video quality, so that it is not lowered automatically when the video buffers frequently.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "downgrade-threshold",
+		Description: "Set the number of times a video can buffer within the downgrade window before it is reloaded at a lower quality (0 to disable).",
+		Value:       "3",
+		Type:        "other",
+	},
+	{
+		Name:        "downgrade-window",
+		Description: "Set the duration within which buffering events are counted for lowering the video quality.",
+		Value:       "1m",
+		Type:        "other",
+	},
+	{
+		Name:        "debug",
+		Description: "Enable the debug information overlay for the player.",
+		Value:       "",
+		Type:        "bool",
+	},
+	{
+		Name:        "instances",
+		Description: "Set a comma-separated list of instances to select the lowest-latency instance from.",
+		Value:       "",
+		Type:        "other",
+	},
 	{
 		Name:        "force-instance",
 		Description: "Force load media from specified invidious instance.",
@@ -168,20 +539,37 @@ func parse() {
 				"show-instances",
 				"play-audio",
 				"play-video",
+				"import-history",
+				"import-subscriptions",
 				"force-instance",
 				"close-instances",
 				"version",
 				"download-dir",
+				"screenshot-dir",
+				"notify",
+				"queue-autoclear",
+				"remember-positions",
+				"enqueue",
+				"startup-autoplay",
+				"lock-quality",
+				"disable-images",
+				"level-meter",
+				"status-socket",
+				"loop-reshuffle",
+				"external-player-stop",
+				"debug",
 			} {
 				if f.Name == name {
 					goto cmdOutPrint
 				}
 			}
 
-			if f.Name != "num-retries" {
-				s += fmt.Sprintf(" (default %q)", f.DefValue)
-			} else {
+			switch f.Name {
+			case "num-retries", "skip-count", "history-limit", "rate-limit-retries", "load-concurrency", "reconnect-retries", "downgrade-threshold":
 				s += fmt.Sprintf(" (default %v)", f.DefValue)
+
+			default:
+				s += fmt.Sprintf(" (default %q)", f.DefValue)
 			}
 
 		cmdOutPrint:
@@ -196,6 +584,9 @@ func parse() {
 		case "bool":
 			fs.Bool(option.Name, false, option.Description)
 
+		case "play":
+			fs.StringArray(option.Name, nil, option.Description)
+
 		default:
 			fs.String(option.Name, option.Value, option.Description)
 		}
@@ -231,6 +622,35 @@ func check() {
 	}
 }
 
+// checkPlayerArgs checks the arguments from the 'mpv-args' option.
+func checkPlayerArgs(args string) {
+	if err := validatePlayerArgs(args); err != nil {
+		printer.Error(err.Error())
+	}
+}
+
+// validatePlayerArgs validates the provided player arguments. Only options
+// are allowed, so that files cannot be passed to the player, and the essential
+// options required to control the player cannot be overridden.
+func validatePlayerArgs(args string) error {
+	for _, arg := range strings.Fields(args) {
+		if !strings.HasPrefix(arg, "--") {
+			return fmt.Errorf("Invalid value for mpv-args, %s is not an option", arg)
+		}
+
+		name := strings.SplitN(strings.TrimPrefix(arg, "--"), "=", 2)[0]
+		name = strings.TrimPrefix(name, "no-")
+
+		for _, essential := range mp.EssentialArgs {
+			if name == essential {
+				return fmt.Errorf("Invalid value for mpv-args, %s cannot be changed", arg)
+			}
+		}
+	}
+
+	return nil
+}
+
 // checkAuth parses and checks the 'token' and 'token-link' command-line parameters.
 // If token-link is set, it will print a link to generate an authentication token.
 func checkAuth() {
@@ -317,11 +737,224 @@ func checkOtherOptions(otherType, other string) {
 			printer.Error("Invalid instance URL")
 		}
 
+	case "instances":
+		for _, instance := range Instances() {
+			if _, err := utils.IsValidURL(instance); err != nil {
+				printer.Error("Invalid instance URL " + instance)
+			}
+		}
+
 	case "num-retries":
 		if _, err := strconv.Atoi(other); err != nil {
 			printer.Error("Invalid value for num-retries")
 		}
 
+	case "retry-delay":
+		if delay, err := time.ParseDuration(other); err != nil || delay <= 0 {
+			printer.Error("Invalid value for retry-delay")
+		}
+
+	case "reconnect-retries":
+		if retries, err := strconv.Atoi(other); err != nil || retries < 0 {
+			printer.Error("Invalid value for reconnect-retries")
+		}
+
+	case "reconnect-delay":
+		if delay, err := time.ParseDuration(other); err != nil || delay <= 0 {
+			printer.Error("Invalid value for reconnect-delay")
+		}
+
+	case "autosave-interval":
+		if interval, err := time.ParseDuration(other); err != nil || interval < 0 {
+			printer.Error("Invalid value for autosave-interval")
+		}
+
+	case "log-level":
+		if !utils.IsLogLevel(other) {
+			printer.Error("Invalid value for log-level")
+		}
+
+	case "update-interval":
+		if interval, err := time.ParseDuration(other); err != nil || interval <= 0 {
+			printer.Error("Invalid value for update-interval")
+		}
+
+	case "rate-limit-retries":
+		if retries, err := strconv.Atoi(other); err != nil || retries < 0 {
+			printer.Error("Invalid value for rate-limit-retries")
+		}
+
+	case "rate-limit-delay":
+		if delay, err := time.ParseDuration(other); err != nil || delay <= 0 {
+			printer.Error("Invalid value for rate-limit-delay")
+		}
+
+	case "progress-fill", "progress-empty":
+		if !isSingleWidth(other, 1) {
+			printer.Error("Invalid value for " + otherType + ", must be a single-width character")
+		}
+
+	case "progress-delimiters":
+		if !isSingleWidth(other, 2) {
+			printer.Error("Invalid value for progress-delimiters, must be two single-width characters")
+		}
+
+	case "progress-style":
+		if other != "block" && other != "fine" {
+			printer.Error("Invalid value for progress-style")
+		}
+
+	case "resume-mode":
+		if other != "auto" && other != "prompt" && other != "off" {
+			printer.Error("Invalid value for resume-mode")
+		}
+
+	case "audio-format":
+		if other != "default" && other != "opus" && other != "aac" && other != "highest-bitrate" {
+			printer.Error("Invalid value for audio-format")
+		}
+
+	case "screenshot-dir":
+		if dir, err := os.Stat(other); err == nil && !dir.IsDir() {
+			printer.Error("Invalid value for screenshot-dir, " + other + " is not a directory")
+		}
+
+	case "proxy":
+		if _, err := client.ParseProxy(other); err != nil {
+			printer.Error("Invalid value for proxy")
+		}
+
+	case "mpv-args":
+		checkPlayerArgs(other)
+
+	case "mpv-config":
+		if file, err := os.Stat(other); err != nil || file.IsDir() {
+			printer.Error("Invalid value for mpv-config, cannot access " + other)
+		}
+
+	case "cookies":
+		file, err := os.Open(other)
+		if err == nil {
+			var info os.FileInfo
+
+			info, err = file.Stat()
+			if err == nil && info.IsDir() {
+				err = fmt.Errorf("%s is a directory", other)
+			}
+
+			file.Close()
+		}
+		if err != nil {
+			printer.Error("Invalid value for cookies, cannot read " + other)
+		}
+
+	case "lyrics-provider":
+		if _, err := utils.IsValidURL(other); err != nil {
+			printer.Error("Invalid value for lyrics-provider")
+		}
+
+	case "cache":
+		if other != "auto" && other != "yes" && other != "no" {
+			printer.Error("Invalid value for cache")
+		}
+
+	case "cache-secs":
+		if secs, err := strconv.Atoi(other); err != nil || secs < 1 || secs > mp.MaxCacheSecs {
+			printer.Error("Invalid value for cache-secs")
+		}
+
+	case "demuxer-max-bytes":
+		size := other
+		for _, suffix := range []string{"KiB", "MiB", "GiB"} {
+			size = strings.TrimSuffix(size, suffix)
+		}
+
+		if bytes, err := strconv.ParseUint(size, 10, 64); err != nil || bytes == 0 {
+			printer.Error("Invalid value for demuxer-max-bytes")
+		}
+
+	case "status-file":
+		if dir, err := os.Stat(filepath.Dir(other)); err != nil || !dir.IsDir() {
+			printer.Error("Invalid value for status-file, cannot access " + filepath.Dir(other))
+		}
+
+	case "status-format":
+		if other != "plain" && other != "json" && other != "waybar" {
+			printer.Error("Invalid value for status-format")
+		}
+
+	case "image-dithering":
+		if other != "none" && other != "floyd-steinberg" && other != "ordered" {
+			printer.Error("Invalid value for image-dithering")
+		}
+
+	case "scrobbler":
+		if other != "lastfm" && other != "listenbrainz" {
+			printer.Error("Invalid value for scrobbler")
+		}
+
+		if GetOptionValue("scrobble-token") == "" {
+			printer.Error("A scrobble-token is required to scrobble to " + other)
+		}
+
+		if other == "lastfm" && (GetOptionValue("lastfm-api-key") == "" || GetOptionValue("lastfm-api-secret") == "") {
+			printer.Error("A lastfm-api-key and lastfm-api-secret are required to scrobble to lastfm")
+		}
+
+	case "sponsorblock-categories":
+		if other == "" {
+			break
+		}
+
+		for _, category := range strings.Split(other, ",") {
+			if !isSponsorBlockCategory(strings.TrimSpace(category)) {
+				printer.Error("Invalid SponsorBlock category " + category)
+			}
+		}
+
+	case "search-mode":
+		if other != "exact" && other != "fuzzy" {
+			printer.Error("Invalid value for search-mode")
+		}
+
+	case "startup-action":
+		switch other {
+		case "empty", "resume":
+
+		case "play-audio", "play-video":
+			if _, _, err := utils.GetVPIDFromURL(GetOptionValue("startup-url")); err != nil {
+				printer.Error("Invalid value for startup-url")
+			}
+
+		default:
+			printer.Error("Invalid value for startup-action")
+		}
+
+	case "stop-action":
+		if other != "close" && other != "stop" {
+			printer.Error("Invalid value for stop-action")
+		}
+
+	case "downgrade-threshold":
+		if threshold, err := strconv.Atoi(other); err != nil || threshold < 0 {
+			printer.Error("Invalid value for downgrade-threshold")
+		}
+
+	case "downgrade-window":
+		if window, err := time.ParseDuration(other); err != nil || window <= 0 {
+			printer.Error("Invalid value for downgrade-window")
+		}
+
+	case "skip-count":
+		if count, err := strconv.Atoi(other); err != nil || count < 1 {
+			printer.Error("Invalid value for skip-count")
+		}
+
+	case "history-limit":
+		if limit, err := strconv.Atoi(other); err != nil || limit < 0 {
+			printer.Error("Invalid value for history-limit")
+		}
+
 	case "video-res":
 		for _, res := range []string{
 			"144p",
@@ -343,3 +976,40 @@ func checkOtherOptions(otherType, other string) {
 		printer.Error("Invalid video resolution")
 	}
 }
+
+// isSponsorBlockCategory returns whether the provided
+// category is a valid SponsorBlock segment category.
+func isSponsorBlockCategory(category string) bool {
+	for _, c := range []string{
+		"sponsor",
+		"selfpromo",
+		"interaction",
+		"intro",
+		"outro",
+		"preview",
+		"music_offtopic",
+		"filler",
+	} {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSingleWidth returns whether the provided text has the
+// provided number of characters, all of which are single-width.
+func isSingleWidth(text string, count int) bool {
+	if utf8.RuneCountInString(text) != count {
+		return false
+	}
+
+	for _, r := range text {
+		if runewidth.RuneWidth(r) != 1 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestValidatePlayerArgs(t *testing.T) {
+	tests := []struct {
+		args  string
+		valid bool
+	}{
+		{args: "", valid: true},
+		{args: "  ", valid: true},
+		{args: "--volume=50", valid: true},
+		{args: "--volume=50   --no-border --profile=fast", valid: true},
+		{args: "--idle-timeout=5", valid: true},
+		{args: "file.mp4", valid: false},
+		{args: "--volume=50 https://example.com/video", valid: false},
+		{args: "-v", valid: false},
+		{args: "--idle", valid: false},
+		{args: "--idle=no", valid: false},
+		{args: "--no-keep-open", valid: false},
+		{args: "--keep-open=no", valid: false},
+		{args: "--volume=50 --input-ipc-server=/tmp/other", valid: false},
+	}
+
+	for _, test := range tests {
+		if err := validatePlayerArgs(test.args); (err == nil) != test.valid {
+			t.Errorf("validatePlayerArgs(%q) = %v, want valid %v", test.args, err, test.valid)
+		}
+	}
+}
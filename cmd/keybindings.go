@@ -32,6 +32,7 @@ const (
 	KeyCancel                  Key = "Cancel"
 	KeySuspend                 Key = "Suspend"
 	KeyInstancesList           Key = "InstancesList"
+	KeyInstancesProbe          Key = "InstancesProbe"
 	KeyQuit                    Key = "Quit"
 	KeySearchStart             Key = "SearchStart"
 	KeySearchSuggestions       Key = "SearchSuggestions"
@@ -62,27 +63,96 @@ const (
 	KeyQueueAppend             Key = "QueueAppend"
 	KeyQueueDelete             Key = "QueueDelete"
 	KeyQueueMove               Key = "QueueMove"
+	KeyQueueMoveUp             Key = "QueueMoveUp"
+	KeyQueueMoveDown           Key = "QueueMoveDown"
+	KeyQueueSearch             Key = "QueueSearch"
+	KeyQueueRetryFailed        Key = "QueueRetryFailed"
+	KeyQueueToggleMediaType    Key = "QueueToggleMediaType"
+	KeyQueueClearOthers        Key = "QueueClearOthers"
+	KeyQueueRemoveDuplicates   Key = "QueueRemoveDuplicates"
+	KeyQueueRemoveAbove        Key = "QueueRemoveAbove"
+	KeyQueueRemoveBelow        Key = "QueueRemoveBelow"
+	KeyHistoryPlay             Key = "HistoryPlay"
+	KeyHistoryFilterMedia      Key = "HistoryFilterMedia"
+	KeyHistoryFilterDate       Key = "HistoryFilterDate"
+	KeyHistoryRemoveEntry      Key = "HistoryRemoveEntry"
+	KeyHistoryClear            Key = "HistoryClear"
+	KeyHistoryExport           Key = "HistoryExport"
+	KeyFavoritesPlay           Key = "FavoritesPlay"
+	KeyFavoritesRemove         Key = "FavoritesRemove"
+	KeyFavoritesExport         Key = "FavoritesExport"
 	KeyPlayerOpenPlaylist      Key = "PlayerOpenPlaylist"
 	KeyPlayerHistory           Key = "PlayerHistory"
+	KeyPlayerReplayLast        Key = "PlayerReplayLast"
+	KeyPlayerFavorites         Key = "PlayerFavorites"
+	KeyPlayerToggleFavorite    Key = "PlayerToggleFavorite"
+	KeyPlayerToggleVisible     Key = "PlayerToggleVisible"
+	KeyPlayerToggleQueue       Key = "PlayerToggleQueue"
+	KeyPlayerAudioDevices      Key = "PlayerAudioDevices"
+	KeyPlayerReload            Key = "PlayerReload"
 	KeyPlayerQueueAudio        Key = "PlayerQueueAudio"
 	KeyPlayerQueueVideo        Key = "PlayerQueueVideo"
+	KeyPlayerQueueNextAudio    Key = "PlayerQueueNextAudio"
+	KeyPlayerQueueNextVideo    Key = "PlayerQueueNextVideo"
+	KeyPlayerAttachAudio       Key = "PlayerAttachAudio"
+	KeyPlayerQueueTrimmed      Key = "PlayerQueueTrimmed"
 	KeyPlayerPlayAudio         Key = "PlayerPlayAudio"
 	KeyPlayerPlayVideo         Key = "PlayerPlayVideo"
 	KeyPlayerInfo              Key = "PlayerInfo"
+	KeyPlayerNowPlaying        Key = "PlayerNowPlaying"
+	KeyPlayerLyrics            Key = "PlayerLyrics"
 	KeyPlayerInfoChangeQuality Key = "PlayerInfoChangeQuality"
+	KeyPlayerLayout            Key = "PlayerLayout"
+	KeyPlayerCopyURL           Key = "PlayerCopyURL"
+	KeyPlayerCopyURLTimestamp  Key = "PlayerCopyURLTimestamp"
+	KeyPlayerOpenExternal      Key = "PlayerOpenExternal"
 	KeyPlayerSeekForward       Key = "PlayerSeekForward"
 	KeyPlayerSeekBackward      Key = "PlayerSeekBackward"
 	KeyPlayerStop              Key = "PlayerStop"
+	KeyPlayerClose             Key = "PlayerClose"
 	KeyPlayerToggleLoop        Key = "PlayerToggleLoop"
 	KeyPlayerToggleShuffle     Key = "PlayerToggleShuffle"
+	KeyPlayerReshuffle         Key = "PlayerReshuffle"
+	KeyPlayerShuffleSeed       Key = "PlayerShuffleSeed"
+	KeyPlayerLoopCount         Key = "PlayerLoopCount"
+	KeyPlayerUnshuffle         Key = "PlayerUnshuffle"
 	KeyPlayerToggleMute        Key = "PlayerToggleMute"
+	KeyPlayerToggleVolumeMute  Key = "PlayerToggleVolumeMute"
 	KeyPlayerTogglePlay        Key = "PlayerTogglePlay"
 	KeyPlayerPrev              Key = "PlayerPrev"
 	KeyPlayerNext              Key = "PlayerNext"
+	KeyPlayerSkipBackward      Key = "PlayerSkipBackward"
+	KeyPlayerSkipForward       Key = "PlayerSkipForward"
 	KeyPlayerVolumeIncrease    Key = "PlayerVolumeIncrease"
 	KeyPlayerVolumeDecrease    Key = "PlayerVolumeDecrease"
+	KeyPlayerVolumeStepUp      Key = "PlayerVolumeStepUp"
+	KeyPlayerVolumeStepDown    Key = "PlayerVolumeStepDown"
+	KeyPlayerVolumeSet         Key = "PlayerVolumeSet"
+	KeyPlayerCommand           Key = "PlayerCommand"
+	KeyPlayerAddToPlaylist     Key = "PlayerAddToPlaylist"
+	KeyPlayerToggleAutoplay    Key = "PlayerToggleAutoplay"
+	KeyPlayerToggleHWDec       Key = "PlayerToggleHWDec"
+	KeyPlayerCycleAspect       Key = "PlayerCycleAspect"
+	KeyPlayerZoomIn            Key = "PlayerZoomIn"
+	KeyPlayerZoomOut           Key = "PlayerZoomOut"
+	KeyPlayerIncreaseCache     Key = "PlayerIncreaseCache"
+	KeyPlayerLockQuality       Key = "PlayerLockQuality"
+	KeyPlayerScreenshot        Key = "PlayerScreenshot"
+	KeyPlayerRunTrackHook      Key = "PlayerRunTrackHook"
+	KeyPlayerDebugInfo         Key = "PlayerDebugInfo"
+	KeyPlayerQueueAllAudio     Key = "PlayerQueueAllAudio"
+	KeyPlayerQueueAllVideo     Key = "PlayerQueueAllVideo"
 	KeyPlayerInfoScrollUp      Key = "PlayerInfoScrollUp"
 	KeyPlayerInfoScrollDown    Key = "PlayerInfoScrollDown"
+	KeyPlayerInfoPageUp        Key = "PlayerInfoPageUp"
+	KeyPlayerInfoPageDown      Key = "PlayerInfoPageDown"
+	KeyPlayerInfoTop           Key = "PlayerInfoTop"
+	KeyPlayerInfoBottom        Key = "PlayerInfoBottom"
+	KeyPlayerInfoNextLink      Key = "PlayerInfoNextLink"
+	KeyPlayerInfoPrevLink      Key = "PlayerInfoPrevLink"
+	KeyPlayerInfoOpenLink      Key = "PlayerInfoOpenLink"
+	KeyPlayerInfoCopyTitle     Key = "PlayerInfoCopyTitle"
+	KeyPlayerInfoOpenChannel   Key = "PlayerInfoOpenChannel"
 	KeyComments                Key = "Comments"
 	KeyCommentReplies          Key = "CommentReplies"
 	KeySwitchTab               Key = "SwitchTab"
@@ -118,6 +188,7 @@ const (
 	KeyContextPlaylist  KeyContext = "Playlist"
 	KeyContextChannel   KeyContext = "Channel"
 	KeyContextHistory   KeyContext = "History"
+	KeyContextFavorites KeyContext = "Favorites"
 )
 
 var (
@@ -147,6 +218,12 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, 'o', tcell.ModNone},
 			Global:  true,
 		},
+		KeyInstancesProbe: {
+			Title:   "Probe Instances",
+			Context: KeyContextApp,
+			Kb:      Keybinding{tcell.KeyRune, 'O', tcell.ModNone},
+			Global:  true,
+		},
 		KeyQuit: {
 			Title:   "Quit",
 			Context: KeyContextApp,
@@ -300,6 +377,96 @@ var (
 			Context: KeyContextQueue,
 			Kb:      Keybinding{tcell.KeyRune, 'M', tcell.ModNone},
 		},
+		KeyQueueMoveUp: {
+			Title:   "Move Up",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyUp, ' ', tcell.ModShift},
+		},
+		KeyQueueMoveDown: {
+			Title:   "Move Down",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyDown, ' ', tcell.ModShift},
+		},
+		KeyQueueSearch: {
+			Title:   "Search",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, '/', tcell.ModNone},
+		},
+		KeyQueueRetryFailed: {
+			Title:   "Retry Failed",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, 'R', tcell.ModNone},
+		},
+		KeyQueueToggleMediaType: {
+			Title:   "Switch Audio/Video",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, 't', tcell.ModNone},
+		},
+		KeyQueueClearOthers: {
+			Title:   "Clear All But Current",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, 'X', tcell.ModNone},
+		},
+		KeyQueueRemoveDuplicates: {
+			Title:   "Remove Duplicates",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, 'D', tcell.ModNone},
+		},
+		KeyQueueRemoveAbove: {
+			Title:   "Remove Above",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, 'K', tcell.ModNone},
+		},
+		KeyQueueRemoveBelow: {
+			Title:   "Remove Below",
+			Context: KeyContextQueue,
+			Kb:      Keybinding{tcell.KeyRune, 'J', tcell.ModNone},
+		},
+		KeyHistoryPlay: {
+			Title:   "Play",
+			Context: KeyContextHistory,
+			Kb:      Keybinding{tcell.KeyEnter, ' ', tcell.ModNone},
+		},
+		KeyHistoryFilterMedia: {
+			Title:   "Filter by type",
+			Context: KeyContextHistory,
+			Kb:      Keybinding{tcell.KeyRune, 't', tcell.ModNone},
+		},
+		KeyHistoryFilterDate: {
+			Title:   "Filter by date",
+			Context: KeyContextHistory,
+			Kb:      Keybinding{tcell.KeyRune, 'T', tcell.ModNone},
+		},
+		KeyHistoryRemoveEntry: {
+			Title:   "Remove Entry",
+			Context: KeyContextHistory,
+			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModNone},
+		},
+		KeyHistoryClear: {
+			Title:   "Clear History",
+			Context: KeyContextHistory,
+			Kb:      Keybinding{tcell.KeyRune, 'X', tcell.ModNone},
+		},
+		KeyHistoryExport: {
+			Title:   "Export History",
+			Context: KeyContextHistory,
+			Kb:      Keybinding{tcell.KeyRune, 'E', tcell.ModNone},
+		},
+		KeyFavoritesPlay: {
+			Title:   "Play",
+			Context: KeyContextFavorites,
+			Kb:      Keybinding{tcell.KeyEnter, ' ', tcell.ModNone},
+		},
+		KeyFavoritesRemove: {
+			Title:   "Remove Favorite",
+			Context: KeyContextFavorites,
+			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModNone},
+		},
+		KeyFavoritesExport: {
+			Title:   "Export Favorites",
+			Context: KeyContextFavorites,
+			Kb:      Keybinding{tcell.KeyRune, 'E', tcell.ModNone},
+		},
 		KeyPlayerOpenPlaylist: {
 			Title:   "Open Playlist",
 			Context: KeyContextPlayer,
@@ -312,6 +479,48 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, 'h', tcell.ModAlt},
 			Global:  true,
 		},
+		KeyPlayerReplayLast: {
+			Title:   "Play Last Entry Again",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'r', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerFavorites: {
+			Title:   "Show Favorites",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'F', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerToggleFavorite: {
+			Title:   "Toggle Favorite",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'f', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerToggleVisible: {
+			Title:   "Toggle Player Visibility",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'b', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerToggleQueue: {
+			Title:   "Toggle Queue",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'q', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerAudioDevices: {
+			Title:   "Select Audio Device",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'D', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerReload: {
+			Title:   "Reload Current Track",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'R', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerQueueAudio: {
 			Title:   "Queue Audio",
 			Context: KeyContextPlayer,
@@ -324,6 +533,42 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, 'v', tcell.ModNone},
 			Global:  true,
 		},
+		KeyPlayerQueueNextAudio: {
+			Title:   "Queue Audio Next",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModNone},
+			Global:  true,
+		},
+		KeyPlayerQueueNextVideo: {
+			Title:   "Queue Video Next",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'N', tcell.ModNone},
+			Global:  true,
+		},
+		KeyPlayerQueueAllAudio: {
+			Title:   "Queue All Audio",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'A', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerQueueAllVideo: {
+			Title:   "Queue All Video",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'V', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerAttachAudio: {
+			Title:   "Queue Video With Audio File",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'v', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerQueueTrimmed: {
+			Title:   "Queue Trimmed",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'T', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerPlayAudio: {
 			Title:   "Play Audio",
 			Context: KeyContextPlayer,
@@ -342,12 +587,48 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, ' ', tcell.ModAlt},
 			Global:  true,
 		},
+		KeyPlayerNowPlaying: {
+			Title:   "Now Playing View",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'n', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerLyrics: {
+			Title:   "Toggle Lyrics",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'k', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerInfoChangeQuality: {
 			Title:   "Change Image Quality",
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, ':', tcell.ModAlt},
 			Global:  true,
 		},
+		KeyPlayerLayout: {
+			Title:   "Change Layout",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'l', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerCopyURL: {
+			Title:   "Copy Playing Link",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'c', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerCopyURLTimestamp: {
+			Title:   "Copy Playing Link At Time",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'C', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerOpenExternal: {
+			Title:   "Open In External Player",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'O', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerSeekForward: {
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRight, ' ', tcell.ModCtrl},
@@ -363,6 +644,11 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, 'S', tcell.ModNone},
 			Global:  true,
 		},
+		KeyPlayerClose: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'x', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerToggleLoop: {
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, 'l', tcell.ModNone},
@@ -373,11 +659,75 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, 's', tcell.ModNone},
 			Global:  true,
 		},
+		KeyPlayerToggleAutoplay: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'a', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerToggleHWDec: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'H', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerCycleAspect: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'w', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerZoomIn: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'z', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerZoomOut: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'Z', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerIncreaseCache: {
+			Title:   "Increase Cache",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'I', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerLockQuality: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'L', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerReshuffle: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 's', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerShuffleSeed: {
+			Title:   "Shuffle With Seed",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'S', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerLoopCount: {
+			Title:   "Repeat Queue N Times",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'N', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerUnshuffle: {
+			Title:   "Restore Queue Order",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'u', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerToggleMute: {
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, 'm', tcell.ModNone},
 			Global:  true,
 		},
+		KeyPlayerToggleVolumeMute: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'M', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerTogglePlay: {
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, ' ', tcell.ModNone},
@@ -393,6 +743,16 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, '>', tcell.ModNone},
 			Global:  true,
 		},
+		KeyPlayerSkipBackward: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, '<', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerSkipForward: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, '>', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerVolumeIncrease: {
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyRune, '=', tcell.ModNone},
@@ -403,6 +763,52 @@ var (
 			Kb:      Keybinding{tcell.KeyRune, '-', tcell.ModNone},
 			Global:  true,
 		},
+		KeyPlayerVolumeStepUp: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, '=', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerVolumeStepDown: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, '-', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerVolumeSet: {
+			Title:   "Set Volume",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, '0', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerCommand: {
+			Title:   "Run Command",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, ':', tcell.ModNone},
+			Global:  true,
+		},
+		KeyPlayerAddToPlaylist: {
+			Title:   "Add To Playlist",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'p', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerScreenshot: {
+			Title:   "Take Screenshot",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'P', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerRunTrackHook: {
+			Title:   "Run Track Change Command",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 't', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerDebugInfo: {
+			Title:   "Debug Information",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'd', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyPlayerInfoScrollUp: {
 			Context: KeyContextPlayer,
 			Kb:      Keybinding{tcell.KeyUp, ' ', tcell.ModCtrl | tcell.ModAlt},
@@ -413,6 +819,54 @@ var (
 			Kb:      Keybinding{tcell.KeyDown, ' ', tcell.ModCtrl | tcell.ModAlt},
 			Global:  true,
 		},
+		KeyPlayerInfoPageUp: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyPgUp, ' ', tcell.ModCtrl | tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoPageDown: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyPgDn, ' ', tcell.ModCtrl | tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoTop: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyHome, ' ', tcell.ModCtrl | tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoBottom: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyEnd, ' ', tcell.ModCtrl | tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoNextLink: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRight, ' ', tcell.ModCtrl | tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoPrevLink: {
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyLeft, ' ', tcell.ModCtrl | tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoOpenLink: {
+			Title:   "Open Selected Info Link",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'o', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoCopyTitle: {
+			Title:   "Copy Title And Author",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'y', tcell.ModAlt},
+			Global:  true,
+		},
+		KeyPlayerInfoOpenChannel: {
+			Title:   "Open Channel",
+			Context: KeyContextPlayer,
+			Kb:      Keybinding{tcell.KeyRune, 'g', tcell.ModAlt},
+			Global:  true,
+		},
 		KeyAudioURL: {
 			Title:   "Play audio from URL",
 			Context: KeyContextPlayer,
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/utils"
@@ -17,8 +20,31 @@ type SettingsData struct {
 
 	SearchHistory []string              `json:"searchHistory"`
 	PlayHistory   []PlayHistorySettings `json:"playHistory"`
+	Favorites     []PlayHistorySettings `json:"favorites"`
 
 	PlayerStates []string `json:"playerStates"`
+	PlayerLayout string   `json:"playerLayout"`
+	LastInstance string   `json:"lastInstance"`
+
+	PlaybackPositions map[string]PlaybackPositionSettings `json:"playbackPositions"`
+	QueuePosition     int                                 `json:"queuePosition"`
+
+	PendingScrobbles []ScrobbleSettings `json:"pendingScrobbles"`
+}
+
+// ScrobbleSettings describes the format to store scrobbles which could not be submitted.
+type ScrobbleSettings struct {
+	Title     string `json:"title"`
+	Artist    string `json:"artist"`
+	VideoID   string `json:"videoId"`
+	Duration  int64  `json:"duration"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// PlaybackPositionSettings describes the format to store the playback position of a video.
+type PlaybackPositionSettings struct {
+	Position  int64 `json:"position"`
+	Timestamp int64 `json:"timestamp"`
 }
 
 // PlayHistorySettings describes the format to store the play history.
@@ -29,37 +55,133 @@ type PlayHistorySettings struct {
 	VideoID    string `json:"videoId"`
 	PlaylistID string `json:"playlistId"`
 	AuthorID   string `json:"authorId"`
+	MediaType  string `json:"mediaType"`
+	Timestamp  int64  `json:"timestamp"`
 }
 
 // Settings stores the application settings.
 var Settings SettingsData
 
+// settingsBackup stores the path to the backup of a settings file which could not be parsed.
+var settingsBackup string
+
 // SaveSettings saves the application settings.
 func SaveSettings() {
+	if err := StoreSettings(); err != nil {
+		printer.Error(err.Error())
+	}
+}
+
+// StoreSettings saves the settings like SaveSettings, but returns an error instead
+// of exiting if the settings cannot be saved, so that it can be used while the
+// application is running.
+func StoreSettings() error {
 	Settings.Credentials = client.GetAuthCredentials()
+	if Instances() != nil {
+		Settings.LastInstance = client.Instance()
+	}
 
 	Settings.SearchHistory = utils.Deduplicate(Settings.SearchHistory)
+	Settings.PlayHistory = DeduplicatePlayHistory(Settings.PlayHistory)
+	Settings.Favorites = DeduplicateFavorites(Settings.Favorites)
 
 	data, err := utils.JSON().MarshalIndent(Settings, "", " ")
 	if err != nil {
-		printer.Error(fmt.Sprintf("Settings: Cannot encode data: %s", err))
+		return fmt.Errorf("Settings: Cannot encode data: %s", err)
 	}
 
 	file, err := GetPath("settings.json")
 	if err != nil {
-		printer.Error("Settings: Cannot get store path")
+		return fmt.Errorf("Settings: Cannot get store path")
 	}
 
-	fd, err := os.OpenFile(file, os.O_WRONLY|os.O_TRUNC|os.O_SYNC, os.ModePerm)
-	if err != nil {
-		printer.Error(fmt.Sprintf("Settings: Cannot open file: %s", err))
+	if err := utils.WriteFileAtomic(file, data, 0600); err != nil {
+		return fmt.Errorf("Settings: Cannot save data: %s", err)
 	}
-	defer fd.Close()
 
-	_, err = fd.Write(data)
-	if err != nil {
-		printer.Error(fmt.Sprintf("Settings: Cannot save data: %s", err))
+	return nil
+}
+
+// DeduplicatePlayHistory removes duplicate entries from the play history,
+// keeping the most recent ones, and limits the number of entries according
+// to the 'history-limit' option. Entries are considered to be duplicates if
+// they have the same type and video or playlist ID.
+func DeduplicatePlayHistory(entries []PlayHistorySettings) []PlayHistorySettings {
+	limit, _ := strconv.Atoi(GetOptionValue("history-limit"))
+
+	return deduplicateEntries(entries, limit)
+}
+
+// DeduplicateFavorites removes duplicate entries from the favorites, keeping the
+// most recent ones. Unlike the play history, the favorites are not limited.
+func DeduplicateFavorites(entries []PlayHistorySettings) []PlayHistorySettings {
+	return deduplicateEntries(entries, 0)
+}
+
+// deduplicateEntries removes duplicate entries, keeping the first ones, and
+// limits the number of entries to the provided limit, if it is positive.
+func deduplicateEntries(entries []PlayHistorySettings, limit int) []PlayHistorySettings {
+	encountered := make(map[string]struct{}, len(entries))
+	dedup := make([]PlayHistorySettings, 0, len(entries))
+
+	for _, entry := range entries {
+		if limit > 0 && len(dedup) >= limit {
+			break
+		}
+
+		key := PlayHistoryKey(entry)
+		if _, ok := encountered[key]; ok {
+			continue
+		}
+
+		encountered[key] = struct{}{}
+		dedup = append(dedup, entry)
 	}
+
+	return dedup
+}
+
+// LimitPlaybackPositions removes the oldest stored playback positions, so that
+// the number of positions is limited according to the 'history-limit' option.
+func LimitPlaybackPositions(positions map[string]PlaybackPositionSettings) {
+	limit, _ := strconv.Atoi(GetOptionValue("history-limit"))
+
+	limitPlaybackPositions(positions, limit)
+}
+
+// limitPlaybackPositions removes the oldest positions, until the number of
+// positions is within the provided limit, if it is positive.
+func limitPlaybackPositions(positions map[string]PlaybackPositionSettings, limit int) {
+	if limit <= 0 || len(positions) <= limit {
+		return
+	}
+
+	ids := make([]string, 0, len(positions))
+	for id := range positions {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if ti, tj := positions[ids[i]].Timestamp, positions[ids[j]].Timestamp; ti != tj {
+			return ti > tj
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	for _, id := range ids[limit:] {
+		delete(positions, id)
+	}
+}
+
+// PlayHistoryKey returns the key which identifies the play history entry.
+func PlayHistoryKey(entry PlayHistorySettings) string {
+	key := entry.Type + ":" + entry.VideoID + entry.PlaylistID
+	if entry.VideoID == "" && entry.PlaylistID == "" {
+		key += entry.AuthorID + entry.Title
+	}
+
+	return key
 }
 
 // getSettings retrives the settings from the settings file.
@@ -71,18 +193,58 @@ func getSettings() {
 		printer.Error("Settings: Cannot create/get store path")
 	}
 
+	parsed, err := decodeSettings(file, &Settings)
+	if err != nil {
+		printer.Error(err.Error())
+	}
+	if !parsed {
+		backupSettings(file)
+	}
+
+	client.SetAuthCredentials(Settings.Credentials)
+}
+
+// decodeSettings decodes the settings file at the provided path into the provided
+// settings, and returns whether the file could be parsed. The settings are only
+// changed if the file was parsed.
+func decodeSettings(file string, settings *SettingsData) (bool, error) {
 	fd, err := os.OpenFile(file, os.O_RDONLY, os.ModePerm)
 	if err != nil {
-		printer.Error("Settings: Cannot open file")
+		return false, fmt.Errorf("Settings: Cannot open file")
 	}
 	defer fd.Close()
 
-	err = utils.JSON().NewDecoder(fd).Decode(&Settings)
+	decoded := *settings
+
+	err = utils.JSON().NewDecoder(fd).Decode(&decoded)
 	if err != nil && err != io.EOF {
-		printer.Error("Settings: Cannot parse values")
+		return false, nil
 	}
 
-	client.SetAuthCredentials(Settings.Credentials)
+	*settings = decoded
+
+	return true, nil
+}
+
+// backupSettings moves a settings file which cannot be parsed, for example
+// if it was partially written during a crash, to a backup file. The settings
+// are then started afresh instead of stopping the application.
+func backupSettings(file string) {
+	backup, err := backupFile(file, time.Now())
+	if err != nil {
+		printer.Error(fmt.Sprintf("Settings: Cannot parse values, and cannot back up %s", file))
+	}
+
+	settingsBackup = backup
+	printer.Print("Settings: Cannot parse values, backed up to " + backup)
+}
+
+// backupFile moves the provided file to a backup file named after the
+// provided time, and returns the path to the backup file.
+func backupFile(file string, now time.Time) (string, error) {
+	backup := file + ".corrupt-" + strconv.FormatInt(now.Unix(), 10)
+
+	return backup, os.Rename(file, backup)
 }
 
 // getOldSettings retreives the settings stored in various files
@@ -147,7 +309,7 @@ func getOldSettings() {
 		fd.Close()
 
 		if err != nil && err != io.EOF {
-			printer.Error(fmt.Sprintf("Settings: Could not parse %s", files.File))
+			backupSettings(file)
 		}
 	}
 }
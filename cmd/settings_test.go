@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDeduplicateEntries(t *testing.T) {
+	video := func(id string, timestamp int64) PlayHistorySettings {
+		return PlayHistorySettings{Type: "video", VideoID: id, Timestamp: timestamp}
+	}
+	playlist := func(id string) PlayHistorySettings {
+		return PlayHistorySettings{Type: "playlist", PlaylistID: id}
+	}
+
+	tests := []struct {
+		name    string
+		entries []PlayHistorySettings
+		limit   int
+		want    []PlayHistorySettings
+	}{
+		{
+			name:    "empty",
+			entries: nil,
+			want:    []PlayHistorySettings{},
+		},
+		{
+			name:    "no duplicates",
+			entries: []PlayHistorySettings{video("a", 3), video("b", 2), playlist("c")},
+			want:    []PlayHistorySettings{video("a", 3), video("b", 2), playlist("c")},
+		},
+		{
+			name:    "keeps the first duplicate",
+			entries: []PlayHistorySettings{video("a", 3), video("b", 2), video("a", 1)},
+			want:    []PlayHistorySettings{video("a", 3), video("b", 2)},
+		},
+		{
+			name: "same ID with a different type",
+			entries: []PlayHistorySettings{
+				video("a", 0), {Type: "playlist", VideoID: "a"},
+			},
+			want: []PlayHistorySettings{
+				video("a", 0), {Type: "playlist", VideoID: "a"},
+			},
+		},
+		{
+			name: "channels without IDs are keyed by title",
+			entries: []PlayHistorySettings{
+				{Type: "channel", AuthorID: "x", Title: "one"},
+				{Type: "channel", AuthorID: "x", Title: "two"},
+				{Type: "channel", AuthorID: "x", Title: "one"},
+			},
+			want: []PlayHistorySettings{
+				{Type: "channel", AuthorID: "x", Title: "one"},
+				{Type: "channel", AuthorID: "x", Title: "two"},
+			},
+		},
+		{
+			name:    "limit",
+			entries: []PlayHistorySettings{video("a", 0), video("b", 0), video("c", 0)},
+			limit:   2,
+			want:    []PlayHistorySettings{video("a", 0), video("b", 0)},
+		},
+		{
+			name:    "limit counts unique entries",
+			entries: []PlayHistorySettings{video("a", 0), video("a", 0), video("b", 0), video("c", 0)},
+			limit:   2,
+			want:    []PlayHistorySettings{video("a", 0), video("b", 0)},
+		},
+		{
+			name:    "negative limit",
+			entries: []PlayHistorySettings{video("a", 0), video("b", 0)},
+			limit:   -1,
+			want:    []PlayHistorySettings{video("a", 0), video("b", 0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := deduplicateEntries(test.entries, test.limit)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("deduplicateEntries() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPlayHistoryKey(t *testing.T) {
+	tests := []struct {
+		entry PlayHistorySettings
+		want  string
+	}{
+		{entry: PlayHistorySettings{Type: "video", VideoID: "a", Title: "Video"}, want: "video:a"},
+		{entry: PlayHistorySettings{Type: "playlist", PlaylistID: "b", AuthorID: "x"}, want: "playlist:b"},
+		{entry: PlayHistorySettings{Type: "channel", AuthorID: "x", Title: "Channel"}, want: "channel:xChannel"},
+		{entry: PlayHistorySettings{Type: "video"}, want: "video:"},
+	}
+
+	for _, test := range tests {
+		if key := PlayHistoryKey(test.entry); key != test.want {
+			t.Errorf("PlayHistoryKey(%+v) = %q, want %q", test.entry, key, test.want)
+		}
+	}
+}
+
+func TestLimitPlaybackPositions(t *testing.T) {
+	positions := func(timestamps map[string]int64) map[string]PlaybackPositionSettings {
+		p := make(map[string]PlaybackPositionSettings, len(timestamps))
+		for id, timestamp := range timestamps {
+			p[id] = PlaybackPositionSettings{Position: 60, Timestamp: timestamp}
+		}
+
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		positions map[string]PlaybackPositionSettings
+		limit     int
+		want      []string
+	}{
+		{
+			name:      "within limit",
+			positions: positions(map[string]int64{"a": 1, "b": 2}),
+			limit:     2,
+			want:      []string{"a", "b"},
+		},
+		{
+			name:      "no limit",
+			positions: positions(map[string]int64{"a": 1, "b": 2, "c": 3}),
+			limit:     0,
+			want:      []string{"a", "b", "c"},
+		},
+		{
+			name:      "removes the oldest",
+			positions: positions(map[string]int64{"a": 3, "b": 1, "c": 4, "d": 2}),
+			limit:     2,
+			want:      []string{"a", "c"},
+		},
+		{
+			name:      "same timestamps",
+			positions: positions(map[string]int64{"c": 1, "a": 1, "b": 1}),
+			limit:     1,
+			want:      []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limitPlaybackPositions(test.positions, test.limit)
+
+			if len(test.positions) != len(test.want) {
+				t.Fatalf("kept %v, want %v", test.positions, test.want)
+			}
+			for _, id := range test.want {
+				if _, ok := test.positions[id]; !ok {
+					t.Errorf("position of %s was removed, kept %v", id, test.positions)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeSettings(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	previous := SettingsData{PlayerLayout: "previous", QueuePosition: 2}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantParsed bool
+		wantErr    bool
+		want       SettingsData
+	}{
+		{
+			name:       "valid",
+			path:       write("valid.json", `{"playerLayout": "compact", "searchHistory": ["a"]}`),
+			wantParsed: true,
+			want:       SettingsData{PlayerLayout: "compact", QueuePosition: 2, SearchHistory: []string{"a"}},
+		},
+		{
+			name:       "empty",
+			path:       write("empty.json", ""),
+			wantParsed: true,
+			want:       previous,
+		},
+		{
+			name:       "truncated",
+			path:       write("truncated.json", `{"playerLayout": "comp`),
+			wantParsed: false,
+			want:       previous,
+		},
+		{
+			name:       "wrong type",
+			path:       write("type.json", `{"queuePosition": "first"}`),
+			wantParsed: false,
+			want:       previous,
+		},
+		{
+			name:    "missing",
+			path:    filepath.Join(dir, "missing.json"),
+			wantErr: true,
+			want:    previous,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := previous
+
+			parsed, err := decodeSettings(test.path, &settings)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, want error %v", err, test.wantErr)
+			}
+			if parsed != test.wantParsed {
+				t.Errorf("parsed = %v, want %v", parsed, test.wantParsed)
+			}
+			if !reflect.DeepEqual(settings, test.want) {
+				t.Errorf("settings = %+v, want %+v", settings, test.want)
+			}
+		})
+	}
+}
+
+func TestBackupFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+
+	if err := os.WriteFile(path, []byte("{corrupt"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := backupFile(path, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := path + ".corrupt-1700000000"; backup != want {
+		t.Errorf("backup = %q, want %q", backup, want)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("settings file still exists: %v", err)
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != "{corrupt" {
+		t.Errorf("backup content = %q, %v", data, err)
+	}
+
+	if _, err := backupFile(path, time.Unix(1700000001, 0)); err == nil {
+		t.Error("backing up a missing file returned no error")
+	}
+}
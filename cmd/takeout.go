@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+)
+
+// takeoutHistoryEntry describes an entry in a YouTube Takeout watch-history.json file.
+type takeoutHistoryEntry struct {
+	Title     string `json:"title"`
+	TitleURL  string `json:"titleUrl"`
+	Time      string `json:"time"`
+	Subtitles []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"subtitles"`
+}
+
+var (
+	takeoutVideoRegex   = regexp.MustCompile(`<a href="(https://www\.youtube\.com/watch\?v=[^"]+)">([^<]*)</a>`)
+	takeoutChannelRegex = regexp.MustCompile(`<a href="(https://www\.youtube\.com/channel/[^"]+)">([^<]*)</a>`)
+	takeoutTimeRegex    = regexp.MustCompile(`<br>([A-Z][a-z]{2} \d{1,2}, \d{4}, \d{1,2}:\d{2}:\d{2}\s[AP]M [A-Z]+)<br>`)
+)
+
+// importTakeout imports the watch history and subscriptions from the files
+// provided in the 'import-history' and 'import-subscriptions' options,
+// saves the settings and exits.
+func importTakeout() {
+	var summary []string
+
+	history, subscriptions := GetOptionValue("import-history"), GetOptionValue("import-subscriptions")
+	if history == "" && subscriptions == "" {
+		return
+	}
+
+	if history != "" {
+		printer.Print("Importing watch history")
+
+		imported, skipped, err := importHistory(history)
+		if err != nil {
+			printer.Error(err.Error())
+		}
+
+		summary = append(summary, fmt.Sprintf("Imported %d history entries (%d skipped)", imported, skipped))
+	}
+
+	if subscriptions != "" {
+		printer.Print("Importing subscriptions")
+
+		imported, failed, err := importSubscriptions(subscriptions)
+		if err != nil {
+			printer.Error(err.Error())
+		}
+
+		summary = append(summary, fmt.Sprintf("Imported %d subscriptions (%d failed)", imported, failed))
+	}
+
+	SaveSettings()
+
+	printer.Print(strings.Join(summary, "\n"), 0)
+}
+
+// importHistory imports the watch history from the provided Takeout file into the
+// play history, and returns the number of imported and skipped entries. Entries for
+// videos that are unavailable, or which do not have any channel information, are skipped.
+func importHistory(file string) (int, int, error) {
+	var entries []PlayHistorySettings
+	var skipped int
+
+	fd, err := os.Open(file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Import: Cannot open %s", file)
+	}
+	defer fd.Close()
+
+	add := func(videoURL, title, channelURL, author string, timestamp int64) {
+		entry, ok := takeoutHistoryInfo(videoURL, title, channelURL, author, timestamp)
+		if !ok {
+			skipped++
+			return
+		}
+
+		entries = append(entries, entry)
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		err = takeoutHistoryJSON(fd, add)
+
+	case ".html", ".htm":
+		err = takeoutHistoryHTML(fd, add)
+
+	default:
+		return 0, 0, fmt.Errorf("Import: %s is not a watch-history.json or watch-history.html file", file)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("Import: Cannot parse %s: %w", file, err)
+	}
+
+	imported := len(entries)
+
+	entries = append(entries, Settings.PlayHistory...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp > entries[j].Timestamp
+	})
+	Settings.PlayHistory = DeduplicatePlayHistory(entries)
+
+	return imported, skipped, nil
+}
+
+// takeoutHistoryJSON parses the entries of a watch-history.json file one by one,
+// so that large files are not loaded into memory at once.
+func takeoutHistoryJSON(r io.Reader, add func(videoURL, title, channelURL, author string, timestamp int64)) error {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		var entry takeoutHistoryEntry
+		var channelURL, author string
+
+		if err := decoder.Decode(&entry); err != nil {
+			return err
+		}
+
+		if len(entry.Subtitles) > 0 {
+			channelURL, author = entry.Subtitles[0].URL, entry.Subtitles[0].Name
+		}
+
+		var timestamp int64
+		if t, err := time.Parse(time.RFC3339, entry.Time); err == nil {
+			timestamp = t.Unix()
+		}
+
+		add(entry.TitleURL, strings.TrimPrefix(entry.Title, "Watched "), channelURL, author, timestamp)
+	}
+
+	return nil
+}
+
+// takeoutHistoryHTML parses the entries of a watch-history.html file one by one. Each entry
+// is contained within an 'outer-cell' element, so the file is split into entries at each one.
+func takeoutHistoryHTML(r io.Reader, add func(videoURL, title, channelURL, author string, timestamp int64)) error {
+	separator := []byte(`<div class="outer-cell`)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		if i := bytes.Index(data[1:], separator); i >= 0 {
+			return i + 1, data[:i+1], nil
+		}
+
+		if atEOF {
+			return len(data), data, nil
+		}
+
+		return 0, nil, nil
+	})
+
+	for scanner.Scan() {
+		var channelURL, author string
+		var timestamp int64
+
+		cell := scanner.Text()
+
+		video := takeoutVideoRegex.FindStringSubmatch(cell)
+		if video == nil {
+			continue
+		}
+
+		if channel := takeoutChannelRegex.FindStringSubmatch(cell); channel != nil {
+			channelURL, author = channel[1], html.UnescapeString(channel[2])
+		}
+
+		if date := takeoutTimeRegex.FindStringSubmatch(cell); date != nil {
+			if t, err := time.Parse("Jan 2, 2006, 3:04:05 PM MST", strings.Join(strings.Fields(date[1]), " ")); err == nil {
+				timestamp = t.Unix()
+			}
+		}
+
+		add(html.UnescapeString(video[1]), html.UnescapeString(video[2]), channelURL, author, timestamp)
+	}
+
+	return scanner.Err()
+}
+
+// takeoutHistoryInfo returns the play history entry for the provided video and channel,
+// and whether the video is available. Videos which have been removed are listed with their
+// URL as their title, or without any URL and channel at all.
+func takeoutHistoryInfo(videoURL, title, channelURL, author string, timestamp int64) (PlayHistorySettings, bool) {
+	uri, err := url.Parse(videoURL)
+	if err != nil {
+		return PlayHistorySettings{}, false
+	}
+
+	id := uri.Query().Get("v")
+	if id == "" || title == "" || title == videoURL || author == "" {
+		return PlayHistorySettings{}, false
+	}
+
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+
+	return PlayHistorySettings{
+		Type:      "video",
+		Title:     title,
+		Author:    author,
+		VideoID:   id,
+		AuthorID:  strings.TrimPrefix(channelURL, "https://www.youtube.com/channel/"),
+		MediaType: "video",
+		Timestamp: timestamp,
+	}, true
+}
+
+// importSubscriptions subscribes to the channels listed in the provided Takeout
+// subscriptions.csv file, and returns the number of imported and failed subscriptions.
+func importSubscriptions(file string) (int, int, error) {
+	var imported, failed int
+
+	if client.Token() == "" {
+		return 0, 0, fmt.Errorf("Import: Authentication is required to import subscriptions")
+	}
+
+	fd, err := os.Open(file)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Import: Cannot open %s", file)
+	}
+	defer fd.Close()
+
+	reader := csv.NewReader(bufio.NewReader(fd))
+	reader.FieldsPerRecord = -1
+
+	for line := 0; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return imported, failed, fmt.Errorf("Import: Cannot parse %s: %w", file, err)
+		}
+
+		id := strings.TrimSpace(record[0])
+		if line == 0 && !strings.HasPrefix(id, "UC") {
+			continue
+		}
+		if id == "" {
+			continue
+		}
+
+		printer.Print("Subscribing to " + id)
+
+		if _, err := client.Send("auth/subscriptions/"+id, "", client.Token()); err != nil {
+			failed++
+			continue
+		}
+
+		imported++
+	}
+
+	return imported, failed, nil
+}
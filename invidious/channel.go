@@ -1,6 +1,8 @@
 package invidious
 
 import (
+	"context"
+
 	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/utils"
 )
@@ -66,6 +68,18 @@ func ChannelVideos(id, continuation string) (ChannelData, error) {
 	return Channel(id, "videos", params)
 }
 
+// ChannelUploads retrieves a page of video information from a channel.
+// Unlike ChannelVideos, this does not cancel any ongoing requests, and
+// is used to load the channel's videos into the player.
+func ChannelUploads(ctx context.Context, id, continuation string) (ChannelData, error) {
+	query := "channels/" + id + "/videos?fields=videos,continuation"
+	if continuation != "" {
+		query += "&continuation=" + continuation
+	}
+
+	return decodeChannelData(query, ctx)
+}
+
 // ChannelPlaylists loads only the playlists present in the channel.
 func ChannelPlaylists(id, continuation string) (ChannelData, error) {
 	params := "?fields=playlists,continuation"
@@ -82,10 +96,14 @@ func ChannelSearch(id, searchText string, page int) ([]SearchData, int, error) {
 }
 
 // decodeChannelData sends a channel query, parses and returns the response.
-func decodeChannelData(query string) (ChannelData, error) {
+func decodeChannelData(query string, ctx ...context.Context) (ChannelData, error) {
 	var data ChannelData
 
-	res, err := client.Fetch(client.Ctx(), query)
+	if ctx == nil {
+		ctx = append(ctx, client.Ctx())
+	}
+
+	res, err := client.Fetch(ctx[0], query)
 	if err != nil {
 		return ChannelData{}, err
 	}
@@ -0,0 +1,59 @@
+package invidious
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// LyricsAPI is the default LRCLIB-compatible API endpoint to search lyrics from.
+const LyricsAPI = "https://lrclib.net/api/search"
+
+// LyricsData stores the lyrics of a track. The synced lyrics are in the LRC format.
+type LyricsData struct {
+	Plain  string `json:"plainLyrics"`
+	Synced string `json:"syncedLyrics"`
+}
+
+// Lyrics searches the provided endpoint for the lyrics of the track with the
+// provided name and artist. The synced lyrics are preferred over the plain lyrics.
+// If no lyrics are found, empty lyrics are returned.
+func Lyrics(ctx context.Context, endpoint, track, artist string) (LyricsData, error) {
+	var results []LyricsData
+
+	query := url.Values{"track_name": []string{track}}
+	if artist != "" {
+		query.Set("artist_name", artist)
+	}
+
+	res, err := client.GetURL(ctx, endpoint+"?"+query.Encode(), http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return LyricsData{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return LyricsData{}, nil
+	}
+
+	err = utils.JSON().NewDecoder(res.Body).Decode(&results)
+	if err != nil {
+		return LyricsData{}, err
+	}
+
+	var lyrics LyricsData
+	for _, result := range results {
+		if result.Synced != "" {
+			return result, nil
+		}
+
+		if lyrics.Plain == "" {
+			lyrics = result
+		}
+	}
+
+	return lyrics, nil
+}
@@ -0,0 +1,177 @@
+package invidious
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/utils"
+)
+
+const (
+	// LastFMAPI is the Last.fm API endpoint to submit scrobbles to.
+	LastFMAPI = "https://ws.audioscrobbler.com/2.0/"
+
+	// ListenBrainzAPI is the ListenBrainz API endpoint to submit listens to.
+	ListenBrainzAPI = "https://api.listenbrainz.org/1/submit-listens"
+)
+
+// ScrobbleTrack stores information about a track to be scrobbled.
+type ScrobbleTrack struct {
+	Title     string
+	Artist    string
+	VideoID   string
+	Duration  int64
+	Timestamp int64
+}
+
+// ScrobbleAuth stores the credentials for a scrobbling service.
+// The API key and secret are only required for Last.fm.
+type ScrobbleAuth struct {
+	Service   string
+	Token     string
+	APIKey    string
+	APISecret string
+}
+
+// listenBrainzListen describes a listen submitted to ListenBrainz.
+type listenBrainzListen struct {
+	ListenedAt int64 `json:"listened_at,omitempty"`
+
+	TrackMetadata struct {
+		ArtistName     string `json:"artist_name"`
+		TrackName      string `json:"track_name"`
+		AdditionalInfo struct {
+			DurationMS int64  `json:"duration_ms,omitempty"`
+			OriginURL  string `json:"origin_url,omitempty"`
+		} `json:"additional_info"`
+	} `json:"track_metadata"`
+}
+
+// ScrobbleNowPlaying submits the provided track as the currently playing track.
+func ScrobbleNowPlaying(ctx context.Context, auth ScrobbleAuth, track ScrobbleTrack) error {
+	return scrobble(ctx, auth, track, true)
+}
+
+// Scrobble submits the provided track as a played track.
+func Scrobble(ctx context.Context, auth ScrobbleAuth, track ScrobbleTrack) error {
+	return scrobble(ctx, auth, track, false)
+}
+
+// scrobble submits the track to the service provided in the credentials.
+func scrobble(ctx context.Context, auth ScrobbleAuth, track ScrobbleTrack, nowPlaying bool) error {
+	var err error
+
+	switch auth.Service {
+	case "lastfm":
+		err = scrobbleLastFM(ctx, auth, track, nowPlaying)
+
+	case "listenbrainz":
+		err = scrobbleListenBrainz(ctx, auth, track, nowPlaying)
+
+	default:
+		return fmt.Errorf("Scrobbler: Unknown service %s", auth.Service)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Scrobbler: %s", err.Error())
+	}
+
+	return nil
+}
+
+// scrobbleLastFM submits the track to Last.fm.
+func scrobbleLastFM(ctx context.Context, auth ScrobbleAuth, track ScrobbleTrack, nowPlaying bool) error {
+	params := url.Values{}
+	params.Set("method", "track.scrobble")
+	params.Set("artist", track.Artist)
+	params.Set("track", track.Title)
+	params.Set("duration", strconv.FormatInt(track.Duration, 10))
+	params.Set("api_key", auth.APIKey)
+	params.Set("sk", auth.Token)
+
+	if nowPlaying {
+		params.Set("method", "track.updateNowPlaying")
+	} else {
+		params.Set("timestamp", strconv.FormatInt(track.Timestamp, 10))
+	}
+
+	params.Set("api_sig", lastFMSignature(params, auth.APISecret))
+	params.Set("format", "json")
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := client.PostURL(ctx, LastFMAPI, params.Encode(), header)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+// scrobbleListenBrainz submits the track to ListenBrainz.
+func scrobbleListenBrainz(ctx context.Context, auth ScrobbleAuth, track ScrobbleTrack, nowPlaying bool) error {
+	var listen listenBrainzListen
+
+	listenType := "single"
+	if nowPlaying {
+		listenType = "playing_now"
+	} else {
+		listen.ListenedAt = track.Timestamp
+	}
+
+	listen.TrackMetadata.ArtistName = track.Artist
+	listen.TrackMetadata.TrackName = track.Title
+	listen.TrackMetadata.AdditionalInfo.DurationMS = track.Duration * 1000
+	if track.VideoID != "" {
+		listen.TrackMetadata.AdditionalInfo.OriginURL = "https://www.youtube.com/watch?v=" + track.VideoID
+	}
+
+	body, err := utils.JSON().MarshalToString(map[string]interface{}{
+		"listen_type": listenType,
+		"payload":     []listenBrainzListen{listen},
+	})
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Authorization", "Token "+auth.Token)
+
+	res, err := client.PostURL(ctx, ListenBrainzAPI, body, header)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	return nil
+}
+
+// lastFMSignature returns the signature for the provided Last.fm API parameters,
+// which is the MD5 hash of the sorted parameters concatenated with the API secret.
+func lastFMSignature(params url.Values, secret string) string {
+	var signature string
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		signature += key + params.Get(key)
+	}
+
+	hash := md5.Sum([]byte(signature + secret))
+
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,50 @@
+package invidious
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// SponsorBlockAPI is the SponsorBlock API endpoint to retrieve segments from.
+const SponsorBlockAPI = "https://sponsor.ajay.app/api/skipSegments"
+
+// SponsorSegment stores information about a SponsorBlock segment.
+type SponsorSegment struct {
+	UUID     string     `json:"UUID"`
+	Category string     `json:"category"`
+	Segment  [2]float64 `json:"segment"`
+}
+
+// SponsorSegments retrieves the SponsorBlock segments for a video,
+// which belong to the provided categories.
+func SponsorSegments(ctx context.Context, id string, categories []string) ([]SponsorSegment, error) {
+	var segments []SponsorSegment
+
+	query, err := utils.JSON().MarshalToString(categories)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := SponsorBlockAPI + "?videoID=" + url.QueryEscape(id) + "&categories=" + url.QueryEscape(query)
+
+	res, err := client.GetURL(ctx, uri, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	err = utils.JSON().NewDecoder(res.Body).Decode(&segments)
+	if err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
@@ -15,12 +15,13 @@ import (
 	"github.com/etherlabsio/go-m3u8/m3u8"
 )
 
-const videoFields = "?fields=title,videoId,author,hlsUrl,publishedText,lengthSeconds,formatStreams,adaptiveFormats,videoThumbnails,liveNow,viewCount,likeCount,subCountText,description&hl=en"
+const videoFields = "?fields=title,videoId,author,authorId,hlsUrl,publishedText,lengthSeconds,formatStreams,adaptiveFormats,videoThumbnails,liveNow,viewCount,likeCount,subCountText,description&hl=en"
 
 // VideoData stores information about a video.
 type VideoData struct {
 	Title           string            `json:"title"`
 	Author          string            `json:"author"`
+	AuthorID        string            `json:"authorId"`
 	VideoID         string            `json:"videoId"`
 	HlsURL          string            `json:"hlsUrl"`
 	LengthSeconds   int64             `json:"lengthSeconds"`
@@ -80,9 +81,29 @@ func Video(id string, ctx ...context.Context) (VideoData, error) {
 	return data, nil
 }
 
+// RelatedVideos retrieves the videos which are recommended for a video.
+func RelatedVideos(ctx context.Context, id string) ([]VideoData, error) {
+	var data struct {
+		RecommendedVideos []VideoData `json:"recommendedVideos"`
+	}
+
+	res, err := client.Fetch(ctx, "videos/"+id+"?fields=recommendedVideos&hl=en")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	err = utils.JSON().NewDecoder(res.Body).Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.RecommendedVideos, nil
+}
+
 // VideoThumbnail returns data to parse a video thumbnail.
 func VideoThumbnail(ctx context.Context, id, image string) (*http.Response, error) {
-	res, err := client.Get(ctx, fmt.Sprintf("/vi/%s/%s", id, image))
+	res, err := client.GetImage(ctx, fmt.Sprintf("/vi/%s/%s", id, image))
 	if err != nil {
 		return nil, err
 	}
@@ -256,9 +277,52 @@ func getVideoByItag(video VideoData, audio bool) (string, string) {
 		},
 	)
 
+	if audio {
+		if format, ok := preferredAudioFormat(video); ok {
+			audioURL = getLatestURL(video.VideoID, format.Itag)
+		}
+	}
+
 	return videoURL, audioURL
 }
 
+// preferredAudioFormat returns the audio format which matches the 'audio-format' option.
+// If there is no preference, or no audio format matches it, false is returned.
+func preferredAudioFormat(video VideoData) (VideoFormat, bool) {
+	var found bool
+	var preferred VideoFormat
+
+	preference := cmd.GetOptionValue("audio-format")
+	if preference == "default" {
+		return VideoFormat{}, false
+	}
+
+	for _, format := range video.AdaptiveFormats {
+		if !strings.HasPrefix(format.Type, "audio/") {
+			continue
+		}
+
+		switch preference {
+		case "opus":
+			if !strings.Contains(format.Type, "opus") {
+				continue
+			}
+
+		case "aac":
+			if !strings.Contains(format.Type, "mp4a") {
+				continue
+			}
+		}
+
+		if !found || format.Bitrate > preferred.Bitrate {
+			found = true
+			preferred = format
+		}
+	}
+
+	return preferred, found
+}
+
 // loopFormats loops over a video's AdaptiveFormats data and gets the
 // audio/video URL according to the values returned by afunc/vfunc.
 func loopFormats(
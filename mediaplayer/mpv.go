@@ -2,11 +2,15 @@ package mediaplayer
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/rand"
 	"net/url"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,30 +26,227 @@ type MPV struct {
 	socket  string
 	monitor map[int]string
 
-	lock sync.Mutex
+	execpath, ytdlpath, useragent string
+	numretries, retrydelay        string
+	args                          []string
+
+	premute    int
+	unshuffled []int
+	hwdec      string
+	audio      string
+	levelMeter bool
+	aspect     string
+	zoom       float64
+	cacheSecs  int
+
+	playlist    []string
+	playlistPos int
+	paused      bool
+
+	properties map[string]interface{}
+
+	// The load lock is held while the queue is changed along with the
+	// error monitor, so that a track which is being loaded is not added
+	// to the monitor after the queue is cleared, and vice-versa.
+	lock, load sync.Mutex
+
+	// The conn lock guards the connection, which is replaced when MPV
+	// is relaunched. It is held while the queue is restored, so that
+	// the player is not controlled until the restore has finished.
+	conn sync.RWMutex
+
+	command *exec.Cmd
+	exited  chan struct{}
+
+	// wait waits between the attempts to connect to MPV, and is
+	// replaced in tests so that the attempts are not delayed.
+	wait func(delay time.Duration, exited <-chan struct{}) bool
 
 	*mpvipc.Connection
 }
 
 var mpv MPV
 
-// Init initializes and sets up MPV.
-func (m *MPV) Init(execpath, ytdlpath, numretries, useragent, socket string) error {
-	if err := m.connect(
-		execpath, ytdlpath,
-		numretries, useragent, socket,
-	); err != nil {
+// enqueueMessage is the name of the script message which
+// is sent to request a running instance to queue a URL.
+const enqueueMessage = "invidtui-enqueue"
+
+// The minimum supported versions of mpv, which reports the playlist entry IDs in its
+// 'end-file' events since 0.33.0, and of youtube-dl, whose last release is used.
+var (
+	minMPVVersion  = []int{0, 33, 0}
+	minYTDLVersion = []int{2021, 12, 17}
+)
+
+// restrictedErrors lists the messages which youtube-dl/yt-dlp reports when
+// a video is age-restricted or requires signing in.
+var restrictedErrors = []string{
+	"confirm your age",
+	"age-restricted",
+	"inappropriate for some users",
+	"sign in to confirm",
+	"login required",
+	"requires authentication",
+	"members-only",
+}
+
+// errExited is returned when MPV exits while connecting to its socket.
+var errExited = errors.New("MPV: Exited")
+
+// maxRetryDelay is the maximum delay between attempts to connect to the socket,
+// unless the initial delay is higher.
+const maxRetryDelay = 2 * time.Second
+
+// observedProperties lists the properties which are observed to update
+// the player when they change. Their observer IDs start from 3, since
+// the playlist and eof-reached properties are observed with IDs 1 and 2.
+// The observed values are cached, to avoid querying MPV for them on every update.
+// The level meter's metadata changes with every audio frame, so its changes are
+// only cached, and shown when the player is next updated.
+var observedProperties = []string{
+	"playback-time",
+	"duration",
+	"pause",
+	"paused-for-cache",
+	"mute",
+	"volume",
+	"shuffle",
+	"loop-file",
+	"loop-playlist",
+	"media-title",
+	"hwdec",
+	"audio-device",
+	levelMeterProperty,
+}
+
+// levelMeterFilter is the audio filter which measures the audio level. Its statistics
+// are reset for every frame, so that the level reflects the audio that is currently playing.
+const levelMeterFilter = "@" + levelMeterLabel + ":lavfi=[astats=metadata=1:reset=1]"
+
+// levelMeterLabel is the label of the audio filter which measures the audio level.
+const levelMeterLabel = "invidtuilevel"
+
+// levelMeterProperty is the property which holds the statistics of the level meter.
+const levelMeterProperty = "af-metadata/" + levelMeterLabel
+
+// EssentialArgs lists the MPV options which are required by the application,
+// and which cannot be overridden by the arguments provided to Init.
+var EssentialArgs = []string{
+	"idle",
+	"keep-open",
+	"input-ipc-server",
+}
+
+// Init initializes and sets up MPV. The provided arguments are passed to MPV
+// before the application's own arguments, so that they can override any defaults
+// except the essential ones.
+func (m *MPV) Init(execpath, ytdlpath, numretries, retrydelay, useragent, socket string, args ...string) error {
+	executables := []executable{
+		{Name: "MPV", Path: execpath, Option: "mpv-path", MinVersion: minMPVVersion},
+	}
+	if ytdlpath != "" {
+		executables = append(executables, executable{
+			Name: "YTDL", Path: ytdlpath, Option: "ytdl-path", MinVersion: minYTDLVersion,
+		})
+	}
+
+	if err := preflight(executables...); err != nil {
+		return err
+	}
+
+	m.execpath, m.ytdlpath = execpath, ytdlpath
+	m.numretries, m.retrydelay, m.useragent = numretries, retrydelay, useragent
+	m.socket, m.args = socket, args
+
+	conn, err := m.connect()
+	if err != nil {
 		return err
 	}
 
+	m.conn.Lock()
+	m.Connection = conn
+	m.conn.Unlock()
+
 	m.monitor = make(map[int]string)
 
 	go m.eventListener()
 	go m.startMonitor()
 
-	m.Call("keybind", "q", "")
-	m.Call("keybind", "Ctrl+q", "")
-	m.Call("keybind", "Shift+q", "")
+	m.setKeybindings()
+
+	return nil
+}
+
+// Reconnect relaunches MPV if it has exited abruptly, and restores
+// the last known queue. The relaunch is attempted 'retries' times, with
+// the delay between each attempt doubling after every failed attempt.
+func (m *MPV) Reconnect(retries int, delay time.Duration) error {
+	m.lock.Lock()
+	playlist, pos, paused := m.playlist, m.playlistPos, m.paused
+	m.lock.Unlock()
+
+	if m.command != nil && m.command.Process != nil {
+		m.command.Process.Kill()
+		<-m.exited
+	}
+
+	for attempt := 1; attempt <= retries; attempt++ {
+		sendReconnectEvent(attempt)
+		utils.LogWarnf("MPV: Reconnecting (attempt %d/%d)", attempt, retries)
+
+		conn, err := m.connect()
+		if err != nil {
+			utils.LogWarnf("MPV: Reconnection attempt %d failed: %v", attempt, err)
+
+			m.delay(delay, nil)
+			delay *= 2
+
+			continue
+		}
+
+		m.load.Lock()
+		m.conn.Lock()
+
+		m.clearMonitor()
+		m.Connection = conn
+		m.restore(conn, playlist, pos, paused)
+
+		m.conn.Unlock()
+		m.load.Unlock()
+
+		go m.eventListener()
+
+		m.setKeybindings()
+		sendReconnectEvent(0)
+		utils.LogInfof("MPV: Reconnected, restored %d tracks", len(playlist))
+
+		return nil
+	}
+
+	utils.LogErrorf("MPV: Could not reconnect after %d attempts", retries)
+
+	return fmt.Errorf("MPV: Could not reconnect after %d attempts", retries)
+}
+
+// SendEnqueue sends a request to queue the provided URL to the MPV instance at the
+// provided socket. The request is sent as a 'script-message', which is broadcast by
+// MPV as a 'client-message' event to all clients connected to the socket, including
+// the instance which started MPV.
+func (m *MPV) SendEnqueue(socket, uri string, audio bool) error {
+	conn := mpvipc.NewConnection(socket)
+	if err := conn.Open(); err != nil {
+		return fmt.Errorf("MPV: Cannot connect to %s", socket)
+	}
+	defer conn.Close()
+
+	mediatype := "video"
+	if audio {
+		mediatype = "audio"
+	}
+
+	if _, err := conn.Call("script-message", enqueueMessage, mediatype, uri); err != nil {
+		return fmt.Errorf("MPV: Cannot queue %s", uri)
+	}
 
 	return nil
 }
@@ -58,7 +259,9 @@ func (m *MPV) Exit() {
 
 // Exited returns whether MPV has exited or not.
 func (m *MPV) Exited() bool {
-	return m.Connection == nil || m.Connection.IsClosed()
+	conn := m.connection()
+
+	return conn == nil || conn.IsClosed()
 }
 
 // SendQuit sends a quit signal to the provided socket.
@@ -74,7 +277,7 @@ func (m *MPV) SendQuit(socket string) {
 
 // LoadFile loads the provided files into MPV. When more than one file is provided,
 // the first file is treated as a video stream and the second file is attached as an audio stream.
-func (m *MPV) LoadFile(title string, duration int64, audio bool, files ...string) error {
+func (m *MPV) LoadFile(title string, duration int64, audio bool, trim Trim, files ...string) error {
 	options := "force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
 
 	if duration > 0 {
@@ -85,17 +288,45 @@ func (m *MPV) LoadFile(title string, duration int64, audio bool, files ...string
 		options += ",vid=no"
 	}
 
+	if trim.Start > 0 {
+		start := strconv.FormatInt(trim.Start, 10)
+
+		options += ",start=" + start
+		files[0] += "&start=" + start
+	}
+
+	if trim.End > 0 {
+		end := strconv.FormatInt(trim.End, 10)
+
+		options += ",end=" + end
+		files[0] += "&end=" + end
+	}
+
 	if len(files) == 2 {
-		options += ",audio-file=" + files[1]
+		options += ",audio-file=%" + strconv.Itoa(len(files[1])) + "%" + files[1]
 	}
 
 	files[0] += "&options=" + url.QueryEscape(options)
-	_, err := m.Call("loadfile", files[0], "append-play", options)
-	if err != nil {
+	utils.LogDebugf("MPV: Loading %s", files[0])
+
+	if err := m.loadEntry(files[0], title, options); err != nil {
+		utils.LogErrorf("MPV: Unable to load %s: %v", title, err)
 		return fmt.Errorf("MPV: Unable to load %s", title)
 	}
 
-	m.addToMonitor(title)
+	return nil
+}
+
+// loadEntry appends the provided file to the queue, and adds it to the error monitor.
+func (m *MPV) loadEntry(file, title, options string) error {
+	m.load.Lock()
+	defer m.load.Unlock()
+
+	if _, err := m.Call("loadfile", file, "append-play", options); err != nil {
+		return err
+	}
+
+	m.addToMonitor(m.connection(), title)
 
 	return nil
 }
@@ -113,10 +344,18 @@ func (m *MPV) LoadPlaylist(
 	var filesAdded int
 
 	if replace {
+		m.load.Lock()
+
 		m.Call("playlist-clear")
 		m.Call("playlist-remove", "current")
 
 		m.clearMonitor()
+
+		m.lock.Lock()
+		m.unshuffled = nil
+		m.lock.Unlock()
+
+		m.load.Unlock()
 	}
 
 	pl, err := os.Open(plpath)
@@ -125,58 +364,26 @@ func (m *MPV) LoadPlaylist(
 	}
 	defer pl.Close()
 
-	// We implement a simple playlist parser instead of relying on
-	// the m3u8 package here, since that package deals with mainly
-	// HLS playlists, and it seems to panic when certain EXTINF fields
-	// are blank. With this method, we can parse the URLs from the playlist
-	// directly, and pass the relevant options to mpv as well.
-	scanner := bufio.NewScanner(pl)
-	scanner.Split(bufio.ScanLines)
-
-	for scanner.Scan() {
-		var title, options string
-
-		line := scanner.Text()
-		if strings.HasPrefix(line, "#") || line == "" {
-			continue
-		}
-
-		lineURI, err := utils.IsValidURL(line)
-		if err != nil {
-			continue
-		}
-
-		lineURI.Host = utils.GetHostname(client.Instance())
-		line = lineURI.String()
+	entries, err := parsePlaylist(pl, filepath.Dir(plpath))
+	if err != nil {
+		return err
+	}
 
-		data := lineURI.Query()
-		if t := data.Get("title"); t != "" {
-			title = t
-		}
-		if o := data.Get("options"); o != "" {
-			options = replaceOptions(o)
-		}
-		if l := data.Get("length"); l == "Live" {
-			audio := data.Get("mediatype") == "Audio"
-			if renewed := renewLiveURL(line, audio); renewed {
+	for _, entry := range entries {
+		if l := entry.data.Get("length"); l == "Live" {
+			audio := entry.data.Get("mediatype") == "Audio"
+			if renewed := renewLiveURL(entry.file, audio); renewed {
 				continue
 			}
 		}
 
-		if !strings.Contains(options, "force-media-title") {
-			options += ",force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
-		}
+		title, options := entryOptions(entry.data)
 
-		if _, err := m.Call("loadfile", line, "append-play", options); err != nil {
+		if err := m.loadEntry(entry.file, title, options); err != nil {
 			return err
 		}
 
 		filesAdded++
-
-		m.addToMonitor(title)
-	}
-	if err := scanner.Err(); err != nil && err != io.EOF {
-		return err
 	}
 	if filesAdded == 0 {
 		return fmt.Errorf("MPV: No files were added")
@@ -189,11 +396,12 @@ func (m *MPV) LoadPlaylist(
 func (m *MPV) Title(pos int) string {
 	pltitle, _ := m.Call("get_property_string", "playlist/"+strconv.Itoa(pos)+"/filename")
 
-	if pltitle == nil {
+	title, ok := pltitle.(string)
+	if !ok {
 		return "-"
 	}
 
-	return pltitle.(string)
+	return title
 }
 
 // MediaType returns the mediatype of the currently playing track.
@@ -206,6 +414,53 @@ func (m *MPV) MediaType() string {
 	return "Video"
 }
 
+// AudioCodec returns the codec of the currently playing audio stream.
+func (m *MPV) AudioCodec() string {
+	codec, err := m.Get("audio-codec-name")
+	if err != nil {
+		return ""
+	}
+
+	if name, ok := codec.(string); ok {
+		return name
+	}
+
+	return ""
+}
+
+// VideoCodec returns the codec of the currently playing video stream.
+func (m *MPV) VideoCodec() string {
+	codec, err := m.Get("video-codec")
+	if err != nil {
+		return ""
+	}
+
+	if name, ok := codec.(string); ok {
+		return name
+	}
+
+	return ""
+}
+
+// Bitrate returns the combined bitrate of the currently playing
+// audio and video streams, in bits per second.
+func (m *MPV) Bitrate() int64 {
+	var bitrate int64
+
+	for _, prop := range []string{"audio-bitrate", "video-bitrate"} {
+		rate, err := m.Get(prop)
+		if err != nil {
+			continue
+		}
+
+		if r, ok := propertyFloat(rate); ok {
+			bitrate += int64(r)
+		}
+	}
+
+	return bitrate
+}
+
 // Play start the playback.
 func (m *MPV) Play() {
 	m.Set("pause", "no")
@@ -226,6 +481,40 @@ func (m *MPV) Prev() {
 	m.Call("playlist-prev")
 }
 
+// SkipForward switches to the track n positions after the current track.
+func (m *MPV) SkipForward(n int) {
+	m.skip(n)
+}
+
+// SkipBackward switches to the track n positions before the current track.
+func (m *MPV) SkipBackward(n int) {
+	m.skip(-n)
+}
+
+// skip switches to the track at the provided offset from the current track.
+// If the playlist is looped, the position wraps around the ends of the queue,
+// otherwise it is clamped to the first or last track.
+func (m *MPV) skip(offset int) {
+	count, pos := m.QueueCount(), m.QueuePosition()
+	if count == 0 || pos < 0 {
+		return
+	}
+
+	target := pos + offset
+
+	if lp, err := m.property("loop-playlist"); err == nil && loopEnabled(lp) {
+		target = ((target % count) + count) % count
+	} else if target < 0 {
+		target = 0
+	} else if target >= count {
+		target = count - 1
+	}
+
+	if target != pos {
+		m.QueueSwitchToTrack(target)
+	}
+}
+
 // SeekForward seeks the track forward by 1s.
 func (m *MPV) SeekForward() {
 	m.Call("seek", 1)
@@ -236,44 +525,46 @@ func (m *MPV) SeekBackward() {
 	m.Call("seek", -1)
 }
 
+// SeekToPosition seeks the track to the provided position, in seconds.
+func (m *MPV) SeekToPosition(position int64) {
+	m.Call("seek", position, "absolute")
+}
+
 // Position returns the seek position.
 func (m *MPV) Position() int64 {
-	timepos, err := m.Get("playback-time")
+	timepos, err := m.property("playback-time")
 	if err != nil {
 		return 0
 	}
 
-	return int64(timepos.(float64))
+	pos, _ := propertyFloat(timepos)
+
+	return int64(pos)
 }
 
 // Duration returns the total duration of the track.
 func (m *MPV) Duration() int64 {
-	duration, err := m.Get("duration")
+	duration, err := m.property("duration")
 	if err != nil {
 		duration, err = m.Get("options/length")
 		if err != nil {
 			return 0
 		}
-
-		time, err := strconv.ParseInt(duration.(string), 10, 64)
-		if err != nil {
-			return 0
-		}
-
-		return time
 	}
 
-	return int64(duration.(float64))
+	time, _ := propertyFloat(duration)
+
+	return int64(time)
 }
 
 // Paused returns whether playback is paused or not.
 func (m *MPV) Paused() bool {
-	paused, err := m.Get("pause")
+	paused, err := m.property("pause")
 	if err != nil {
 		return false
 	}
 
-	return paused.(bool)
+	return propertyBool(paused, false)
 }
 
 // TogglePaused toggles pausing the playback.
@@ -287,12 +578,12 @@ func (m *MPV) TogglePaused() {
 
 // Shuffled returns whether tracks are shuffled.
 func (m *MPV) Shuffled() bool {
-	shuffle, err := m.Get("shuffle")
+	shuffle, err := m.property("shuffle")
 	if err != nil {
 		return false
 	}
 
-	return shuffle.(bool)
+	return propertyBool(shuffle, false)
 }
 
 // ToggleShuffled toggles shuffling of tracks.
@@ -300,261 +591,1138 @@ func (m *MPV) ToggleShuffled() {
 	m.Call("cycle", "shuffle")
 }
 
-// Muted returns whether playback is muted.
-func (m *MPV) Muted() bool {
-	mute, err := m.Get("mute")
-	if err != nil {
-		return false
+// ReshuffleKeepingCurrent moves the currently playing track to the top
+// of the queue and shuffles the rest of the tracks after it.
+// Since the tracks are only moved, their playlist entry IDs remain the same,
+// and the error monitor does not need to be updated.
+func (m *MPV) ReshuffleKeepingCurrent() {
+	count := m.QueueCount()
+	pos := m.QueuePosition()
+	if count < 2 || pos < 0 {
+		return
 	}
 
-	return mute.(bool)
-}
+	if pos > 0 {
+		m.Call("playlist-move", pos, 0)
+	}
 
-// ToggleMuted toggles muting of the playback.
-func (m *MPV) ToggleMuted() {
-	m.Call("cycle", "mute")
+	m.shuffle(1, count, rand.New(rand.NewSource(time.Now().UnixNano())))
 }
 
-// LoopMode returns the current loop setting
-// Either of loop-file (R-F), loop-playlist (R-P), or nothing.
-func (m *MPV) LoopMode() string {
-	lf, err := m.Call("get_property_string", "loop-file")
-	if err != nil {
-		return ""
+// ShuffleWithSeed shuffles the queue in an order determined by the provided seed.
+// The order of the queue before it is first shuffled is recorded, and the queue is
+// always shuffled from that order, so that the same seed results in the same order.
+func (m *MPV) ShuffleWithSeed(seed int64) {
+	count := m.QueueCount()
+	if count < 2 {
+		return
 	}
 
-	lp, err := m.Call("get_property_string", "loop-playlist")
-	if err != nil {
-		return ""
+	m.Unshuffle()
+
+	m.lock.Lock()
+	m.unshuffled = m.playlistIDs()
+	m.lock.Unlock()
+
+	m.shuffle(0, count, rand.New(rand.NewSource(seed)))
+}
+
+// Unshuffle restores the queue to the order it was in before it was
+// shuffled with ShuffleWithSeed. Tracks which were added after the queue
+// was shuffled are placed after the restored tracks.
+func (m *MPV) Unshuffle() {
+	m.lock.Lock()
+	order := m.unshuffled
+	m.unshuffled = nil
+	m.lock.Unlock()
+
+	if order == nil {
+		return
 	}
 
-	if lf == "yes" || lf == "inf" {
-		return "loop-file"
+	current := m.playlistIDs()
+	positions := make(map[int]struct{}, len(current))
+	for _, id := range current {
+		positions[id] = struct{}{}
 	}
 
-	if lp == "yes" || lp == "inf" {
-		return "loop-playlist"
+	target := make([]int, 0, len(current))
+	for _, id := range order {
+		if _, ok := positions[id]; ok {
+			target = append(target, id)
+		}
 	}
 
-	return ""
+	for to, id := range target {
+		from := to
+		for from < len(current) && current[from] != id {
+			from++
+		}
+		if from == len(current) || from == to {
+			continue
+		}
+
+		m.Call("playlist-move", from, to)
+
+		current = append(current[:from], current[from+1:]...)
+		current = append(current[:to], append([]int{id}, current[to:]...)...)
+	}
 }
 
-// ToggleLoopMode toggles the loop mode between none, loop-file and loop-playlist.
-func (m *MPV) ToggleLoopMode() {
-	switch m.LoopMode() {
-	case "":
-		m.Set("loop-file", "yes")
-		m.Set("loop-playlist", "no")
+// IsShuffledWithSeed returns whether the queue was shuffled with ShuffleWithSeed.
+func (m *MPV) IsShuffledWithSeed() bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
 
-	case "loop-file":
-		m.Set("loop-file", "no")
-		m.Set("loop-playlist", "yes")
+	return m.unshuffled != nil
+}
 
-	case "loop-playlist":
-		m.Set("loop-file", "no")
-		m.Set("loop-playlist", "no")
+// shuffle shuffles the tracks from the start position till the end of
+// the queue, using the Fisher-Yates algorithm with the provided source.
+func (m *MPV) shuffle(start, count int, random *rand.Rand) {
+	for i := start; i < count-1; i++ {
+		j := i + random.Intn(count-i)
+		if j == i {
+			continue
+		}
+
+		m.Call("playlist-move", j, i)
 	}
 }
 
-// Idle returns if the player is idle.
-func (m *MPV) Idle() bool {
-	idle, err := m.Get("core-idle")
-	if err != nil {
-		return false
+// currentID returns the playlist entry ID of the currently playing track.
+func (m *MPV) currentID() int {
+	ids := m.playlistIDs()
+
+	pos := m.QueuePosition()
+	if pos < 0 || pos >= len(ids) {
+		return -1
 	}
 
-	return idle.(bool)
+	return ids[pos]
 }
 
-// Finished returns if the playback has finished.
-func (m *MPV) Finished() bool {
-	eof, err := m.Get("eof-reached")
-	if err != nil {
-		return false
+// playlistIDs returns the playlist entry IDs of the tracks in the queue, in order.
+func (m *MPV) playlistIDs() []int {
+	var entries []struct {
+		ID int `json:"id"`
 	}
 
-	return eof.(bool)
-}
+	if err := utils.JSON().UnmarshalFromString(m.QueueData(), &entries); err != nil {
+		return nil
+	}
 
-// Buffering returns if the player is buffering.
-func (m *MPV) Buffering() bool {
-	buf, err := m.Get("paused-for-cache")
-	if err != nil {
-		return true
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
 	}
 
-	return buf.(bool)
+	return ids
 }
 
-// Volume returns the volume.
-func (m *MPV) Volume() int {
-	vol, err := m.Get("volume")
+// hwdecModes lists the hardware decoding modes cycled through by ToggleHWDec.
+var hwdecModes = []string{"no", "auto-safe", "auto"}
+
+// aspectRatios lists the aspect ratios cycled through by CycleAspect,
+// where "-1" uses the aspect ratio of the video.
+var aspectRatios = []string{"-1", "16:9", "4:3"}
+
+// maxZoom is the maximum video zoom level in either direction. Since the zoom
+// level is a power of 2, a level of 2 enlarges the video to 4 times its size.
+const maxZoom = 2
+
+// MaxCacheSecs is the maximum number of seconds which can be cached ahead.
+const MaxCacheSecs = 3600
+
+// DefaultCacheSecs is the number of seconds which are cached ahead if the
+// 'cache-secs' option is not set, since MPV's default is 1000 hours.
+const DefaultCacheSecs = 30
+
+// HWDec returns the current hardware decoding mode.
+func (m *MPV) HWDec() string {
+	hwdec, err := m.property("hwdec")
 	if err != nil {
-		return -1
+		return ""
 	}
 
-	return int(vol.(float64))
+	mode, _ := hwdec.(string)
+
+	return mode
 }
 
-// VolumeIncrease increments the volume by 1.
-func (m *MPV) VolumeIncrease() {
-	vol := m.Volume()
-	if vol == -1 {
-		return
-	}
+// SetHWDec sets the hardware decoding mode. The mode is also
+// applied when MPV is relaunched after it has exited abruptly.
+func (m *MPV) SetHWDec(mode string) {
+	m.lock.Lock()
+	m.hwdec = mode
+	m.lock.Unlock()
 
-	m.Set("volume", vol+1)
+	m.Set("hwdec", mode)
 }
 
-// VolumeDecrease decreases the volume by 1.
-func (m *MPV) VolumeDecrease() {
-	vol := m.Volume()
-	if vol == -1 {
-		return
-	}
+// ToggleHWDec cycles the hardware decoding mode between no, auto-safe
+// and auto, and returns the new mode.
+func (m *MPV) ToggleHWDec() string {
+	mode := hwdecModes[0]
+
+	current := m.HWDec()
+	for i, hwdec := range hwdecModes {
+		if hwdec == current {
+			mode = hwdecModes[(i+1)%len(hwdecModes)]
+			break
+		}
+	}
+
+	m.SetHWDec(mode)
+
+	return mode
+}
+
+// AudioDevices returns the audio output devices which are available.
+func (m *MPV) AudioDevices() []AudioDevice {
+	var devices []AudioDevice
+
+	list, err := m.Get("audio-device-list")
+	if err != nil {
+		return nil
+	}
+
+	entries, _ := list.([]interface{})
+	for _, entry := range entries {
+		device, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name, _ := device["name"].(string)
+		description, _ := device["description"].(string)
+		if name == "" {
+			continue
+		}
+
+		devices = append(devices, AudioDevice{Name: name, Description: description})
+	}
+
+	return devices
+}
+
+// SetAudioDevice sets the audio output device. The device is also
+// applied when MPV is relaunched after it has exited abruptly.
+func (m *MPV) SetAudioDevice(name string) {
+	m.lock.Lock()
+	m.audio = name
+	m.lock.Unlock()
+
+	m.Set("audio-device", name)
+}
+
+// CurrentAudioDevice returns the name of the current audio output device.
+func (m *MPV) CurrentAudioDevice() string {
+	device, err := m.property("audio-device")
+	if err != nil {
+		return ""
+	}
+
+	name, _ := device.(string)
+
+	return name
+}
+
+// SetAudioLevelMeter adds or removes the audio filter which measures the audio level.
+// The filter is also applied when MPV is relaunched after it has exited abruptly.
+func (m *MPV) SetAudioLevelMeter(enabled bool) error {
+	command, filter := "remove", "@"+levelMeterLabel
+	if enabled {
+		command, filter = "add", levelMeterFilter
+	}
+
+	if _, err := m.Call("af", command, filter); err != nil {
+		utils.LogErrorf("MPV: Unable to %s the audio level filter: %v", command, err)
+		return fmt.Errorf("MPV: Unable to set the audio level meter")
+	}
+
+	m.lock.Lock()
+	m.levelMeter = enabled
+	m.lock.Unlock()
+
+	return nil
+}
+
+// AudioLevel returns the RMS level of the audio output in decibels, as measured
+// by the audio level filter. If the level is unavailable, false is returned.
+func (m *MPV) AudioLevel() (float64, bool) {
+	metadata, err := m.property(levelMeterProperty)
+	if err != nil {
+		return 0, false
+	}
+
+	values, ok := metadata.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+
+	text, ok := values["lavfi.astats.Overall.RMS_level"].(string)
+	if !ok {
+		return 0, false
+	}
+
+	level, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return level, true
+}
+
+// Aspect returns the aspect ratio the video is displayed with.
+func (m *MPV) Aspect() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.aspect == "" {
+		return aspectRatios[0]
+	}
+
+	return m.aspect
+}
+
+// SetAspect sets the aspect ratio the video is displayed with. The aspect
+// ratio is also applied when MPV is relaunched after it has exited abruptly.
+func (m *MPV) SetAspect(aspect string) {
+	m.lock.Lock()
+	m.aspect = aspect
+	m.lock.Unlock()
+
+	m.Set("video-aspect-override", aspect)
+}
+
+// CycleAspect cycles the aspect ratio the video is displayed with
+// between the video's own, 16:9 and 4:3, and returns the new aspect ratio.
+func (m *MPV) CycleAspect() string {
+	aspect := aspectRatios[0]
+
+	current := m.Aspect()
+	for i, ratio := range aspectRatios {
+		if ratio == current {
+			aspect = aspectRatios[(i+1)%len(aspectRatios)]
+			break
+		}
+	}
+
+	m.SetAspect(aspect)
+
+	return aspect
+}
+
+// Zoom returns the video zoom level.
+func (m *MPV) Zoom() float64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.zoom
+}
+
+// SetZoom sets the video zoom level. The zoom level is also
+// applied when MPV is relaunched after it has exited abruptly.
+func (m *MPV) SetZoom(zoom float64) {
+	zoom = math.Max(-maxZoom, math.Min(maxZoom, zoom))
+
+	m.lock.Lock()
+	m.zoom = zoom
+	m.lock.Unlock()
+
+	m.Set("video-zoom", zoom)
+}
+
+// ChangeZoom changes the video zoom level by the provided step,
+// and returns the new zoom level.
+func (m *MPV) ChangeZoom(step float64) float64 {
+	m.SetZoom(m.Zoom() + step)
+
+	return m.Zoom()
+}
+
+// CacheSecs returns the number of seconds which are cached ahead.
+func (m *MPV) CacheSecs() int {
+	secs, err := m.Get("cache-secs")
+	if err != nil {
+		return 0
+	}
+
+	value, _ := secs.(float64)
+
+	return int(value)
+}
+
+// SetCacheSecs sets the number of seconds to cache ahead, up to MaxCacheSecs.
+// The value is also applied when MPV is relaunched after it has exited abruptly.
+// Values below 1 are ignored.
+func (m *MPV) SetCacheSecs(secs int) {
+	if secs < 1 {
+		return
+	}
+	if secs > MaxCacheSecs {
+		secs = MaxCacheSecs
+	}
+
+	m.lock.Lock()
+	m.cacheSecs = secs
+	m.lock.Unlock()
+
+	m.Set("cache-secs", secs)
+}
+
+// Screenshot saves the current video frame to the provided path.
+func (m *MPV) Screenshot(path string) error {
+	if _, err := m.Call("screenshot-to-file", path); err != nil {
+		utils.LogErrorf("MPV: Unable to save screenshot to %s: %v", path, err)
+		return fmt.Errorf("MPV: Unable to save screenshot")
+	}
+
+	return nil
+}
+
+// Muted returns whether playback is muted.
+func (m *MPV) Muted() bool {
+	mute, err := m.property("mute")
+	if err != nil {
+		return false
+	}
+
+	return propertyBool(mute, false)
+}
+
+// ToggleMuted toggles muting of the playback.
+func (m *MPV) ToggleMuted() {
+	m.Call("cycle", "mute")
+}
+
+// ToggleVolumeMute sets the volume to zero and stores the current volume,
+// or restores the stored volume if the volume was set to zero previously.
+// Unlike ToggleMuted, this does not use MPV's mute property.
+func (m *MPV) ToggleVolumeMute() {
+	if premute := m.PreMuteVolume(); premute > 0 {
+		m.SetVolume(premute)
+		m.PreMuteVolume(0)
+
+		return
+	}
+
+	volume := m.Volume()
+	if volume <= 0 {
+		return
+	}
+
+	m.PreMuteVolume(volume)
+	m.Set("volume", 0)
+}
+
+// PreMuteVolume returns the volume stored by ToggleVolumeMute.
+// If set is provided, the stored volume is set to it.
+// A value of 0 indicates that the volume is not muted.
+func (m *MPV) PreMuteVolume(set ...int) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if set != nil {
+		m.premute = set[0]
+	}
+
+	return m.premute
+}
+
+// LoopMode returns the current loop setting
+// Either of loop-file (R-F), loop-playlist (R-P), or nothing.
+func (m *MPV) LoopMode() string {
+	lf, err := m.property("loop-file")
+	if err != nil {
+		return ""
+	}
+
+	lp, err := m.property("loop-playlist")
+	if err != nil {
+		return ""
+	}
+
+	if loopEnabled(lf) {
+		return "loop-file"
+	}
+
+	if loopEnabled(lp) {
+		return "loop-playlist"
+	}
+
+	return ""
+}
+
+// loopEnabled returns whether the provided value of a loop property
+// enables looping. MPV returns false if looping is disabled, "inf"
+// for infinite looping, or the number of times to loop.
+func loopEnabled(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+
+	case string:
+		return v == "yes" || v == "inf"
+
+	case float64:
+		return v > 0
+	}
+
+	return false
+}
+
+// ToggleLoopMode toggles the loop mode between none, loop-file and loop-playlist.
+func (m *MPV) ToggleLoopMode() {
+	switch m.LoopMode() {
+	case "":
+		m.Set("loop-file", "yes")
+		m.Set("loop-playlist", "no")
+
+	case "loop-file":
+		m.Set("loop-file", "no")
+		m.Set("loop-playlist", "yes")
+
+	case "loop-playlist":
+		m.Set("loop-file", "no")
+		m.Set("loop-playlist", "no")
+	}
+}
+
+// Idle returns if the player is idle.
+func (m *MPV) Idle() bool {
+	idle, err := m.Get("core-idle")
+	if err != nil {
+		return false
+	}
+
+	return propertyBool(idle, false)
+}
+
+// Finished returns if the playback has finished.
+func (m *MPV) Finished() bool {
+	eof, err := m.property("eof-reached")
+	if err != nil {
+		return false
+	}
+
+	return propertyBool(eof, false)
+}
+
+// Buffering returns if the player is buffering.
+func (m *MPV) Buffering() bool {
+	buf, err := m.property("paused-for-cache")
+	if err != nil {
+		return true
+	}
+
+	return propertyBool(buf, true)
+}
+
+// Volume returns the volume.
+func (m *MPV) Volume() int {
+	vol, err := m.property("volume")
+	if err != nil {
+		return -1
+	}
+
+	volume, ok := propertyFloat(vol)
+	if !ok {
+		return -1
+	}
+
+	return int(volume)
+}
+
+// defaultVolumeMax is MPV's default maximum volume, which is
+// used if the maximum volume cannot be retrieved.
+const defaultVolumeMax = 130
+
+// SetVolume sets the volume, clamped to the range allowed by MPV.
+func (m *MPV) SetVolume(volume int) {
+	limit := defaultVolumeMax
+
+	if vmax, err := m.Get("volume-max"); err == nil {
+		if v, ok := propertyFloat(vmax); ok {
+			limit = int(v)
+		}
+	}
+
+	if volume < 0 {
+		volume = 0
+	} else if volume > limit {
+		volume = limit
+	}
+
+	m.Set("volume", volume)
+}
+
+// VolumeIncrease increments the volume by 1.
+func (m *MPV) VolumeIncrease() {
+	vol := m.Volume()
+	if vol == -1 {
+		return
+	}
+
+	m.Set("volume", vol+1)
+}
+
+// VolumeDecrease decreases the volume by 1.
+func (m *MPV) VolumeDecrease() {
+	vol := m.Volume()
+	if vol == -1 {
+		return
+	}
 
 	m.Set("volume", vol-1)
 }
 
-// QueueCount returns the total number of tracks within the queue.
-func (m *MPV) QueueCount() int {
-	count, err := m.Get("playlist-count")
-	if err != nil {
-		return 0
+// QueueCount returns the total number of tracks within the queue.
+func (m *MPV) QueueCount() int {
+	count, err := m.Get("playlist-count")
+	if err != nil {
+		return 0
+	}
+
+	total, _ := propertyFloat(count)
+
+	return int(total)
+}
+
+// QueuePosition returns the position of the current track within the queue.
+// If no track is playing, or the position cannot be retrieved, -1 is returned.
+func (m *MPV) QueuePosition() int {
+	pos, err := m.Get("playlist-playing-pos")
+	if err != nil {
+		return -1
+	}
+
+	position, ok := propertyFloat(pos)
+	if !ok {
+		return -1
+	}
+
+	return int(position)
+}
+
+// QueueDelete removes the track number from the queue.
+func (m *MPV) QueueDelete(number int) {
+	m.load.Lock()
+	defer m.load.Unlock()
+
+	ids := m.playlistIDs()
+
+	m.Call("playlist-remove", number)
+
+	if number >= 0 && number < len(ids) {
+		m.lock.Lock()
+		delete(m.monitor, ids[number])
+		m.lock.Unlock()
+	}
+}
+
+// QueueMove moves the position of the track.
+func (m *MPV) QueueMove(before, after int) {
+	m.Call("playlist-move", after, before)
+}
+
+// QueueSwitchToTrack switches playback to the provided track number.
+func (m *MPV) QueueSwitchToTrack(number int) {
+	m.Set("playlist-pos", number)
+}
+
+// QueueData returns the current playlist data from MPV.
+func (m *MPV) QueueData() string {
+	list, err := m.Call("get_property_string", "playlist")
+	if err != nil {
+		return ""
+	}
+
+	data, _ := list.(string)
+
+	return data
+}
+
+// QueuePlayLatest plays the latest track entry in the queue.
+func (m *MPV) QueuePlayLatest() {
+	m.Set("playlist-pos", m.QueueCount()-1)
+
+	m.Play()
+}
+
+// QueueClear clears the queue.
+func (m *MPV) QueueClear() {
+	m.load.Lock()
+	defer m.load.Unlock()
+
+	m.Call("playlist-clear")
+
+	m.clearMonitor()
+
+	m.lock.Lock()
+	m.unshuffled = nil
+	m.lock.Unlock()
+}
+
+// WaitClosed waits for MPV to exit.
+func (m *MPV) WaitClosed() {
+	m.connection().WaitUntilClosed()
+}
+
+// Call send a command to MPV.
+func (m *MPV) Call(args ...interface{}) (interface{}, error) {
+	conn := m.connection()
+	if conn == nil || conn.IsClosed() {
+		return nil, fmt.Errorf("MPV: Connection closed")
+	}
+
+	return conn.Call(args...)
+}
+
+// Get gets a property from the mpv instance.
+func (m *MPV) Get(prop string) (interface{}, error) {
+	conn := m.connection()
+	if conn == nil || conn.IsClosed() {
+		return nil, fmt.Errorf("MPV: Connection closed")
+	}
+
+	value, err := conn.Get(prop)
+	if err != nil {
+		utils.LogDebugf("MPV: Cannot get property %s: %v", prop, err)
+	}
+
+	return value, err
+}
+
+// Set sets a property in the mpv instance.
+func (m *MPV) Set(prop string, value interface{}) error {
+	conn := m.connection()
+	if conn == nil || conn.IsClosed() {
+		return fmt.Errorf("MPV: Connection closed")
+	}
+
+	return conn.Set(prop, value)
+}
+
+// connection returns the current connection to MPV.
+func (m *MPV) connection() *mpvipc.Connection {
+	m.conn.RLock()
+	defer m.conn.RUnlock()
+
+	return m.Connection
+}
+
+// connect launches MPV and returns a new connection via the socket.
+func (m *MPV) connect() (*mpvipc.Connection, error) {
+	command := exec.Command(m.execpath, m.launchArgs()...)
+
+	utils.LogInfof("MPV: Starting %s", m.execpath)
+
+	if err := command.Start(); err != nil {
+		utils.LogErrorf("MPV: Could not start %s: %v", m.execpath, err)
+
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("MPV: %s was not found, set its path with the 'mpv-path' option", m.execpath)
+		}
+
+		return nil, fmt.Errorf("MPV: Could not start %s", m.execpath)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		command.Wait()
+		close(exited)
+	}()
+
+	m.command, m.exited = command, exited
+
+	retries, _ := strconv.Atoi(m.numretries)
+	delay, err := time.ParseDuration(m.retrydelay)
+	if err != nil {
+		delay = time.Second
+	}
+
+	conn := mpvipc.NewConnection(m.socket)
+
+	err = m.openSocket(conn.Open, exited, retries, delay)
+	switch {
+	case err == nil:
+		utils.LogInfof("MPV: Connected to socket %s", m.socket)
+		return conn, nil
+
+	case errors.Is(err, errExited):
+		utils.LogErrorf("MPV: %s exited before its socket %s was available", m.execpath, m.socket)
+		return nil, fmt.Errorf("MPV: %s exited on startup, check the 'mpv-args' option and the log file", m.execpath)
+	}
+
+	command.Process.Kill()
+
+	utils.LogErrorf("MPV: Could not connect to socket %s", m.socket)
+
+	return nil, fmt.Errorf("MPV: Socket %s did not come up after %d attempts, try increasing 'num-retries'", m.socket, retries+1)
+}
+
+// openSocket attempts to open the connection to the socket until it is opened, MPV exits
+// or the retries are exhausted. The delay between the attempts is backed off after every
+// attempt, and the progress of the attempts is reported via ConnectProgress.
+func (m *MPV) openSocket(open func() error, exited <-chan struct{}, retries int, delay time.Duration) error {
+	err := fmt.Errorf("MPV: Socket %s is not available", m.socket)
+
+	for attempt := 1; attempt <= retries+1; attempt++ {
+		if ConnectProgress != nil {
+			ConnectProgress(attempt, retries+1)
+		}
+
+		if err = open(); err == nil {
+			return nil
+		}
+
+		utils.LogDebugf("MPV: Cannot connect to socket %s (attempt %d): %v", m.socket, attempt, err)
+
+		if attempt > retries {
+			break
+		}
+
+		if !m.delay(backoffDelay(delay, attempt), exited) {
+			return errExited
+		}
+	}
+
+	return err
+}
+
+// delay waits for the provided delay, and returns false if
+// MPV exits, i.e. the exited channel is closed, in the meantime.
+func (m *MPV) delay(delay time.Duration, exited <-chan struct{}) bool {
+	if m.wait != nil {
+		return m.wait(delay, exited)
+	}
+
+	select {
+	case <-exited:
+		return false
+
+	case <-time.After(delay):
+	}
+
+	return true
+}
+
+// launchArgs returns the arguments to launch MPV with.
+func (m *MPV) launchArgs() []string {
+	args := []string{
+		"--no-terminal",
+		"--really-quiet",
+		"--no-input-terminal",
+		"--user-agent=" + m.useragent,
+		"--script-opts=ytdl_hook-ytdl_path=" + m.ytdlpath,
+	}
+
+	m.lock.Lock()
+	if m.hwdec != "" {
+		args = append(args, "--hwdec="+m.hwdec)
+	}
+	if m.audio != "" {
+		args = append(args, "--audio-device="+m.audio)
+	}
+	if m.levelMeter {
+		args = append(args, "--af-add="+levelMeterFilter)
+	}
+	if m.aspect != "" {
+		args = append(args, "--video-aspect-override="+m.aspect)
+	}
+	if m.zoom != 0 {
+		args = append(args, "--video-zoom="+strconv.FormatFloat(m.zoom, 'f', -1, 64))
+	}
+	if m.cacheSecs > 0 {
+		args = append(args, "--cache-secs="+strconv.Itoa(m.cacheSecs))
+	}
+	m.lock.Unlock()
+
+	// Since MPV applies the last value provided for an option, the essential
+	// options are provided last, so that they always take precedence.
+	args = append(append(args, m.args...),
+		"--idle",
+		"--keep-open",
+		"--input-ipc-server="+m.socket,
+	)
+
+	return args
+}
+
+// backoffDelay returns the delay before the next attempt to connect to the socket,
+// which is doubled after every attempt starting from the provided initial delay,
+// up to the maximum delay.
+func backoffDelay(initial time.Duration, attempt int) time.Duration {
+	limit := maxRetryDelay
+	if initial > limit {
+		return initial
+	}
+
+	delay := initial
+	for i := 1; i < attempt && delay < limit; i++ {
+		delay *= 2
+	}
+
+	if delay > limit {
+		delay = limit
 	}
 
-	return int(count.(float64))
+	return delay
 }
 
-// QueuePosition returns the position of the current track within the queue.
-func (m *MPV) QueuePosition() int {
-	pos, err := m.Get("playlist-playing-pos")
-	if err != nil {
-		return 0
+// property returns the cached value of the provided property if it is observed,
+// or retrieves it from MPV otherwise.
+func (m *MPV) property(name string) (interface{}, error) {
+	m.lock.Lock()
+	value, ok := m.properties[name]
+	m.lock.Unlock()
+
+	if !ok {
+		return m.Get(name)
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("MPV: Property %s is unavailable", name)
 	}
 
-	return int(pos.(float64))
+	return value, nil
 }
 
-// QueueDelete removes the track number from the queue.
-func (m *MPV) QueueDelete(number int) {
-	m.Call("playlist-remove", number)
+// setProperty caches the provided value of an observed property.
+// If the value is nil, the property is marked as unavailable.
+// The pause state is kept after the cache is cleared, so that
+// it can be restored if MPV is relaunched.
+func (m *MPV) setProperty(name string, value interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.properties == nil {
+		m.properties = make(map[string]interface{})
+	}
+
+	m.properties[name] = value
+
+	if paused, ok := value.(bool); ok && name == "pause" {
+		m.paused = paused
+	}
 }
 
-// QueueMove moves the position of the track.
-func (m *MPV) QueueMove(before, after int) {
-	m.Call("playlist-move", after, before)
+// clearProperties clears the cached property values.
+func (m *MPV) clearProperties() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.properties = nil
 }
 
-// QueueSwitchToTrack switches playback to the provided track number.
-func (m *MPV) QueueSwitchToTrack(number int) {
-	m.Set("playlist-pos", number)
+// setKeybindings unbinds the MPV keys that close the player.
+func (m *MPV) setKeybindings() {
+	m.Call("keybind", "q", "")
+	m.Call("keybind", "Ctrl+q", "")
+	m.Call("keybind", "Shift+q", "")
 }
 
-// QueueData returns the current playlist data from MPV.
-func (m *MPV) QueueData() string {
-	list, err := m.Call("get_property_string", "playlist")
-	if err != nil {
-		return ""
+// restore loads the provided playlist into MPV via the provided connection,
+// adds its tracks to the error monitor, and switches to the track at the provided
+// position with the provided pause state. If the track at the position cannot be
+// loaded, the next track which was loaded is switched to instead. It must be
+// called with the load lock held.
+func (m *MPV) restore(conn *mpvipc.Connection, playlist []string, pos int, paused bool) {
+	added, target := 0, -1
+
+	for i, entry := range playlist {
+		title, options := entryOptions(utils.GetDataFromURL(entry))
+
+		if _, err := conn.Call("loadfile", entry, "append", options); err != nil {
+			utils.LogWarnf("MPV: Unable to restore %s: %v", entry, err)
+			continue
+		}
+
+		m.addToMonitor(conn, title)
+
+		if i >= pos && target < 0 {
+			target = added
+		}
+
+		added++
 	}
 
-	return list.(string)
-}
+	if added == 0 {
+		return
+	}
 
-// QueuePlayLatest plays the latest track entry in the queue.
-func (m *MPV) QueuePlayLatest() {
-	m.Set("playlist-pos", m.QueueCount()-1)
+	if target < 0 {
+		target = 0
+	}
 
-	m.Play()
-}
+	pause := "no"
+	if paused {
+		pause = "yes"
+	}
 
-// QueueClear clears the queue.
-func (m *MPV) QueueClear() {
-	m.Call("playlist-clear")
+	conn.Set("playlist-pos", target)
+	conn.Set("pause", pause)
 
-	m.clearMonitor()
+	select {
+	case Events.FileLoadedEvent <- struct{}{}:
+
+	default:
+	}
 }
 
-// WaitClosed waits for MPV to exit.
-func (m *MPV) WaitClosed() {
-	m.Connection.WaitUntilClosed()
+// playlistItem describes an entry parsed from a playlist,
+// along with the data to load it with.
+type playlistItem struct {
+	file string
+	data url.Values
 }
 
-// Call send a command to MPV.
-func (m *MPV) Call(args ...interface{}) (interface{}, error) {
-	if m.Exited() {
-		return nil, fmt.Errorf("MPV: Connection closed")
+// parsePlaylist returns the entries of the provided playlist, whose local paths
+// are resolved relative to the provided playlist directory.
+//
+// We implement a simple playlist parser instead of relying on
+// the m3u8 package here, since that package deals with mainly
+// HLS playlists, and it seems to panic when certain EXTINF fields
+// are blank. With this method, we can parse the URLs from the playlist
+// directly, and pass the relevant options to mpv as well.
+//
+// Entries which were generated by invidtui are rewritten to point to the
+// current instance, while entries from external playlists (local files,
+// or URLs to other hosts) are passed to mpv as-is, with the titles from
+// their EXTINF headers.
+func parsePlaylist(r io.Reader, dir string) ([]playlistItem, error) {
+	var extinf string
+	var entries []playlistItem
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#EXTINF:") {
+			if i := strings.Index(line, ","); i >= 0 {
+				extinf = strings.TrimSpace(line[i+1:])
+			}
+
+			continue
+		}
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+
+		entry, data, ok := playlistEntry(line, dir)
+		if !ok {
+			extinf = ""
+			continue
+		}
+
+		if data.Get("title") == "" && extinf != "" {
+			data.Set("title", extinf)
+		}
+		extinf = ""
+
+		entries = append(entries, playlistItem{file: entry, data: data})
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return nil, err
 	}
 
-	return m.Connection.Call(args...)
+	return entries, nil
 }
 
-// Get gets a property from the mpv instance.
-func (m *MPV) Get(prop string) (interface{}, error) {
-	if m.Exited() {
-		return nil, fmt.Errorf("MPV: Connection closed")
+// playlistEntry returns the entry to be loaded into mpv and its data,
+// for the provided playlist line. Local paths are resolved relative to
+// the provided playlist directory, and only the hosts of entries generated
+// by invidtui are replaced with the current instance.
+func playlistEntry(line, dir string) (string, url.Values, bool) {
+	if uri, err := url.Parse(line); err != nil || uri.Scheme == "" || uri.Scheme == "file" {
+		path := line
+		if err == nil && uri.Scheme == "file" {
+			path = uri.Path
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			return "", nil, false
+		}
+
+		return path, url.Values{}, true
 	}
 
-	return m.Connection.Get(prop)
-}
+	lineURI, err := utils.IsValidURL(line)
+	if err != nil {
+		return "", nil, false
+	}
 
-// Set sets a property in the mpv instance.
-func (m *MPV) Set(prop string, value interface{}) error {
-	if m.Exited() {
-		return fmt.Errorf("MPV: Connection closed")
+	data := lineURI.Query()
+	if data.Get("id") == "" || data.Get("mediatype") == "" {
+		values := url.Values{"title": []string{data.Get("title")}}
+		for _, key := range []string{"start", "end"} {
+			if v := data.Get(key); v != "" {
+				values.Set(key, v)
+			}
+		}
+
+		return line, values, true
 	}
 
-	return m.Connection.Set(prop, value)
+	lineURI.Host = utils.GetHostname(client.Instance())
+
+	return lineURI.String(), data, true
 }
 
-// connect launches MPV and starts a new connection via the provided socket.
-func (m *MPV) connect(mpvpath, ytdlpath, numretries, useragent, socket string) error {
-	command := exec.Command(
-		mpvpath,
-		"--idle",
-		"--keep-open",
-		"--no-terminal",
-		"--really-quiet",
-		"--no-input-terminal",
-		"--user-agent="+useragent,
-		"--input-ipc-server="+socket,
-		"--script-opts=ytdl_hook-ytdl_path="+ytdlpath,
-	)
+// entryOptions returns the title and the options to load a playlist entry with,
+// which are stored within the query parameters of the entry's URL.
+func entryOptions(data url.Values) (string, string) {
+	var title, options string
 
-	if err := command.Start(); err != nil {
-		return fmt.Errorf("MPV: Could not start")
+	if t := data.Get("title"); t != "" {
+		title = t
+	}
+	if o := data.Get("options"); o != "" {
+		options = replaceOptions(o)
 	}
 
-	conn := mpvipc.NewConnection(socket)
-	retries, _ := strconv.Atoi(numretries)
-	for i := 0; i <= retries; i++ {
-		err := conn.Open()
-		if err != nil {
-			time.Sleep(1 * time.Second)
-			continue
-		}
-
-		m.socket = socket
-		m.Connection = conn
+	if title != "" && !strings.Contains(options, "force-media-title") {
+		options += ",force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
+	}
 
-		return nil
+	for _, key := range []string{"start", "end"} {
+		if v := data.Get(key); v != "" && !strings.Contains(options, ","+key+"=") {
+			if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+				options += "," + key + "=" + v
+			}
+		}
 	}
 
-	return fmt.Errorf("MPV: Could not connect to socket")
+	return title, options
 }
 
 // startMonitor starts monitoring MPV for error events.
 func (m *MPV) startMonitor() {
-	for id := range Events.ErrorNumber {
+	for track := range Events.ErrorNumber {
 		m.lock.Lock()
 
-		title := m.monitor[id]
-		delete(m.monitor, id)
+		title := m.monitor[track.ID]
+		delete(m.monitor, track.ID)
 
 		m.lock.Unlock()
 
+		// Restricted tracks are reported with a specific error via
+		// the failed event, so the generic error is not shown for them.
+		if !track.Restricted {
+			select {
+			case Events.ErrorEvent <- title:
+			default:
+			}
+		}
+
 		select {
-		case Events.ErrorEvent <- title:
+		case Events.FailedEvent <- track:
 		default:
 		}
 	}
@@ -568,29 +1736,63 @@ func (m *MPV) clearMonitor() {
 	m.monitor = make(map[int]string)
 }
 
-// addMonitor adds a track to be monitored for errors.
-func (m *MPV) addToMonitor(title string) {
-	select {
-	case id := <-Events.FileNumber:
-		m.lock.Lock()
-		defer m.lock.Unlock()
+// addToMonitor adds the last track in the queue of the provided connection to be
+// monitored for errors. It must be called with the load lock held, right after the
+// track is appended, so that the last track in the queue is the one which was appended.
+// Since the playlist entry IDs are unique, the track is correlated with its error events
+// regardless of whether it is moved within the queue or has started playing.
+func (m *MPV) addToMonitor(conn *mpvipc.Connection, title string) {
+	count, err := conn.Get("playlist-count")
+	if err != nil {
+		return
+	}
+
+	last, ok := propertyFloat(count)
+	if !ok || last < 1 {
+		return
+	}
 
-		m.monitor[id] = title
+	value, err := conn.Get("playlist/" + strconv.Itoa(int(last)-1) + "/id")
+	if err != nil {
+		return
+	}
 
-	default:
+	id, ok := propertyFloat(value)
+	if !ok {
+		return
 	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.monitor[int(id)] = title
 }
 
 // eventListener listens for MPV events.
 //
 //gocyclo:ignore
 func (m *MPV) eventListener() {
-	events, stopListening := m.Connection.NewEventListener()
+	conn := m.connection()
+	events, stopListening := conn.NewEventListener()
 
-	defer m.Connection.Close()
+	defer conn.Close()
+	defer m.clearProperties()
 	defer func() { stopListening <- struct{}{} }()
 
+	var seconds int64 = -1
+	var restricted bool
+	var failures []string
+	playing := -1
+
+	// The errors from the ytdl hook and the HTTP errors of the stream are
+	// only reported as log messages, so they are requested to detect why a
+	// track failed to load. HTTP errors are logged as warnings by FFmpeg.
+	m.Call("request_log_messages", "warn")
 	m.Call("observe_property", 1, "playlist")
+	m.Call("observe_property", 2, "eof-reached")
+	for i, property := range observedProperties {
+		m.Call("observe_property", i+3, property)
+	}
 
 	//lint:ignore S1000 because for-range over the events channel blocks.
 	for {
@@ -610,38 +1812,250 @@ func (m *MPV) eventListener() {
 						}
 					}
 
+					m.savePlaylist(pldata)
+
 					Events.DataEvent <- pldata
 
 					break
 				}
 			}
 
+			// Since the player is started with the 'keep-open' option,
+			// the last track in the queue does not send an 'end-file'
+			// event, so we check if the end of the track has been reached.
+			if event.ID == 2 {
+				m.setProperty("eof-reached", event.Data)
+
+				if eof, ok := event.Data.(bool); ok && eof {
+					sendFileEndEvent(m.currentID())
+				}
+
+				break
+			}
+
+			if index := int(event.ID) - 3; index >= 0 && index < len(observedProperties) {
+				m.setProperty(observedProperties[index], event.Data)
+
+				if observedProperties[index] == levelMeterProperty {
+					break
+				}
+
+				// The playback time changes many times per second, so
+				// the player is only updated when the second changes.
+				if observedProperties[index] == "playback-time" {
+					pos, ok := event.Data.(float64)
+					if ok && int64(pos) == seconds {
+						break
+					}
+
+					seconds = int64(pos)
+				}
+
+				sendPropertyEvent()
+
+				break
+			}
+
 			switch event.Name {
 			case "start-file":
+				if id, ok := propertyFloat(event.ExtraData["playlist_entry_id"]); ok {
+					playing, seconds, restricted, failures = int(id), -1, false, nil
+				}
+
 				m.Set("pause", "yes")
 				m.Set("pause", "no")
 
-				if len(event.ExtraData) > 0 {
-					val := event.ExtraData["playlist_entry_id"]
-
-					Events.FileNumber <- int(val.(float64))
-				}
-
 			case "end-file":
 				if len(event.ExtraData) > 0 {
-					err := event.ExtraData["file_error"]
-					val := event.ExtraData["playlist_entry_id"]
+					err, _ := event.ExtraData["file_error"].(string)
+					id, ok := propertyFloat(event.ExtraData["playlist_entry_id"])
+
+					if err != "" && ok {
+						track := FailedTrack{ID: int(id), Error: err}
+						if track.ID == playing {
+							if seconds > 0 {
+								track.Position = seconds
+							}
+
+							track.Restricted = restricted
+							if failures != nil {
+								track.Error += ": " + strings.Join(failures, ", ")
+							}
+						}
 
-					if err != nil && val != nil {
-						if err.(string) != "" {
-							Events.ErrorNumber <- int(val.(float64))
+						Events.ErrorNumber <- track
+					}
+
+					// The reason is a named field of the event,
+					// so it is not included in its extra data.
+					if event.Reason == "eof" {
+						if !ok {
+							id = -1
 						}
+
+						sendFileEndEvent(int(id))
 					}
 				}
 
 			case "file-loaded":
 				Events.FileLoadedEvent <- struct{}{}
+
+			case "log-message":
+				if event.Prefix == "ytdl_hook" && event.Level == "error" {
+					restricted = restricted || isRestrictedError(event.Text)
+				}
+				if isHTTPError(event.Text) {
+					failures = append(failures, strings.TrimSpace(event.Text))
+				}
+
+			case "client-message":
+				sendEnqueueEvent(event.ExtraData["args"])
 			}
 		}
 	}
 }
+
+// isRestrictedError returns whether the provided error message indicates
+// that a video is age-restricted or requires signing in.
+func isRestrictedError(text string) bool {
+	text = strings.ToLower(text)
+
+	for _, restriction := range restrictedErrors {
+		if strings.Contains(text, restriction) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isHTTPError returns whether the provided log message reports an HTTP error
+// response, like "HTTP error 403 Forbidden", while opening or reading a stream.
+func isHTTPError(text string) bool {
+	text = strings.ToLower(text)
+
+	return strings.Contains(text, "http error") || strings.Contains(text, "server returned")
+}
+
+// sendFileEndEvent sends an event when a track has finished playing.
+func sendFileEndEvent(id int) {
+	select {
+	case Events.FileEndEvent <- id:
+
+	default:
+	}
+}
+
+// sendPropertyEvent sends an event when an observed property has changed.
+func sendPropertyEvent() {
+	select {
+	case Events.PropertyEvent <- struct{}{}:
+
+	default:
+	}
+}
+
+// sendEnqueueEvent parses the provided client message arguments,
+// and sends an enqueue event if it is an enqueue request.
+func sendEnqueueEvent(data interface{}) {
+	args, ok := data.([]interface{})
+	if !ok || len(args) != 3 || args[0] != enqueueMessage {
+		return
+	}
+
+	mediatype, _ := args[1].(string)
+	uri, _ := args[2].(string)
+	if uri == "" {
+		return
+	}
+
+	select {
+	case Events.EnqueueEvent <- EnqueueRequest{URL: uri, Audio: mediatype == "audio"}:
+
+	default:
+	}
+}
+
+// savePlaylist stores the filenames and the current position
+// of the provided playlist data, to restore it on reconnection.
+func (m *MPV) savePlaylist(pldata []map[string]interface{}) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.playlistPos = -1
+	m.playlist = make([]string, 0, len(pldata))
+
+	for i, data := range pldata {
+		filename, ok := data["filename"].(string)
+		if !ok {
+			continue
+		}
+
+		if current, ok := data["current"].(bool); ok && current {
+			m.playlistPos = i
+		}
+
+		m.playlist = append(m.playlist, filename)
+	}
+}
+
+// sendReconnectEvent sends the reconnection attempt number.
+// If the attempt number is 0, MPV has reconnected.
+func sendReconnectEvent(attempt int) {
+	select {
+	case Events.ReconnectEvent <- attempt:
+
+	default:
+	}
+}
+
+// propertyFloat converts the provided property value to a float64.
+// Since the type of a property value may differ between MPV versions,
+// numeric and string values are converted, and false is returned
+// if the value cannot be converted.
+func propertyFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+
+	case float32:
+		return float64(v), true
+
+	case int:
+		return float64(v), true
+
+	case int64:
+		return float64(v), true
+
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+// propertyBool converts the provided property value to a bool.
+// If the value cannot be converted, the provided default is returned.
+func propertyBool(value interface{}, def bool) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+
+	case string:
+		switch v {
+		case "yes", "true":
+			return true
+
+		case "no", "false":
+			return false
+		}
+
+	default:
+		if f, ok := propertyFloat(v); ok {
+			return f != 0
+		}
+	}
+
+	return def
+}
@@ -0,0 +1,1058 @@
+package mediaplayer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darkhz/mpvipc"
+)
+
+// fakeMPV is a minimal MPV IPC server, which keeps a playlist and
+// a set of properties, and replies to the commands used by the player.
+type fakeMPV struct {
+	listener net.Listener
+	conns    []net.Conn
+
+	playlist []fakeEntry
+	nextID   int
+	pos      int
+	props    map[string]interface{}
+	fail     map[string]bool
+	calls    map[string]int
+
+	mutex, write sync.Mutex
+}
+
+// fakeEntry describes an entry in the playlist of the fake MPV server.
+type fakeEntry struct {
+	filename, options string
+	id                int
+}
+
+// newFakeMPV starts a fake MPV server, and returns an MPV instance connected to it.
+func newFakeMPV(t testing.TB) (*MPV, *fakeMPV) {
+	dir, err := os.MkdirTemp("", "invidtui-mpv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	socket := filepath.Join(dir, "socket")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeMPV{
+		listener: listener,
+		nextID:   1,
+		pos:      -1,
+		props:    make(map[string]interface{}),
+		fail:     make(map[string]bool),
+		calls:    make(map[string]int),
+	}
+
+	go f.serve()
+
+	t.Cleanup(func() {
+		listener.Close()
+		os.RemoveAll(dir)
+	})
+
+	return &MPV{monitor: make(map[int]string), Connection: f.connect(t, socket)}, f
+}
+
+// connect returns a new connection to the fake MPV server at the provided socket.
+func (f *fakeMPV) connect(t testing.TB, socket string) *mpvipc.Connection {
+	conn := mpvipc.NewConnection(socket)
+	if err := conn.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	return conn
+}
+
+// serve accepts connections to the fake MPV server.
+func (f *fakeMPV) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		f.mutex.Lock()
+		f.conns = append(f.conns, conn)
+		f.mutex.Unlock()
+
+		go f.handle(conn)
+	}
+}
+
+// handle replies to the commands sent via the provided connection.
+func (f *fakeMPV) handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var request struct {
+			Command []interface{} `json:"command"`
+			ID      uint          `json:"request_id"`
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil {
+			continue
+		}
+
+		data, status := f.command(request.Command)
+
+		reply, _ := json.Marshal(map[string]interface{}{
+			"request_id": request.ID,
+			"error":      status,
+			"data":       data,
+		})
+
+		f.send(conn, reply)
+	}
+}
+
+// send sends the provided message via the provided connection.
+func (f *fakeMPV) send(conn net.Conn, message []byte) {
+	f.write.Lock()
+	defer f.write.Unlock()
+
+	conn.Write(append(message, '\n'))
+}
+
+// emit sends the provided event to all the connections.
+func (f *fakeMPV) emit(event map[string]interface{}) {
+	message, _ := json.Marshal(event)
+
+	f.mutex.Lock()
+	conns := append([]net.Conn(nil), f.conns...)
+	f.mutex.Unlock()
+
+	for _, conn := range conns {
+		f.send(conn, message)
+	}
+}
+
+// command runs the provided command, and returns its result and status.
+func (f *fakeMPV) command(args []interface{}) (interface{}, string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	name, _ := args[0].(string)
+	f.calls[name]++
+
+	switch name {
+	case "get_property":
+		return f.get(args[1].(string))
+
+	case "get_property_string":
+		if args[1] != "playlist" {
+			return nil, "property unavailable"
+		}
+
+		entries := make([]map[string]interface{}, 0, len(f.playlist))
+		for _, entry := range f.playlist {
+			entries = append(entries, map[string]interface{}{"filename": entry.filename, "id": entry.id})
+		}
+
+		data, _ := json.Marshal(entries)
+
+		return string(data), "success"
+
+	case "set_property":
+		prop := args[1].(string)
+		if prop == "playlist-pos" {
+			f.pos = int(args[2].(float64))
+		}
+
+		f.props[prop] = args[2]
+
+	case "loadfile":
+		file := args[1].(string)
+		if f.fail[file] {
+			return nil, "error running command"
+		}
+
+		var options string
+		if len(args) > 3 {
+			options, _ = args[3].(string)
+		}
+
+		f.playlist = append(f.playlist, fakeEntry{filename: file, options: options, id: f.nextID})
+		f.nextID++
+
+		if args[2] == "append-play" && f.pos < 0 {
+			f.pos = len(f.playlist) - 1
+		}
+
+	case "playlist-clear":
+		if f.pos < 0 {
+			f.playlist = nil
+			break
+		}
+
+		f.playlist = []fakeEntry{f.playlist[f.pos]}
+		f.pos = 0
+
+	case "playlist-remove":
+		index, ok := args[1].(float64)
+		if !ok || int(index) < 0 || int(index) >= len(f.playlist) {
+			return nil, "invalid parameter"
+		}
+
+		f.playlist = append(f.playlist[:int(index)], f.playlist[int(index)+1:]...)
+
+		switch {
+		case int(index) == f.pos:
+			f.pos = -1
+
+		case int(index) < f.pos:
+			f.pos--
+		}
+	}
+
+	return nil, "success"
+}
+
+// get returns the value of the provided property.
+func (f *fakeMPV) get(prop string) (interface{}, string) {
+	switch {
+	case prop == "playlist-count":
+		return float64(len(f.playlist)), "success"
+
+	case prop == "playlist-pos", prop == "playlist-playing-pos":
+		if f.pos < 0 {
+			return nil, "property unavailable"
+		}
+
+		return float64(f.pos), "success"
+
+	case strings.HasPrefix(prop, "playlist/") && strings.HasSuffix(prop, "/id"):
+		index, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(prop, "playlist/"), "/id"))
+		if err != nil || index < 0 || index >= len(f.playlist) {
+			return nil, "property unavailable"
+		}
+
+		return float64(f.playlist[index].id), "success"
+	}
+
+	if value, ok := f.props[prop]; ok {
+		return value, "success"
+	}
+
+	return nil, "property unavailable"
+}
+
+// filenames returns the filenames in the playlist of the fake MPV server.
+func (f *fakeMPV) filenames() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	filenames := make([]string, 0, len(f.playlist))
+	for _, entry := range f.playlist {
+		filenames = append(filenames, entry.filename)
+	}
+
+	return filenames
+}
+
+func TestQueuePosition(t *testing.T) {
+	var closed MPV
+	if pos := closed.QueuePosition(); pos != -1 {
+		t.Errorf("QueuePosition() without a connection = %d, want -1", pos)
+	}
+
+	m, f := newFakeMPV(t)
+
+	if pos := m.QueuePosition(); pos != -1 {
+		t.Errorf("QueuePosition() with an empty queue = %d, want -1", pos)
+	}
+
+	f.mutex.Lock()
+	f.playlist = []fakeEntry{{filename: "a", id: 1}, {filename: "b", id: 2}}
+	f.pos = 1
+	f.mutex.Unlock()
+
+	if pos := m.QueuePosition(); pos != 1 {
+		t.Errorf("QueuePosition() = %d, want 1", pos)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	entry := func(title string) string {
+		return "https://example.com/" + title + "?title=" + title
+	}
+
+	tests := []struct {
+		name      string
+		failed    []string
+		pos       int
+		paused    bool
+		wantFiles []string
+		wantPos   float64
+		wantPause string
+	}{
+		{
+			name:      "all restored",
+			pos:       2,
+			wantFiles: []string{entry("a"), entry("b"), entry("c"), entry("d")},
+			wantPos:   2,
+			wantPause: "no",
+		},
+		{
+			name:      "paused",
+			pos:       1,
+			paused:    true,
+			wantFiles: []string{entry("a"), entry("b"), entry("c"), entry("d")},
+			wantPos:   1,
+			wantPause: "yes",
+		},
+		{
+			name:      "failure before the position",
+			failed:    []string{entry("a")},
+			pos:       2,
+			wantFiles: []string{entry("b"), entry("c"), entry("d")},
+			wantPos:   1,
+			wantPause: "no",
+		},
+		{
+			name:      "failure at the position",
+			failed:    []string{entry("b"), entry("c")},
+			pos:       1,
+			paused:    true,
+			wantFiles: []string{entry("a"), entry("d")},
+			wantPos:   1,
+			wantPause: "yes",
+		},
+		{
+			name:      "failure at the last position",
+			failed:    []string{entry("d")},
+			pos:       3,
+			wantFiles: []string{entry("a"), entry("b"), entry("c")},
+			wantPos:   0,
+			wantPause: "no",
+		},
+		{
+			name:      "no position",
+			pos:       -1,
+			wantFiles: []string{entry("a"), entry("b"), entry("c"), entry("d")},
+			wantPos:   0,
+			wantPause: "no",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, f := newFakeMPV(t)
+
+			for _, failed := range test.failed {
+				f.fail[failed] = true
+			}
+
+			playlist := []string{entry("a"), entry("b"), entry("c"), entry("d")}
+
+			m.load.Lock()
+			m.restore(m.Connection, playlist, test.pos, test.paused)
+			m.load.Unlock()
+
+			files := f.filenames()
+			if strings.Join(files, " ") != strings.Join(test.wantFiles, " ") {
+				t.Errorf("restored %v, want %v", files, test.wantFiles)
+			}
+
+			f.mutex.Lock()
+			pos, pause := f.props["playlist-pos"], f.props["pause"]
+			entries := f.playlist
+			f.mutex.Unlock()
+
+			if pos != test.wantPos {
+				t.Errorf("playlist-pos = %v, want %v", pos, test.wantPos)
+			}
+			if pause != test.wantPause {
+				t.Errorf("pause = %v, want %v", pause, test.wantPause)
+			}
+
+			if len(m.monitor) != len(entries) {
+				t.Errorf("monitoring %d tracks, want %d", len(m.monitor), len(entries))
+			}
+			for _, e := range entries {
+				if title := m.monitor[e.id]; e.filename != entry(title) {
+					t.Errorf("entry %d is monitored as %q, want the title of %s", e.id, title, e.filename)
+				}
+			}
+		})
+	}
+}
+
+func TestPausedStateKept(t *testing.T) {
+	var m MPV
+
+	m.setProperty("pause", true)
+	m.clearProperties()
+
+	if !m.paused {
+		t.Error("pause state was not kept after the properties were cleared")
+	}
+
+	m.setProperty("pause", nil)
+	if !m.paused {
+		t.Error("pause state was changed by an unavailable value")
+	}
+
+	m.setProperty("pause", false)
+	if m.paused {
+		t.Error("pause state was not updated")
+	}
+}
+
+func TestPropertyCache(t *testing.T) {
+	m, f := newFakeMPV(t)
+
+	f.mutex.Lock()
+	f.props["volume"] = float64(40)
+	f.props["mute"] = true
+	f.mutex.Unlock()
+
+	if value, err := m.property("volume"); err != nil || value != float64(40) {
+		t.Errorf("uncached property = %v, %v, want 40", value, err)
+	}
+	if calls := f.calls["get_property"]; calls != 1 {
+		t.Errorf("uncached property queried MPV %d times, want 1", calls)
+	}
+
+	m.setProperty("volume", float64(80))
+	m.setProperty("mute", nil)
+
+	if value, err := m.property("volume"); err != nil || value != float64(80) {
+		t.Errorf("cached property = %v, %v, want 80", value, err)
+	}
+	if value, err := m.property("mute"); err == nil {
+		t.Errorf("unavailable property = %v, want an error", value)
+	}
+	if calls := f.calls["get_property"]; calls != 1 {
+		t.Errorf("cached properties queried MPV %d times, want 1", calls-1)
+	}
+
+	m.clearProperties()
+
+	if value, err := m.property("volume"); err != nil || value != float64(40) {
+		t.Errorf("property after clearing the cache = %v, %v, want 40", value, err)
+	}
+	if calls := f.calls["get_property"]; calls != 2 {
+		t.Errorf("property after clearing the cache queried MPV %d times, want 1", calls-1)
+	}
+}
+
+func TestObservedAudioProperties(t *testing.T) {
+	m, f := newFakeMPV(t)
+
+	if device := m.CurrentAudioDevice(); device != "" {
+		t.Errorf("CurrentAudioDevice() without a device = %q", device)
+	}
+	if level, ok := m.AudioLevel(); ok {
+		t.Errorf("AudioLevel() without the filter = %v", level)
+	}
+
+	f.mutex.Lock()
+	calls := f.calls["get_property"]
+	f.mutex.Unlock()
+
+	m.setProperty("audio-device", "pulse/sink")
+	m.setProperty(levelMeterProperty, map[string]interface{}{
+		"lavfi.astats.Overall.RMS_level": "-20.5",
+	})
+
+	for i := 0; i < 10; i++ {
+		if device := m.CurrentAudioDevice(); device != "pulse/sink" {
+			t.Fatalf("CurrentAudioDevice() = %q, want pulse/sink", device)
+		}
+		if level, ok := m.AudioLevel(); !ok || level != -20.5 {
+			t.Fatalf("AudioLevel() = %v, %v, want -20.5", level, ok)
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.calls["get_property"] != calls {
+		t.Errorf("observed properties queried MPV %d times", f.calls["get_property"]-calls)
+	}
+
+	m.setProperty(levelMeterProperty, map[string]interface{}{
+		"lavfi.astats.Overall.RMS_level": "-inf",
+	})
+	if level, ok := m.AudioLevel(); !ok || !math.IsInf(level, -1) {
+		t.Errorf("AudioLevel() of silence = %v, %v, want -Inf", level, ok)
+	}
+}
+
+func BenchmarkProperty(b *testing.B) {
+	b.Run("cached", func(b *testing.B) {
+		m, _ := newFakeMPV(b)
+		m.setProperty("volume", float64(50))
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			m.property("volume")
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		m, f := newFakeMPV(b)
+
+		f.mutex.Lock()
+		f.props["volume"] = float64(50)
+		f.mutex.Unlock()
+
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			m.property("volume")
+		}
+	})
+}
+
+func TestPropertyFloat(t *testing.T) {
+	tests := []struct {
+		value  interface{}
+		want   float64
+		wantOK bool
+	}{
+		{value: float64(1.5), want: 1.5, wantOK: true},
+		{value: float32(2.5), want: 2.5, wantOK: true},
+		{value: 3, want: 3, wantOK: true},
+		{value: int64(-4), want: -4, wantOK: true},
+		{value: "5.25", want: 5.25, wantOK: true},
+		{value: "", want: 0, wantOK: false},
+		{value: "abc", want: 0, wantOK: false},
+		{value: nil, want: 0, wantOK: false},
+		{value: true, want: 0, wantOK: false},
+		{value: []interface{}{1.0}, want: 0, wantOK: false},
+		{value: map[string]interface{}{"a": 1.0}, want: 0, wantOK: false},
+	}
+
+	for _, test := range tests {
+		got, ok := propertyFloat(test.value)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("propertyFloat(%#v) = %v, %v, want %v, %v", test.value, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestPropertyBool(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		def   bool
+		want  bool
+	}{
+		{value: true, def: false, want: true},
+		{value: false, def: true, want: false},
+		{value: "yes", def: false, want: true},
+		{value: "true", def: false, want: true},
+		{value: "no", def: true, want: false},
+		{value: "false", def: true, want: false},
+		{value: "1", def: false, want: false},
+		{value: "maybe", def: true, want: true},
+		{value: "maybe", def: false, want: false},
+		{value: "", def: true, want: true},
+		{value: float64(1), def: false, want: true},
+		{value: float64(0), def: true, want: false},
+		{value: 2, def: false, want: true},
+		{value: nil, def: true, want: true},
+		{value: nil, def: false, want: false},
+		{value: []interface{}{true}, def: false, want: false},
+		{value: map[string]interface{}{}, def: true, want: true},
+	}
+
+	for _, test := range tests {
+		if got := propertyBool(test.value, test.def); got != test.want {
+			t.Errorf("propertyBool(%#v, %v) = %v, want %v", test.value, test.def, got, test.want)
+		}
+	}
+}
+
+func TestLoopEnabled(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{value: true, want: true},
+		{value: false, want: false},
+		{value: "inf", want: true},
+		{value: "yes", want: true},
+		{value: "no", want: false},
+		{value: float64(3), want: true},
+		{value: float64(0), want: false},
+		{value: nil, want: false},
+		{value: []interface{}{}, want: false},
+	}
+
+	for _, test := range tests {
+		if got := loopEnabled(test.value); got != test.want {
+			t.Errorf("loopEnabled(%#v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestLaunchArgs(t *testing.T) {
+	m := &MPV{
+		socket:    "/tmp/socket",
+		useragent: "agent",
+		ytdlpath:  "yt-dlp",
+		hwdec:     "auto",
+		cacheSecs: 30,
+		args:      []string{"--include=/tmp/mpv.conf", "--hwdec=no", "--volume=50"},
+	}
+
+	args := m.launchArgs()
+
+	index := func(arg string) int {
+		for i, a := range args {
+			if a == arg {
+				return i
+			}
+		}
+
+		t.Fatalf("argument %s is missing: %v", arg, args)
+
+		return -1
+	}
+
+	essential := args[len(args)-len(EssentialArgs):]
+	for i, arg := range []string{"--idle", "--keep-open", "--input-ipc-server=/tmp/socket"} {
+		if essential[i] != arg {
+			t.Errorf("essential argument %d = %s, want %s: %v", i, essential[i], arg, args)
+		}
+	}
+
+	if index("--hwdec=auto") > index("--hwdec=no") {
+		t.Errorf("provided arguments do not override the defaults: %v", args)
+	}
+	if index("--include=/tmp/mpv.conf") > index("--volume=50") {
+		t.Errorf("provided arguments are reordered: %v", args)
+	}
+
+	index("--cache-secs=30")
+	index("--user-agent=agent")
+}
+
+// TestConcurrentLoads loads entries into the queue from many goroutines while
+// entries are removed and the queue is cleared, and checks that every monitored
+// track is in the queue with its title. It is meant to be run with -race.
+func TestConcurrentLoads(t *testing.T) {
+	const (
+		loaders = 8
+		entries = 25
+	)
+
+	m, f := newFakeMPV(t)
+
+	var wg sync.WaitGroup
+
+	for loader := 0; loader < loaders; loader++ {
+		wg.Add(1)
+
+		go func(loader int) {
+			defer wg.Done()
+
+			for entry := 0; entry < entries; entry++ {
+				title := strconv.Itoa(loader) + "-" + strconv.Itoa(entry)
+				if err := m.loadEntry("https://example.com/"+title, title, ""); err != nil {
+					t.Errorf("loadEntry(%s) returned error: %v", title, err)
+				}
+			}
+		}(loader)
+	}
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < entries; i++ {
+			m.QueueDelete(0)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 5; i++ {
+			m.QueueClear()
+		}
+	}()
+
+	wg.Wait()
+
+	f.mutex.Lock()
+	titles := make(map[int]string, len(f.playlist))
+	for _, entry := range f.playlist {
+		titles[entry.id] = strings.TrimPrefix(entry.filename, "https://example.com/")
+	}
+	f.mutex.Unlock()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for id, title := range m.monitor {
+		if want, ok := titles[id]; !ok || title != want {
+			t.Errorf("monitored track %d is %q, but the queue has %q (present: %v)", id, title, want, ok)
+		}
+	}
+
+	for id, title := range titles {
+		if _, ok := m.monitor[id]; !ok {
+			t.Errorf("track %d (%s) in the queue is not monitored", id, title)
+		}
+	}
+}
+
+func TestConcurrentLoadsWithoutChanges(t *testing.T) {
+	const loaders, entries = 8, 25
+
+	m, f := newFakeMPV(t)
+
+	var wg sync.WaitGroup
+
+	for loader := 0; loader < loaders; loader++ {
+		wg.Add(1)
+
+		go func(loader int) {
+			defer wg.Done()
+
+			for entry := 0; entry < entries; entry++ {
+				title := strconv.Itoa(loader) + "-" + strconv.Itoa(entry)
+				m.loadEntry("https://example.com/"+title, title, "")
+			}
+		}(loader)
+	}
+
+	wg.Wait()
+
+	if count := len(f.filenames()); count != loaders*entries {
+		t.Errorf("queue has %d entries, want %d", count, loaders*entries)
+	}
+	if count := len(m.monitor); count != loaders*entries {
+		t.Errorf("monitor has %d entries, want %d", count, loaders*entries)
+	}
+}
+
+// waitCalls waits until the fake MPV server has received the provided number of commands.
+func (f *fakeMPV) waitCalls(t *testing.T, name string, count int) {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		f.mutex.Lock()
+		calls := f.calls[name]
+		f.mutex.Unlock()
+
+		if calls >= count {
+			return
+		}
+	}
+
+	t.Fatalf("MPV did not receive %d %s commands", count, name)
+}
+
+func TestIsHTTPError(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{text: "https: HTTP error 403 Forbidden\n", want: true},
+		{text: "tcp: HTTP error 404 Not Found", want: true},
+		{text: "Server returned 403 Forbidden (access denied)", want: true},
+		{text: "Failed to recognize file format.", want: false},
+		{text: "", want: false},
+	}
+
+	for _, test := range tests {
+		if isHTTPError(test.text) != test.want {
+			t.Errorf("isHTTPError(%q) = %v, want %v", test.text, !test.want, test.want)
+		}
+	}
+}
+
+func TestFailedTrackError(t *testing.T) {
+	m, f := newFakeMPV(t)
+
+	Events.ErrorNumber = make(chan FailedTrack, 10)
+	Events.PropertyEvent = make(chan struct{}, 1)
+
+	go m.eventListener()
+	f.waitCalls(t, "observe_property", len(observedProperties)+2)
+
+	f.emit(map[string]interface{}{"event": "start-file", "playlist_entry_id": 1})
+	f.waitCalls(t, "set_property", 2)
+
+	f.emit(map[string]interface{}{"event": "property-change", "id": 3, "name": "playback-time", "data": 42.5})
+	select {
+	case <-Events.PropertyEvent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("playback time was not updated")
+	}
+
+	f.emit(map[string]interface{}{
+		"event": "log-message", "prefix": "ffmpeg", "level": "warn",
+		"text": "https: HTTP error 403 Forbidden\n",
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	f.emit(map[string]interface{}{
+		"event": "end-file", "reason": "error",
+		"file_error": "loading failed", "playlist_entry_id": 1,
+	})
+
+	select {
+	case track := <-Events.ErrorNumber:
+		want := FailedTrack{ID: 1, Position: 42, Error: "loading failed: https: HTTP error 403 Forbidden"}
+		if track != want {
+			t.Errorf("failed track = %+v, want %+v", track, want)
+		}
+
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed track was not reported")
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		initial time.Duration
+		want    []time.Duration
+	}{
+		{
+			initial: 100 * time.Millisecond,
+			want: []time.Duration{
+				100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+				800 * time.Millisecond, 1600 * time.Millisecond, maxRetryDelay, maxRetryDelay,
+			},
+		},
+		{
+			initial: 700 * time.Millisecond,
+			want:    []time.Duration{700 * time.Millisecond, 1400 * time.Millisecond, maxRetryDelay, maxRetryDelay},
+		},
+		{
+			initial: maxRetryDelay,
+			want:    []time.Duration{maxRetryDelay, maxRetryDelay},
+		},
+		{
+			initial: 5 * time.Second,
+			want:    []time.Duration{5 * time.Second, 5 * time.Second},
+		},
+	}
+
+	for _, test := range tests {
+		for i, want := range test.want {
+			if delay := backoffDelay(test.initial, i+1); delay != want {
+				t.Errorf("backoffDelay(%s, %d) = %s, want %s", test.initial, i+1, delay, want)
+			}
+		}
+	}
+}
+
+// fakeWait returns an MPV instance whose waits between connection attempts are
+// recorded instead of delayed, along with the slice to which the delays are appended.
+func fakeWait() (*MPV, *[]time.Duration) {
+	var delays []time.Duration
+
+	m := &MPV{
+		wait: func(delay time.Duration, exited <-chan struct{}) bool {
+			delays = append(delays, delay)
+
+			select {
+			case <-exited:
+				return false
+
+			default:
+			}
+
+			return true
+		},
+	}
+
+	return m, &delays
+}
+
+func TestOpenSocketBackoff(t *testing.T) {
+	const delay = 250 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		retries  int
+		failures int
+		wantErr  bool
+		want     []time.Duration
+	}{
+		{name: "first attempt", retries: 3, failures: 0, want: nil},
+		{
+			name: "doubled", retries: 5, failures: 3,
+			want: []time.Duration{delay, 2 * delay, 4 * delay},
+		},
+		{
+			name: "capped", retries: 6, failures: 6,
+			want: []time.Duration{delay, 2 * delay, 4 * delay, maxRetryDelay, maxRetryDelay, maxRetryDelay},
+		},
+		{
+			name: "retries exhausted", retries: 3, failures: 10, wantErr: true,
+			want: []time.Duration{delay, 2 * delay, 4 * delay},
+		},
+		{name: "no retries", retries: 0, failures: 1, wantErr: true, want: nil},
+		{name: "no attempts", retries: -1, failures: 0, wantErr: true, want: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts int
+			var progress [][2]int
+
+			m, delays := fakeWait()
+
+			ConnectProgress = func(attempt, total int) {
+				progress = append(progress, [2]int{attempt, total})
+			}
+			defer func() { ConnectProgress = nil }()
+
+			err := m.openSocket(func() error {
+				attempts++
+				if attempts <= test.failures {
+					return os.ErrNotExist
+				}
+
+				return nil
+			}, make(chan struct{}), test.retries, delay)
+
+			if (err != nil) != test.wantErr {
+				t.Fatalf("openSocket() error = %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(*delays, test.want) {
+				t.Errorf("delays = %v, want %v", *delays, test.want)
+			}
+
+			for i, p := range progress {
+				if p != [2]int{i + 1, test.retries + 1} {
+					t.Errorf("progress %d = %v, want [%d %d]", i, p, i+1, test.retries+1)
+				}
+			}
+			if len(progress) != attempts {
+				t.Errorf("reported %d attempts, made %d", len(progress), attempts)
+			}
+		})
+	}
+}
+
+func TestOpenSocketExited(t *testing.T) {
+	m, delays := fakeWait()
+
+	exited := make(chan struct{})
+	close(exited)
+
+	var attempts int
+
+	err := m.openSocket(func() error {
+		attempts++
+		return os.ErrNotExist
+	}, exited, 5, time.Second)
+
+	if !errors.Is(err, errExited) {
+		t.Errorf("openSocket() error = %v, want %v", err, errExited)
+	}
+	if attempts != 1 || len(*delays) != 1 {
+		t.Errorf("made %d attempts and waited %d times after exiting, want 1", attempts, len(*delays))
+	}
+}
+
+func TestDelayExited(t *testing.T) {
+	var m MPV
+
+	exited := make(chan struct{})
+	close(exited)
+
+	if m.delay(time.Hour, exited) {
+		t.Error("delay() did not stop when MPV exited")
+	}
+	if !m.delay(time.Millisecond, nil) {
+		t.Error("delay() stopped without MPV exiting")
+	}
+}
+
+func TestSetCacheSecs(t *testing.T) {
+	m, f := newFakeMPV(t)
+
+	tests := []struct {
+		secs, want int
+	}{
+		{secs: 120, want: 120},
+		{secs: MaxCacheSecs + 1, want: MaxCacheSecs},
+		{secs: 0, want: MaxCacheSecs},
+		{secs: -30, want: MaxCacheSecs},
+		{secs: 1, want: 1},
+	}
+
+	for _, test := range tests {
+		m.SetCacheSecs(test.secs)
+
+		if secs := m.CacheSecs(); secs != test.want {
+			t.Errorf("SetCacheSecs(%d): cache is %d seconds, want %d", test.secs, secs, test.want)
+		}
+
+		f.mutex.Lock()
+		value := f.props["cache-secs"]
+		f.mutex.Unlock()
+
+		if value != float64(test.want) {
+			t.Errorf("SetCacheSecs(%d): MPV cache-secs is %v, want %d", test.secs, value, test.want)
+		}
+	}
+}
+
+func TestSetVolume(t *testing.T) {
+	m, f := newFakeMPV(t)
+
+	volume := func() interface{} {
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+
+		return f.props["volume"]
+	}
+
+	tests := []struct {
+		volume    int
+		volumeMax interface{}
+		want      float64
+	}{
+		{volume: 80, want: 80},
+		{volume: 125, want: 125},
+		{volume: 200, want: defaultVolumeMax},
+		{volume: -10, want: 0},
+		{volume: 125, volumeMax: 100.0, want: 100},
+		{volume: 150, volumeMax: 150.0, want: 150},
+	}
+
+	for _, test := range tests {
+		f.mutex.Lock()
+		delete(f.props, "volume-max")
+		if test.volumeMax != nil {
+			f.props["volume-max"] = test.volumeMax
+		}
+		f.mutex.Unlock()
+
+		m.SetVolume(test.volume)
+
+		if v := volume(); v != test.want {
+			t.Errorf("SetVolume(%d) with maximum %v: volume is %v, want %v", test.volume, test.volumeMax, v, test.want)
+		}
+	}
+}
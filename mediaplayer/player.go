@@ -1,24 +1,40 @@
 package mediaplayer
 
+import "time"
+
+// Trim describes the positions, in seconds, at which the playback
+// of a file starts and ends. Positions which are zero are not set.
+type Trim struct {
+	Start, End int64
+}
+
 // MediaPlayer describes a media player.
 type MediaPlayer interface {
-	Init(execpath, ytdlpath, numretries, useragent, socket string) error
+	Init(execpath, ytdlpath, numretries, retrydelay, useragent, socket string, args ...string) error
 	Exit()
 	Exited() bool
+	Reconnect(retries int, delay time.Duration) error
 	SendQuit(socket string)
+	SendEnqueue(socket, uri string, audio bool) error
 
-	LoadFile(title string, duration int64, liveaudio bool, files ...string) error
+	LoadFile(title string, duration int64, liveaudio bool, trim Trim, files ...string) error
 	LoadPlaylist(plpath string, replace bool, renewLiveURL func(uri string, audio bool) bool) error
 
 	Title(pos int) string
 	MediaType() string
+	AudioCodec() string
+	VideoCodec() string
+	Bitrate() int64
 
 	Play()
 	Stop()
 	Next()
 	Prev()
+	SkipForward(n int)
+	SkipBackward(n int)
 	SeekForward()
 	SeekBackward()
+	SeekToPosition(position int64)
 	Position() int64
 	Duration() int64
 
@@ -27,9 +43,38 @@ type MediaPlayer interface {
 
 	Shuffled() bool
 	ToggleShuffled()
+	ReshuffleKeepingCurrent()
+	ShuffleWithSeed(seed int64)
+	Unshuffle()
+	IsShuffledWithSeed() bool
+
+	HWDec() string
+	SetHWDec(mode string)
+	ToggleHWDec() string
+
+	Aspect() string
+	SetAspect(aspect string)
+	CycleAspect() string
+	Zoom() float64
+	SetZoom(zoom float64)
+	ChangeZoom(step float64) float64
+
+	CacheSecs() int
+	SetCacheSecs(secs int)
+
+	AudioDevices() []AudioDevice
+	SetAudioDevice(name string)
+	CurrentAudioDevice() string
+
+	SetAudioLevelMeter(enabled bool) error
+	AudioLevel() (float64, bool)
+
+	Screenshot(path string) error
 
 	Muted() bool
 	ToggleMuted()
+	ToggleVolumeMute()
+	PreMuteVolume(set ...int) int
 
 	LoopMode() string
 	ToggleLoopMode()
@@ -39,6 +84,7 @@ type MediaPlayer interface {
 	Buffering() bool
 
 	Volume() int
+	SetVolume(volume int)
 	VolumeIncrease()
 	VolumeDecrease()
 
@@ -60,36 +106,82 @@ type MediaPlayer interface {
 
 // MediaEvents describes the various media player related events.
 type MediaEvents struct {
-	FileNumber, ErrorNumber chan int
-	ErrorEvent              chan string
-	FileLoadedEvent         chan struct{}
-	DataEvent               chan []map[string]interface{}
+	ErrorNumber     chan FailedTrack
+	ReconnectEvent  chan int
+	EnqueueEvent    chan EnqueueRequest
+	FailedEvent     chan FailedTrack
+	ErrorEvent      chan string
+	FileLoadedEvent chan struct{}
+	FileEndEvent    chan int
+	PropertyEvent   chan struct{}
+	DataEvent       chan []map[string]interface{}
+}
+
+// FailedTrack describes a track which failed to play, along with the position
+// in seconds up to which it was played before it failed. The error includes the
+// HTTP errors of the stream, if any. Restricted is set if the track failed because
+// it is age-restricted or requires signing in.
+type FailedTrack struct {
+	ID         int
+	Position   int64
+	Error      string
+	Restricted bool
+}
+
+// AudioDevice describes an audio output device.
+type AudioDevice struct {
+	Name        string
+	Description string
+}
+
+// EnqueueRequest describes a request from another instance to queue a URL.
+type EnqueueRequest struct {
+	URL   string
+	Audio bool
 }
 
 var (
 	current string
 	Events  MediaEvents
 
+	// ConnectProgress, if set, is called before each attempt
+	// to connect to the player, with the attempt number and
+	// the total number of attempts.
+	ConnectProgress func(attempt, attempts int)
+
 	players = map[string]MediaPlayer{
 		"mpv": &mpv,
 	}
 )
 
-// Init launches the provided player.
-func Init(player, execpath, ytdlpath, numretries, useragent, socket string) error {
+// Init launches the provided player. The provided arguments are passed to the player
+// in addition to the ones required by the application.
+func Init(player, execpath, ytdlpath, numretries, retrydelay, useragent, socket string, args ...string) error {
 	current = player
 
-	Events.FileNumber, Events.ErrorNumber = make(chan int, 100), make(chan int, 100)
+	Events.ErrorNumber = make(chan FailedTrack, 100)
 	Events.ErrorEvent = make(chan string, 100)
+	Events.ReconnectEvent = make(chan int, 10)
+	Events.EnqueueEvent = make(chan EnqueueRequest, 100)
+	Events.FailedEvent = make(chan FailedTrack, 100)
 	Events.FileLoadedEvent = make(chan struct{}, 100)
+	Events.FileEndEvent = make(chan int, 100)
+	Events.PropertyEvent = make(chan struct{}, 1)
 	Events.DataEvent = make(chan []map[string]interface{}, 10)
 
 	return players[player].Init(
 		execpath, ytdlpath,
-		numretries, useragent, socket,
+		numretries, retrydelay, useragent, socket,
+		args...,
 	)
 }
 
+// SendEnqueue sends a request to queue the provided URL to
+// an instance running the provided player at the socket.
+func SendEnqueue(player, socket, uri string, audio bool) error {
+	return players[player].SendEnqueue(socket, uri, audio)
+}
+
 // Player returns the currently selected player.
 func Player() MediaPlayer {
 	return players[current]
@@ -0,0 +1,283 @@
+package mediaplayer
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/darkhz/invidtui/client"
+)
+
+func TestEntryOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        url.Values
+		wantTitle   string
+		wantOptions string
+	}{
+		{
+			name:        "no data",
+			data:        nil,
+			wantTitle:   "",
+			wantOptions: "",
+		},
+		{
+			name: "video",
+			data: url.Values{
+				"title":   {"Video"},
+				"options": {"force-media-title=%5%Video,length=120"},
+			},
+			wantTitle:   "Video",
+			wantOptions: "force-media-title=%5%Video,length=120",
+		},
+		{
+			name: "audio",
+			data: url.Values{
+				"title":   {"Audio"},
+				"options": {"force-media-title=%5%Audio,length=120,vid=no"},
+			},
+			wantTitle:   "Audio",
+			wantOptions: "force-media-title=%5%Audio,length=120,vid=no",
+		},
+		{
+			name: "video with an audio file",
+			data: url.Values{
+				"title":   {"Video"},
+				"options": {"force-media-title=%5%Video,audio-file=%21%https://a.b/c?d=e,f=g"},
+			},
+			wantTitle:   "Video",
+			wantOptions: "force-media-title=%5%Video,audio-file=%21%https://a.b/c?d=e,f=g",
+		},
+		{
+			name:        "title without options",
+			data:        url.Values{"title": {"Ünïcode"}},
+			wantTitle:   "Ünïcode",
+			wantOptions: ",force-media-title=%9%Ünïcode",
+		},
+		{
+			name: "trim without options",
+			data: url.Values{
+				"title": {"Video"},
+				"start": {"10"},
+				"end":   {"20"},
+			},
+			wantTitle:   "Video",
+			wantOptions: ",force-media-title=%5%Video,start=10,end=20",
+		},
+		{
+			name: "trim already in options",
+			data: url.Values{
+				"title":   {"Video"},
+				"options": {"force-media-title=%5%Video,start=10,end=20"},
+				"start":   {"10"},
+				"end":     {"20"},
+			},
+			wantTitle:   "Video",
+			wantOptions: "force-media-title=%5%Video,start=10,end=20",
+		},
+		{
+			name: "invalid trim",
+			data: url.Values{
+				"title": {"Video"},
+				"start": {"abc"},
+			},
+			wantTitle:   "Video",
+			wantOptions: ",force-media-title=%5%Video",
+		},
+		{
+			name: "unsafe options",
+			data: url.Values{
+				"title":   {"Video"},
+				"options": {"force-media-title=%5%Video,run=rm,subprocess=ls"},
+			},
+			wantTitle:   "Video",
+			wantOptions: "force-media-title=%5%Video",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			title, options := entryOptions(test.data)
+
+			if title != test.wantTitle {
+				t.Errorf("title = %q, want %q", title, test.wantTitle)
+			}
+			if options != test.wantOptions {
+				t.Errorf("options = %q, want %q", options, test.wantOptions)
+			}
+		})
+	}
+}
+
+func TestPlaylistEntry(t *testing.T) {
+	client.SetHost("https://new.example.com")
+
+	dir := t.TempDir()
+
+	local := filepath.Join(dir, "local.mp3")
+	if err := os.WriteFile(local, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	invidtui := "https://old.example.com/latest_version?id=abc&itag=&local=true" +
+		"&title=Video&mediatype=Audio&options=" + url.QueryEscape("force-media-title=%5%Video,vid=no")
+
+	tests := []struct {
+		name      string
+		line      string
+		wantEntry string
+		wantTitle string
+		wantOK    bool
+	}{
+		{name: "relative path", line: "local.mp3", wantEntry: local, wantOK: true},
+		{name: "absolute path", line: local, wantEntry: local, wantOK: true},
+		{name: "file URL", line: "file://" + local, wantEntry: local, wantOK: true},
+		{name: "missing file", line: "missing.mp3", wantOK: false},
+		{
+			name:      "external URL",
+			line:      "https://example.org/watch?v=xyz&title=External",
+			wantEntry: "https://example.org/watch?v=xyz&title=External",
+			wantTitle: "External",
+			wantOK:    true,
+		},
+		{
+			name: "invidtui URL",
+			line: invidtui,
+			wantEntry: "https://new.example.com/latest_version?id=abc&itag=&local=true" +
+				"&title=Video&mediatype=Audio&options=" + url.QueryEscape("force-media-title=%5%Video,vid=no"),
+			wantTitle: "Video",
+			wantOK:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			entry, data, ok := playlistEntry(test.line, dir)
+			if ok != test.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, test.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			if entry != test.wantEntry {
+				t.Errorf("entry = %q, want %q", entry, test.wantEntry)
+			}
+			if title := data.Get("title"); title != test.wantTitle {
+				t.Errorf("title = %q, want %q", title, test.wantTitle)
+			}
+		})
+	}
+}
+
+func TestPlaylistEntryOptions(t *testing.T) {
+	client.SetHost("https://example.com")
+
+	tests := []struct {
+		name, line, want string
+	}{
+		{
+			name: "video",
+			line: "https://example.com/latest_version?id=abc&title=Video&mediatype=Video&options=" +
+				url.QueryEscape("force-media-title=%5%Video,length=60"),
+			want: "force-media-title=%5%Video,length=60",
+		},
+		{
+			name: "audio",
+			line: "https://example.com/latest_version?id=abc&title=Audio&mediatype=Audio&options=" +
+				url.QueryEscape("force-media-title=%5%Audio,length=60,vid=no"),
+			want: "force-media-title=%5%Audio,length=60,vid=no",
+		},
+		{
+			name: "external options are ignored",
+			line: "https://example.org/video?title=External&options=" + url.QueryEscape("run=rm") + "&start=5",
+			want: ",force-media-title=%8%External,start=5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, data, ok := playlistEntry(test.line, "")
+			if !ok {
+				t.Fatal("entry was not loaded")
+			}
+
+			if _, options := entryOptions(data); options != test.want {
+				t.Errorf("options = %q, want %q", options, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePlaylist(t *testing.T) {
+	client.SetHost("https://new.example.com")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "local.mp3"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	options := url.QueryEscape("force-media-title=%13%First & Video")
+	invidtui := "https://inv.example.com/latest_version?id=abc&itag=251&local=true&id=abc" +
+		"&title=First+%26+Video&author=Author&mediatype=Video&length=3%3A05&options=" + options
+
+	playlist := "#EXTM3U\n\n" +
+		"# Autogenerated by invidtui. DO NOT EDIT.\n\n" +
+		"#EXTINF:,First & Video\n" +
+		invidtui + "\n\n" +
+		"#EXTINF:3723,Second, Audio\n" +
+		"https://inv.example.com/latest_version?id=def&itag=251&local=true\n\n" +
+		"#EXTINF:0,Missing\n" +
+		"missing.mp3\n\n" +
+		"local.mp3\n" +
+		"  #EXTINF:-1 , Indented  \n" +
+		"  https://example.org/live.m3u8  \n"
+
+	entries, err := parsePlaylist(strings.NewReader(playlist), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		file, title, options string
+	}{
+		{
+			file:    strings.Replace(invidtui, "inv.example.com", "new.example.com", 1),
+			title:   "First & Video",
+			options: "force-media-title=%13%First & Video",
+		},
+		{
+			file:    "https://inv.example.com/latest_version?id=def&itag=251&local=true",
+			title:   "Second, Audio",
+			options: ",force-media-title=%13%Second, Audio",
+		},
+		{
+			file: filepath.Join(dir, "local.mp3"),
+		},
+		{
+			file:    "https://example.org/live.m3u8",
+			title:   "Indented",
+			options: ",force-media-title=%8%Indented",
+		},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("parsed %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+
+	for i, entry := range entries {
+		title, options := entryOptions(entry.data)
+
+		if entry.file != want[i].file {
+			t.Errorf("entry %d: file = %q, want %q", i, entry.file, want[i].file)
+		}
+		if title != want[i].title {
+			t.Errorf("entry %d: title = %q, want %q", i, title, want[i].title)
+		}
+		if options != want[i].options {
+			t.Errorf("entry %d: options = %q, want %q", i, options, want[i].options)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package mediaplayer
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/utils"
+)
+
+// executable describes an executable required by the player,
+// and the minimum version of it which is supported.
+type executable struct {
+	Name, Path, Option string
+	MinVersion         []int
+}
+
+// versionTimeout is the maximum time to wait for an executable to print its version.
+const versionTimeout = 10 * time.Second
+
+// versionRegex matches the dotted version numbers of an executable, like '0.35.1' or '2023.03.04'.
+var versionRegex = regexp.MustCompile(`\d+(?:\.\d+)+`)
+
+// preflight checks whether the provided executables exist, are executable,
+// and meet their minimum versions.
+func preflight(executables ...executable) error {
+	for _, exe := range executables {
+		if _, err := checkExecutable(exe); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkExecutable checks the provided executable and returns its resolved path.
+// If the executable is not found at its configured path, but is found in $PATH,
+// its location in $PATH is suggested in the returned error.
+func checkExecutable(exe executable) (string, error) {
+	path, err := exec.LookPath(exe.Path)
+	if err != nil {
+		message := fmt.Sprintf("%s was not found or is not executable", exe.Path)
+
+		if found, err := exec.LookPath(filepath.Base(exe.Path)); err == nil && found != exe.Path {
+			message += fmt.Sprintf(", but %s was found in $PATH; set the '%s' option to it", found, exe.Option)
+		}
+
+		return "", fmt.Errorf("%s: %s", exe.Name, message)
+	}
+
+	version, err := executableVersion(path)
+	if err != nil {
+		utils.LogWarnf("%s: Could not determine the version of %s: %v", exe.Name, path, err)
+		return path, nil
+	}
+
+	if compareVersions(version, exe.MinVersion) < 0 {
+		return "", fmt.Errorf(
+			"%s: %s has version %s, but at least version %s is required",
+			exe.Name, path, formatVersion(version), formatVersion(exe.MinVersion),
+		)
+	}
+
+	utils.LogInfof("%s: Using %s (version %s)", exe.Name, path, formatVersion(version))
+
+	return path, nil
+}
+
+// executableVersion returns the version printed by the executable at the provided path.
+func executableVersion(path string) ([]int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	line := strings.SplitN(string(output), "\n", 2)[0]
+
+	return parseVersion(line)
+}
+
+// parseVersion returns the first version number within the provided text.
+func parseVersion(text string) ([]int, error) {
+	match := versionRegex.FindString(text)
+	if match == "" {
+		return nil, fmt.Errorf("no version found")
+	}
+
+	parts := strings.Split(match, ".")
+	version := make([]int, len(parts))
+
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, err
+		}
+
+		version[i] = number
+	}
+
+	return version, nil
+}
+
+// compareVersions compares two versions, and returns a negative number if the first
+// version is lower, a positive number if it is higher, and zero if they are equal.
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		if x != y {
+			return x - y
+		}
+	}
+
+	return 0
+}
+
+// formatVersion returns the provided version in the dotted format.
+func formatVersion(version []int) string {
+	parts := make([]string, len(version))
+	for i, number := range version {
+		parts[i] = strconv.Itoa(number)
+	}
+
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,53 @@
+package mediaplayer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    []int
+		wantErr bool
+	}{
+		{text: "mpv 0.35.1 Copyright © 2000-2023 mpv/MPlayer/mplayer2 projects", want: []int{0, 35, 1}},
+		{text: "mpv v0.33.0-dirty", want: []int{0, 33, 0}},
+		{text: "2023.07.06", want: []int{2023, 7, 6}},
+		{text: "2021.12.17", want: []int{2021, 12, 17}},
+		{text: "mpv git-2023-01-01", wantErr: true},
+		{text: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		version, err := parseVersion(test.text)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseVersion(%q) error = %v, want error %v", test.text, err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(version, test.want) {
+			t.Errorf("parseVersion(%q) = %v, want %v", test.text, version, test.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b []int
+		want int
+	}{
+		{a: []int{0, 33, 0}, b: []int{0, 33, 0}, want: 0},
+		{a: []int{0, 33}, b: []int{0, 33, 0}, want: 0},
+		{a: []int{0, 32, 9}, b: []int{0, 33, 0}, want: -1},
+		{a: []int{0, 35, 1}, b: []int{0, 33, 0}, want: 1},
+		{a: []int{2021, 12, 17, 1}, b: []int{2021, 12, 17}, want: 1},
+		{a: []int{2021, 6, 6}, b: []int{2021, 12, 17}, want: -1},
+	}
+
+	for _, test := range tests {
+		result := compareVersions(test.a, test.b)
+		if result < 0 && test.want >= 0 || result > 0 && test.want <= 0 || result == 0 && test.want != 0 {
+			t.Errorf("compareVersions(%v, %v) = %d, want sign %d", test.a, test.b, result, test.want)
+		}
+	}
+}
@@ -10,8 +10,8 @@ import (
 
 // Application describes the layout of the app.
 type Application struct {
-	MenuLayout *tview.Flex
-	Menu, Tabs *tview.TextView
+	MenuLayout           *tview.Flex
+	Menu, Tabs, Instance *tview.TextView
 
 	Area           *tview.Pages
 	Pages          *tview.Pages
@@ -51,7 +51,7 @@ func Setup() {
 	UI.ColumnStyle = tcell.Style{}.
 		Attributes(tcell.AttrBold)
 
-	UI.Menu, UI.Tabs = tview.NewTextView(), tview.NewTextView()
+	UI.Menu, UI.Tabs, UI.Instance = tview.NewTextView(), tview.NewTextView(), tview.NewTextView()
 	UI.Menu.SetWrap(false)
 	UI.Menu.SetRegions(true)
 	UI.Tabs.SetWrap(false)
@@ -59,6 +59,10 @@ func Setup() {
 	UI.Tabs.SetDynamicColors(true)
 	UI.Menu.SetDynamicColors(true)
 	UI.Tabs.SetTextAlign(tview.AlignRight)
+	UI.Instance.SetWrap(false)
+	UI.Instance.SetDynamicColors(true)
+	UI.Instance.SetTextAlign(tview.AlignRight)
+	UI.Instance.SetBackgroundColor(tcell.ColorDefault)
 	UI.Menu.SetBackgroundColor(tcell.ColorDefault)
 	UI.Tabs.SetBackgroundColor(tcell.ColorDefault)
 	UI.Menu.SetHighlightedFunc(MenuHighlightHandler)
@@ -66,7 +70,8 @@ func Setup() {
 	UI.MenuLayout = tview.NewFlex().
 		SetDirection(tview.FlexColumn).
 		AddItem(UI.Menu, 0, 1, false).
-		AddItem(UI.Tabs, 0, 1, false)
+		AddItem(UI.Tabs, 0, 1, false).
+		AddItem(UI.Instance, 0, 0, false)
 	UI.MenuLayout.SetBackgroundColor(tcell.ColorDefault)
 
 	UI.Pages = tview.NewPages()
@@ -121,6 +126,18 @@ func SetPrimaryFocus() {
 	UI.SetFocus(UI.Pages)
 }
 
+// SetInstanceStatus displays the provided instance status beside the tabs.
+func SetInstanceStatus(status string) {
+	UI.Instance.SetText(status)
+
+	width := tview.TaggedStringWidth(status)
+	if width > 0 {
+		width++
+	}
+
+	UI.MenuLayout.ResizeItem(UI.Instance, width, 0)
+}
+
 // SetResizeHandler sets the resize handler for the app.
 func SetResizeHandler(resize func(screen tcell.Screen)) {
 	UI.resize = resize
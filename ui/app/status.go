@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/darkhz/invidtui/utils"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
@@ -220,7 +221,11 @@ func ShowInfo(text string, persist bool, print ...bool) {
 	UI.Status.InfoMessage(text, persist)
 }
 
-// ShowError shows an error message.
+// ShowError shows an error message, and writes it to the log.
 func ShowError(err error) {
+	if err != nil {
+		utils.LogErrorf("%s", err.Error())
+	}
+
 	UI.Status.ErrorMessage(err)
 }
@@ -1,6 +1,8 @@
 package menu
 
 import (
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/ui/player"
 	"github.com/darkhz/invidtui/ui/view"
@@ -33,6 +35,9 @@ func query(menuType string) bool {
 	case "History":
 		return !player.IsHistoryInputFocused()
 
+	case "Favorites":
+		return !player.IsFavoritesInputFocused()
+
 	case "Search":
 		return !searchInputFocused(menuType)
 	}
@@ -80,6 +85,13 @@ func isVideoOrPlaylist(menuType string) bool {
 	return isVideo(menuType) || isPlaylist(menuType)
 }
 
+func isMedia(menuType string) bool {
+	info, err := app.FocusedTableReference()
+
+	return err == nil &&
+		(isVideoOrPlaylist(menuType) || info.Type == "channel" && info.AuthorID != "")
+}
+
 func isDashboardFocused(menuType string) bool {
 	focused := view.Dashboard.IsFocused()
 	if focused {
@@ -118,14 +130,42 @@ func playerQueue(menuType string) bool {
 	return !player.IsQueueEmpty() && !player.IsQueueFocused()
 }
 
+func queueNotEmpty(menuType string) bool {
+	return !player.IsQueueEmpty()
+}
+
 func infoShown(menuType string) bool {
 	return isPlaying(menuType) && player.IsInfoShown()
 }
 
+func infoImageShown(menuType string) bool {
+	return infoShown(menuType) && player.IsImageEnabled()
+}
+
+func infoChannelAvailable(menuType string) bool {
+	return infoShown(menuType) && player.IsInfoChannelAvailable()
+}
+
 func isPlaying(menuType string) bool {
 	return player.IsPlayerShown()
 }
 
+func isShuffledWithSeed(menuType string) bool {
+	return player.IsPlayerShown() && mp.Player().IsShuffledWithSeed()
+}
+
+func trackHookSet(menuType string) bool {
+	return player.IsPlayerShown() && cmd.GetOptionValue("on-track-change") != ""
+}
+
+func debugEnabled(menuType string) bool {
+	return player.IsPlayerShown() && cmd.IsOptionEnabled("debug")
+}
+
+func instancesConfigured(menuType string) bool {
+	return cmd.Instances() != nil
+}
+
 func playlistAddTo(menuType string) bool {
 	return isVideo(menuType) && !view.Dashboard.IsFocused()
 }
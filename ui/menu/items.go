@@ -15,6 +15,7 @@ var Items = &app.MenuData{
 			cmd.KeyDownloadView,
 			cmd.KeyDownloadOptions,
 			cmd.KeyInstancesList,
+			cmd.KeyInstancesProbe,
 			cmd.KeyQuit,
 		},
 		cmd.KeyContextStart: {
@@ -91,10 +92,42 @@ var Items = &app.MenuData{
 			cmd.KeyPlayerOpenPlaylist,
 			cmd.KeyQueue,
 			cmd.KeyPlayerHistory,
+			cmd.KeyPlayerReplayLast,
+			cmd.KeyPlayerFavorites,
+			cmd.KeyPlayerToggleFavorite,
+			cmd.KeyPlayerToggleVisible,
+			cmd.KeyPlayerToggleQueue,
+			cmd.KeyPlayerAudioDevices,
+			cmd.KeyPlayerReload,
 			cmd.KeyPlayerInfo,
+			cmd.KeyPlayerNowPlaying,
+			cmd.KeyPlayerLyrics,
 			cmd.KeyPlayerInfoChangeQuality,
+			cmd.KeyPlayerInfoOpenLink,
+			cmd.KeyPlayerInfoCopyTitle,
+			cmd.KeyPlayerInfoOpenChannel,
+			cmd.KeyPlayerLayout,
+			cmd.KeyPlayerVolumeSet,
+			cmd.KeyPlayerCommand,
+			cmd.KeyPlayerAddToPlaylist,
+			cmd.KeyPlayerScreenshot,
+			cmd.KeyPlayerRunTrackHook,
+			cmd.KeyPlayerDebugInfo,
+			cmd.KeyPlayerShuffleSeed,
+			cmd.KeyPlayerLoopCount,
+			cmd.KeyPlayerUnshuffle,
+			cmd.KeyPlayerCopyURL,
+			cmd.KeyPlayerCopyURLTimestamp,
+			cmd.KeyPlayerOpenExternal,
+			cmd.KeyPlayerIncreaseCache,
 			cmd.KeyPlayerQueueAudio,
 			cmd.KeyPlayerQueueVideo,
+			cmd.KeyPlayerQueueNextAudio,
+			cmd.KeyPlayerQueueNextVideo,
+			cmd.KeyPlayerQueueAllAudio,
+			cmd.KeyPlayerQueueAllVideo,
+			cmd.KeyPlayerAttachAudio,
+			cmd.KeyPlayerQueueTrimmed,
 			cmd.KeyPlayerPlayAudio,
 			cmd.KeyPlayerPlayVideo,
 			cmd.KeyAudioURL,
@@ -106,10 +139,34 @@ var Items = &app.MenuData{
 			cmd.KeyQueueAppend,
 			cmd.KeyQueueDelete,
 			cmd.KeyQueueMove,
+			cmd.KeyQueueMoveUp,
+			cmd.KeyQueueMoveDown,
+			cmd.KeyQueueSearch,
+			cmd.KeyQueueRetryFailed,
+			cmd.KeyQueueToggleMediaType,
+			cmd.KeyQueueClearOthers,
+			cmd.KeyQueueRemoveDuplicates,
+			cmd.KeyQueueRemoveAbove,
+			cmd.KeyQueueRemoveBelow,
 			cmd.KeyClose,
 		},
 		cmd.KeyContextHistory: {
+			cmd.KeyHistoryPlay,
 			cmd.KeyQuery,
+			cmd.KeyHistoryFilterMedia,
+			cmd.KeyHistoryFilterDate,
+			cmd.KeyHistoryRemoveEntry,
+			cmd.KeyHistoryClear,
+			cmd.KeyHistoryExport,
+			cmd.KeyChannelVideos,
+			cmd.KeyChannelPlaylists,
+			cmd.KeyClose,
+		},
+		cmd.KeyContextFavorites: {
+			cmd.KeyFavoritesPlay,
+			cmd.KeyQuery,
+			cmd.KeyFavoritesRemove,
+			cmd.KeyFavoritesExport,
 			cmd.KeyChannelVideos,
 			cmd.KeyChannelPlaylists,
 			cmd.KeyClose,
@@ -135,11 +192,42 @@ var Items = &app.MenuData{
 		cmd.KeyDashboardReload:         isDashboardFocused,
 		cmd.KeyDashboardCreatePlaylist: createPlaylist,
 		cmd.KeyDashboardEditPlaylist:   editPlaylist,
+		cmd.KeyInstancesProbe:          instancesConfigured,
 		cmd.KeyQueue:                   playerQueue,
 		cmd.KeyPlayerInfo:              isPlaying,
-		cmd.KeyPlayerInfoChangeQuality: infoShown,
-		cmd.KeyPlayerQueueAudio:        isVideo,
-		cmd.KeyPlayerQueueVideo:        isVideo,
+		cmd.KeyPlayerNowPlaying:        isPlaying,
+		cmd.KeyPlayerLyrics:            isPlaying,
+		cmd.KeyPlayerInfoChangeQuality: infoImageShown,
+		cmd.KeyPlayerInfoOpenLink:      infoShown,
+		cmd.KeyPlayerInfoCopyTitle:     infoShown,
+		cmd.KeyPlayerInfoOpenChannel:   infoChannelAvailable,
+		cmd.KeyPlayerLayout:            isPlaying,
+		cmd.KeyPlayerVolumeSet:         isPlaying,
+		cmd.KeyPlayerCommand:           isPlaying,
+		cmd.KeyPlayerAddToPlaylist:     isPlaying,
+		cmd.KeyPlayerScreenshot:        isPlaying,
+		cmd.KeyPlayerReload:            isPlaying,
+		cmd.KeyPlayerToggleFavorite:    isPlaying,
+		cmd.KeyPlayerToggleVisible:     isPlaying,
+		cmd.KeyPlayerToggleQueue:       queueNotEmpty,
+		cmd.KeyPlayerAudioDevices:      isPlaying,
+		cmd.KeyPlayerRunTrackHook:      trackHookSet,
+		cmd.KeyPlayerDebugInfo:         debugEnabled,
+		cmd.KeyPlayerShuffleSeed:       isPlaying,
+		cmd.KeyPlayerLoopCount:         isPlaying,
+		cmd.KeyPlayerUnshuffle:         isShuffledWithSeed,
+		cmd.KeyPlayerCopyURL:           isPlaying,
+		cmd.KeyPlayerOpenExternal:      isPlaying,
+		cmd.KeyPlayerIncreaseCache:     isPlaying,
+		cmd.KeyPlayerCopyURLTimestamp:  isPlaying,
+		cmd.KeyPlayerQueueAudio:        isMedia,
+		cmd.KeyPlayerQueueVideo:        isMedia,
+		cmd.KeyPlayerQueueNextAudio:    isMedia,
+		cmd.KeyPlayerQueueNextVideo:    isMedia,
+		cmd.KeyPlayerQueueAllAudio:     isMedia,
+		cmd.KeyPlayerQueueAllVideo:     isMedia,
+		cmd.KeyPlayerAttachAudio:       isVideo,
+		cmd.KeyPlayerQueueTrimmed:      isVideo,
 		cmd.KeyPlayerPlayAudio:         isVideo,
 		cmd.KeyPlayerPlayVideo:         isVideo,
 	},
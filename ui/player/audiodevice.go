@@ -0,0 +1,103 @@
+package player
+
+import (
+	"fmt"
+
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// showAudioDevices shows a popup with the available audio output devices,
+// and switches to the selected device.
+func showAudioDevices() {
+	var modal *app.Modal
+	var width int
+
+	devices := mp.Player().AudioDevices()
+	if devices == nil {
+		app.ShowError(fmt.Errorf("Player: No audio devices found"))
+		return
+	}
+
+	current := mp.Player().CurrentAudioDevice()
+
+	table := tview.NewTable()
+	table.SetSelectorWrap(true)
+	table.SetSelectable(true, false)
+	table.SetBackgroundColor(tcell.ColorDefault)
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			row, _ := table.GetSelection()
+			if device, ok := table.GetCell(row, 0).GetReference().(mp.AudioDevice); ok {
+				setAudioDevice(device)
+			}
+
+			modal.Exit(false)
+
+		case tcell.KeyEscape:
+			modal.Exit(false)
+		}
+
+		return event
+	})
+	table.SetFocusFunc(func() {
+		app.SetContextMenu("", nil)
+	})
+
+	for row, device := range devices {
+		text := tview.Escape(device.Description)
+		if text == "" {
+			text = tview.Escape(device.Name)
+		}
+		if device.Name == current {
+			text += " [white::b](Selected)[-:-:-]"
+		}
+
+		if w := tview.TaggedStringWidth(text); w > width {
+			width = w
+		}
+
+		table.SetCell(row, 0, tview.NewTableCell(text).
+			SetReference(device).
+			SetTextColor(tcell.ColorBlue).
+			SetSelectedStyle(app.UI.SelectedStyle),
+		)
+
+		if device.Name == current {
+			table.Select(row, 0)
+		}
+	}
+
+	modal = app.NewModal("audio_devices", "Audio devices", table, len(devices)+4, width+4)
+	modal.Show(false)
+}
+
+// setAudioDevice switches the audio output to the provided device.
+func setAudioDevice(device mp.AudioDevice) {
+	mp.Player().SetAudioDevice(device.Name)
+
+	name := device.Description
+	if name == "" {
+		name = device.Name
+	}
+
+	app.ShowInfo("Player: Audio output set to "+name, false)
+	sendPlayerEvents()
+}
+
+// restoreAudioDevice switches the audio output to the provided saved device.
+// If the device is no longer available, the default device is kept.
+func restoreAudioDevice(name string) {
+	for _, device := range mp.Player().AudioDevices() {
+		if device.Name == name {
+			mp.Player().SetAudioDevice(name)
+			return
+		}
+	}
+
+	utils.LogWarnf("Player: Saved audio device %s is unavailable, using the default device", name)
+}
@@ -0,0 +1,76 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/darkhz/invidtui/client"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// autoplayNext queues and plays a video related to the track with the provided
+// playlist entry ID, if the autoplay mode is enabled, the queue is not looped,
+// and the track was the last track in the queue. Videos which are in the queue or in the play history are skipped.
+func autoplayNext(entry int) {
+	if !autoplayStatus() || mp.Player().LoopMode() != "" {
+		return
+	}
+
+	list := player.queue.getQueueData()
+	if len(list) == 0 || list[len(list)-1].ID != entry {
+		return
+	}
+
+	last := list[len(list)-1]
+	if last.VideoID == "" {
+		return
+	}
+
+	skip := make(map[string]struct{}, len(list))
+	for _, data := range list {
+		skip[data.VideoID] = struct{}{}
+	}
+
+	player.mutex.Lock()
+	for _, item := range player.history.entries {
+		skip[item.VideoID] = struct{}{}
+	}
+	player.mutex.Unlock()
+
+	go func(id string, audio bool) {
+		app.ShowInfo("Autoplay: Finding related videos", true)
+
+		related, err := inv.RelatedVideos(client.Ctx(), id)
+		if err != nil {
+			app.ShowError(fmt.Errorf("Autoplay: Cannot load related videos: %w", err))
+			return
+		}
+
+		for _, video := range related {
+			if _, ok := skip[video.VideoID]; ok || video.LengthSeconds == 0 {
+				continue
+			}
+
+			title, err := loadVideo(video.VideoID, audio, -1)
+			if err != nil {
+				app.ShowError(err)
+				return
+			}
+
+			go addToHistory(inv.SearchData{
+				Type:    "video",
+				Title:   title,
+				Author:  video.Author,
+				VideoID: video.VideoID,
+			}, audio)
+
+			mp.Player().QueuePlayLatest()
+			app.ShowInfo("Autoplay: Playing "+title, false)
+
+			return
+		}
+
+		app.ShowInfo("Autoplay: No related videos found", false)
+	}(last.VideoID, last.Type == "Audio")
+}
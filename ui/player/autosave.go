@@ -0,0 +1,108 @@
+package player
+
+import (
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// Autosave stores the state of the periodic saving of the settings and the queue,
+// so that the session is not lost if the application exits abruptly.
+type Autosave struct {
+	stop chan struct{}
+
+	// The mutex is held while the settings are being saved periodically,
+	// so that the saving is not interrupted by the exit-time saving.
+	mutex sync.Mutex
+}
+
+var autosave Autosave
+
+// startAutosave starts saving the settings and the queue periodically,
+// according to the 'autosave-interval' option.
+func startAutosave() {
+	interval := autosaveInterval(cmd.GetOptionValue("autosave-interval"))
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	ticker := time.NewTicker(interval)
+
+	autosave.stop = stop
+
+	go func() {
+		defer ticker.Stop()
+
+		runAutosave(ticker.C, stop, saveSession)
+	}()
+}
+
+// autosaveInterval returns the interval from the provided 'autosave-interval'
+// option value, or zero if it is invalid or periodic saving is disabled.
+func autosaveInterval(value string) time.Duration {
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0
+	}
+
+	return interval
+}
+
+// stopAutosave stops the periodic saving, and waits for an ongoing save to finish.
+func stopAutosave() {
+	if autosave.stop == nil {
+		return
+	}
+
+	close(autosave.stop)
+
+	autosave.mutex.Lock()
+	autosave.stop = nil
+	autosave.mutex.Unlock()
+}
+
+// runAutosave saves the session with the provided function at every tick, until it is stopped.
+func runAutosave(tick <-chan time.Time, stop chan struct{}, save func() error) {
+	for {
+		select {
+		case <-stop:
+			return
+
+		case <-tick:
+		}
+
+		autosave.mutex.Lock()
+
+		select {
+		case <-stop:
+			autosave.mutex.Unlock()
+			return
+
+		default:
+		}
+
+		err := save()
+
+		autosave.mutex.Unlock()
+
+		if err != nil {
+			utils.LogErrorf("Player: Unable to autosave settings: %v", err)
+			continue
+		}
+
+		utils.LogDebugf("Player: Autosaved settings")
+	}
+}
+
+// saveSession saves the queue and the settings.
+func saveSession() error {
+	saveQueue()
+
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	return cmd.StoreSettings()
+}
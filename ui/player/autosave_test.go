@@ -0,0 +1,124 @@
+package player
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAutosaveInterval(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "5m", want: 5 * time.Minute},
+		{value: "90s", want: 90 * time.Second},
+		{value: "0", want: 0},
+		{value: "-1m", want: 0},
+		{value: "5", want: 0},
+		{value: "", want: 0},
+	}
+
+	for _, test := range tests {
+		if interval := autosaveInterval(test.value); interval != test.want {
+			t.Errorf("autosaveInterval(%q) = %s, want %s", test.value, interval, test.want)
+		}
+	}
+}
+
+func TestRunAutosave(t *testing.T) {
+	tick := make(chan time.Time)
+	stop := make(chan struct{})
+	saved := make(chan struct{})
+	done := make(chan struct{})
+
+	var saves int
+
+	go func() {
+		defer close(done)
+
+		runAutosave(tick, stop, func() error {
+			saves++
+			saved <- struct{}{}
+
+			if saves == 2 {
+				return errors.New("save failed")
+			}
+
+			return nil
+		})
+	}()
+
+	// A failed save does not stop the periodic saving.
+	for i := 0; i < 3; i++ {
+		tick <- time.Now()
+		<-saved
+	}
+
+	close(stop)
+	<-done
+
+	if saves != 3 {
+		t.Errorf("saved %d times, want 3", saves)
+	}
+}
+
+func TestStopAutosaveWaits(t *testing.T) {
+	tick := make(chan time.Time)
+	stop := make(chan struct{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	autosave.stop = stop
+
+	var saves int
+
+	go func() {
+		defer close(done)
+
+		runAutosave(tick, stop, func() error {
+			saves++
+			close(started)
+			<-release
+
+			return nil
+		})
+	}()
+
+	tick <- time.Now()
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		stopAutosave()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("stopAutosave() returned during an ongoing save")
+
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopAutosave() did not return after the save finished")
+	}
+
+	<-done
+
+	if saves != 1 {
+		t.Errorf("saved %d times, want 1", saves)
+	}
+	if autosave.stop != nil {
+		t.Error("autosave was not reset after stopping")
+	}
+
+	// Stopping again without an autosave is a no-op.
+	stopAutosave()
+}
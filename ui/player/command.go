@@ -0,0 +1,252 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// playerCommand describes a command that can be run from the command line.
+type playerCommand struct {
+	usage string
+	run   func(args []string) error
+}
+
+// commands stores the commands that can be run from the command line.
+var commands = map[string]playerCommand{
+	"seek": {
+		usage: "seek [+|-]<seconds>",
+		run:   seekCommand,
+	},
+	"volume": {
+		usage: "volume [+|-]<percent>",
+		run:   volumeCommand,
+	},
+	"loop": {
+		usage: "loop none|file|playlist|once",
+		run:   loopCommand,
+	},
+	"shuffle": {
+		usage: "shuffle [seed]",
+		run:   shuffleCommand,
+	},
+	"unshuffle": {
+		usage: "unshuffle",
+		run: noArgs(func() {
+			mp.Player().Unshuffle()
+		}),
+	},
+	"play": {
+		usage: "play",
+		run: noArgs(func() {
+			if mp.Player().Paused() {
+				mp.Player().TogglePaused()
+			}
+		}),
+	},
+	"pause": {
+		usage: "pause",
+		run: noArgs(func() {
+			if !mp.Player().Paused() {
+				mp.Player().TogglePaused()
+			}
+		}),
+	},
+	"mute": {
+		usage: "mute",
+		run: noArgs(func() {
+			mp.Player().ToggleVolumeMute()
+		}),
+	},
+	"next": {
+		usage: "next",
+		run: noArgs(func() {
+			mp.Player().Next()
+		}),
+	},
+	"prev": {
+		usage: "prev",
+		run: noArgs(func() {
+			mp.Player().Prev()
+		}),
+	},
+	"stop": {
+		usage: "stop",
+		run: noArgs(func() {
+			stopPlayer()
+		}),
+	},
+	"close": {
+		usage: "close",
+		run: noArgs(func() {
+			closePlayer()
+		}),
+	},
+	"queue-clear": {
+		usage: "queue-clear",
+		run: noArgs(func() {
+			clearQueue()
+		}),
+	},
+}
+
+// commandInput displays an inputbox to enter a player command.
+func commandInput() {
+	app.UI.Status.SetInput(":", 0, true, func(text string) {
+		if err := runCommand(text); err != nil {
+			app.ShowError(err)
+			return
+		}
+
+		sendPlayerEvents()
+	}, nil)
+}
+
+// runCommand parses and runs the provided player command.
+func runCommand(text string) error {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(text), ":"))
+	if len(fields) == 0 {
+		return nil
+	}
+
+	command, ok := commands[fields[0]]
+	if !ok {
+		return fmt.Errorf("Player: Unknown command %s", fields[0])
+	}
+
+	if err := command.run(fields[1:]); err != nil {
+		return fmt.Errorf("Player: %s (usage: %s)", err.Error(), command.usage)
+	}
+
+	return nil
+}
+
+// seekCommand seeks to the provided position, or relative to
+// the current position if the value is prefixed with a sign.
+func seekCommand(args []string) error {
+	value, relative, err := commandValue(args)
+	if err != nil {
+		return err
+	}
+
+	if relative {
+		value += mp.Player().Position()
+	}
+	if value < 0 {
+		value = 0
+	}
+
+	mp.Player().SeekToPosition(value)
+
+	return nil
+}
+
+// volumeCommand sets the volume to the provided value, or changes
+// it by the provided value if the value is prefixed with a sign.
+func volumeCommand(args []string) error {
+	value, relative, err := commandValue(args)
+	if err != nil {
+		return err
+	}
+
+	if relative {
+		value += int64(mp.Player().Volume())
+	}
+
+	mp.Player().SetVolume(int(value))
+
+	return nil
+}
+
+// loopCommand sets the loop mode.
+func loopCommand(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Invalid arguments")
+	}
+
+	clearLoopCount()
+
+	loopFile, loopPlaylist := "no", "no"
+
+	switch args[0] {
+	case "none":
+
+	case "file":
+		loopFile = "yes"
+
+	case "playlist":
+		loopPlaylist = "yes"
+
+	case "once":
+		mp.Player().Set("loop-playlist", "no")
+		setRepeatOnce(true)
+
+		return nil
+
+	default:
+		return fmt.Errorf("Invalid loop mode %s", args[0])
+	}
+
+	if repeatOnceStatus() {
+		setRepeatOnce(false)
+	}
+
+	mp.Player().Set("loop-file", loopFile)
+	mp.Player().Set("loop-playlist", loopPlaylist)
+
+	return nil
+}
+
+// shuffleCommand shuffles the queue with the provided seed,
+// or reshuffles the queue if no seed is provided.
+func shuffleCommand(args []string) error {
+	switch len(args) {
+	case 0:
+		mp.Player().ReshuffleKeepingCurrent()
+
+	case 1:
+		seed, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid seed %s", args[0])
+		}
+
+		mp.Player().ShuffleWithSeed(seed)
+
+	default:
+		return fmt.Errorf("Invalid arguments")
+	}
+
+	return nil
+}
+
+// commandValue parses the single numeric argument of a command, and
+// returns whether it is relative, i.e. prefixed with a '+' or '-' sign.
+func commandValue(args []string) (int64, bool, error) {
+	if len(args) != 1 {
+		return 0, false, fmt.Errorf("Invalid arguments")
+	}
+
+	value, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("Invalid value %s", args[0])
+	}
+
+	return value, strings.ContainsAny(args[0][:1], "+-"), nil
+}
+
+// noArgs returns a command which runs the provided function,
+// and does not accept any arguments.
+func noArgs(f func()) func(args []string) error {
+	return func(args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("Invalid arguments")
+		}
+
+		f()
+
+		return nil
+	}
+}
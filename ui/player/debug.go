@@ -0,0 +1,109 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// DebugInfo describes the overlay which shows the media player's properties.
+type DebugInfo struct {
+	modal *app.Modal
+	view  *tview.TextView
+}
+
+// debugProperties lists the properties shown in the debug overlay.
+var debugProperties = []string{
+	"playlist-playing-pos",
+	"playlist-count",
+	"core-idle",
+	"paused-for-cache",
+	"cache-buffering-state",
+	"demuxer-cache-duration",
+	"demuxer-cache-idle",
+	"cache-speed",
+	"frame-drop-count",
+	"decoder-frame-drop-count",
+	"avsync",
+	"audio-codec-name",
+	"video-codec",
+	"hwdec-current",
+	"audio-bitrate",
+	"video-bitrate",
+	"path",
+}
+
+var debugInfo DebugInfo
+
+// showDebugInfo toggles the debug overlay, if it is enabled.
+func showDebugInfo() {
+	if !cmd.IsOptionEnabled("debug") {
+		app.ShowError(fmt.Errorf("Player: Debug information is disabled, enable it with the 'debug' option"))
+		return
+	}
+
+	if debugInfo.modal != nil && debugInfo.modal.Open {
+		debugInfo.modal.Exit(false)
+		return
+	}
+
+	if debugInfo.modal == nil {
+		debugInfo.view = tview.NewTextView()
+		debugInfo.view.SetDynamicColors(true)
+		debugInfo.view.SetBackgroundColor(tcell.ColorDefault)
+		debugInfo.view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				debugInfo.modal.Exit(false)
+			}
+
+			return event
+		})
+		debugInfo.view.SetFocusFunc(func() {
+			app.SetContextMenu("", nil)
+		})
+
+		debugInfo.modal = app.NewModal("player_debug", "Debug information", debugInfo.view, len(debugProperties)+4, 80)
+	}
+
+	debugInfo.view.SetText("[::b]Loading...")
+	debugInfo.modal.Show(false)
+
+	sendPlayerEvents()
+}
+
+// renderDebugInfo updates the debug overlay with the current values of the
+// properties. The properties are only retrieved if the overlay is open.
+func renderDebugInfo() {
+	if !debugOpen() {
+		return
+	}
+
+	var text strings.Builder
+
+	for _, property := range debugProperties {
+		value := "-"
+		if v, err := mp.Player().Get(property); err == nil && v != nil {
+			value = fmt.Sprint(v)
+		}
+
+		text.WriteString("[::b]" + property + ":[-:-:-] " + tview.Escape(value) + "\n")
+	}
+
+	app.UI.QueueUpdateDraw(func() {
+		debugInfo.view.SetText(strings.TrimSuffix(text.String(), "\n"))
+	})
+}
+
+// debugOpen returns whether the debug overlay is open.
+func debugOpen() bool {
+	app.UI.RLock()
+	defer app.UI.RUnlock()
+
+	return debugInfo.modal != nil && debugInfo.modal.Open
+}
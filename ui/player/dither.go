@@ -0,0 +1,72 @@
+package player
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/tview"
+)
+
+// bayerMatrix is the 4x4 threshold map used for ordered dithering.
+var bayerMatrix = [4][4]int{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// orderedSpread is the amount by which each color channel is offset
+// during ordered dithering. It roughly corresponds to the distance
+// between the levels of each channel in the 256-color palette.
+const orderedSpread = 51
+
+// imageDithering returns the dithering mode for the image view.
+// Ordered dithering is applied to the image before it is set,
+// so no further dithering is done by the image view in that case.
+func imageDithering() int {
+	if cmd.GetOptionValue("image-dithering") == "floyd-steinberg" {
+		return tview.DitheringFloydSteinberg
+	}
+
+	return tview.DitheringNone
+}
+
+// orderedDither returns a copy of the provided image with ordered
+// dithering applied, using the Bayer threshold map.
+func orderedDither(img image.Image) image.Image {
+	bounds := img.Bounds()
+	dithered := image.NewRGBA(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			offset := (bayerMatrix[y%4][x%4]*2 - 15) * orderedSpread / 32
+
+			dithered.Set(x, y, color.RGBA{
+				R: ditherChannel(r, offset),
+				G: ditherChannel(g, offset),
+				B: ditherChannel(b, offset),
+				A: uint8(a >> 8),
+			})
+		}
+	}
+
+	return dithered
+}
+
+// ditherChannel offsets a 16-bit color channel value by the
+// provided offset, and returns it as a clamped 8-bit value.
+func ditherChannel(value uint32, offset int) uint8 {
+	v := int(value>>8) + offset
+
+	switch {
+	case v < 0:
+		return 0
+
+	case v > 255:
+		return 255
+	}
+
+	return uint8(v)
+}
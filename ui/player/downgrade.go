@@ -0,0 +1,178 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// Downgrade stores the buffering events of the currently playing video,
+// which are used to determine whether the video quality should be lowered.
+type Downgrade struct {
+	id        string
+	events    []time.Time
+	buffering bool
+	locked    bool
+	loading   bool
+
+	seekID       string
+	seekPosition int64
+
+	mutex sync.Mutex
+}
+
+// resolutions lists the video resolutions in ascending order.
+var resolutions = []string{"144p", "240p", "360p", "480p", "720p", "1080p", "1440p", "2160p"}
+
+var downgrade Downgrade
+
+// setupDowngrade sets the initial quality lock from the 'lock-quality' option.
+func setupDowngrade() {
+	qualityLocked(cmd.IsOptionEnabled("lock-quality"))
+}
+
+// qualityLocked sets or returns whether the video quality is locked,
+// so that it is not lowered automatically.
+func qualityLocked(set ...bool) bool {
+	downgrade.mutex.Lock()
+	defer downgrade.mutex.Unlock()
+
+	if set != nil {
+		downgrade.locked = set[0]
+	}
+
+	return downgrade.locked
+}
+
+// toggleQualityLock toggles the video quality lock.
+func toggleQualityLock() {
+	if qualityLocked(!qualityLocked()) {
+		app.ShowInfo("Player: Video quality locked", false)
+		return
+	}
+
+	app.ShowInfo("Player: Video quality unlocked", false)
+}
+
+// checkBuffering records the start of each buffering event of the currently playing
+// video. If the video buffers 'downgrade-threshold' times within 'downgrade-window',
+// it is reloaded at a lower resolution, or as audio only at the lowest resolution.
+func checkBuffering(id string) {
+	threshold, _ := strconv.Atoi(cmd.GetOptionValue("downgrade-threshold"))
+	window, err := time.ParseDuration(cmd.GetOptionValue("downgrade-window"))
+	if threshold <= 0 || err != nil || id == "" || mp.Player().MediaType() == "Audio" {
+		return
+	}
+
+	buffering := mp.Player().Buffering() && mp.Player().Position() > 0
+
+	downgrade.mutex.Lock()
+	defer downgrade.mutex.Unlock()
+
+	if id != downgrade.id {
+		downgrade.id, downgrade.events = id, nil
+	}
+
+	started := buffering && !downgrade.buffering
+	downgrade.buffering = buffering
+	if !started || downgrade.locked || downgrade.loading {
+		return
+	}
+
+	now := time.Now()
+	events := []time.Time{now}
+	for _, event := range downgrade.events {
+		if now.Sub(event) < window {
+			events = append(events, event)
+		}
+	}
+
+	downgrade.events = events
+	if len(events) < threshold {
+		return
+	}
+
+	downgrade.events, downgrade.loading = nil, true
+
+	go downgradeQuality(id)
+}
+
+// downgradeQuality reloads the currently playing video at the next lower resolution,
+// or as audio only if the video is already playing at the lowest resolution. Since the
+// 'video-res' option is lowered, videos which are loaded afterwards use the same resolution.
+func downgradeQuality(id string) {
+	defer func() {
+		downgrade.mutex.Lock()
+		downgrade.loading = false
+		downgrade.mutex.Unlock()
+	}()
+
+	pos := mp.Player().QueuePosition()
+	if pos < 0 || currentVideoID() != id {
+		return
+	}
+
+	audio, resolution := false, lowerResolution(cmd.GetOptionValue("video-res"))
+	if resolution == "" {
+		audio = true
+		app.ShowInfo("Player: Buffering frequently, switching to audio only", true)
+	} else {
+		cmd.SetOptionValue("video-res", resolution)
+		app.ShowInfo("Player: Buffering frequently, switching to "+resolution, true)
+	}
+
+	if err := replaceTrack(pos, id, audio, true, mp.Player().Position()); err != nil {
+		app.ShowError(fmt.Errorf("Player: Unable to reload video at a lower quality"))
+		return
+	}
+
+	if audio {
+		app.ShowInfo("Player: Switched to audio only", false)
+		return
+	}
+
+	app.ShowInfo("Player: Switched to "+resolution, false)
+}
+
+// seekOnReload sets the position to which the provided video is seeked, once
+// it is reloaded into the queue and starts playing.
+func seekOnReload(id string, position int64) {
+	downgrade.mutex.Lock()
+	defer downgrade.mutex.Unlock()
+
+	downgrade.seekID, downgrade.seekPosition = id, position
+}
+
+// resumeReload seeks the reloaded video to the position at which it was reloaded,
+// and returns whether the currently playing video was reloaded.
+func resumeReload() bool {
+	downgrade.mutex.Lock()
+	id, position := downgrade.seekID, downgrade.seekPosition
+	downgrade.seekID, downgrade.seekPosition = "", 0
+	downgrade.mutex.Unlock()
+
+	if id == "" || id != currentVideoID() {
+		return false
+	}
+
+	mp.Player().SeekToPosition(position)
+
+	return true
+}
+
+// lowerResolution returns the resolution lower than the provided one.
+// If there is no lower resolution, an empty string is returned.
+func lowerResolution(resolution string) string {
+	for i, res := range resolutions {
+		if res == resolution && i > 0 {
+			return resolutions[i-1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,178 @@
+package player
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// exportEntry describes an exported history or favorites entry.
+type exportEntry struct {
+	Title     string `json:"title"`
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	MediaType string `json:"mediatype"`
+	Author    string `json:"author"`
+	AuthorID  string `json:"authorId"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// exportFields lists the column names of the exported CSV files.
+var exportFields = []string{"title", "id", "type", "mediatype", "author", "authorId", "timestamp"}
+
+// exportHistory shows the file browser to select the file to export the history to.
+func exportHistory() {
+	player.history.modal.Exit(false)
+
+	app.UI.FileBrowser.Show("Export history to:", func(file string) {
+		player.mutex.Lock()
+		entries := player.history.entries
+		player.mutex.Unlock()
+
+		exportTo(file, "History", entries)
+	})
+}
+
+// exportFavorites shows the file browser to select the file to export the favorites to.
+func exportFavorites() {
+	player.favorites.modal.Exit(false)
+
+	app.UI.FileBrowser.Show("Export favorites to:", func(file string) {
+		player.mutex.Lock()
+		entries := player.favorites.entries
+		player.mutex.Unlock()
+
+		exportTo(file, "Favorites", entries)
+	})
+}
+
+// exportTo exports the provided entries to the provided file. The entries are exported
+// in the CSV format if the file has a '.csv' extension, and in the JSON format otherwise.
+func exportTo(file, name string, entries []cmd.PlayHistorySettings) {
+	if len(entries) == 0 {
+		app.ShowError(fmt.Errorf("%s: No entries to export", name))
+		return
+	}
+
+	if _, err := os.Stat(file); err == nil {
+		reply := app.UI.FileBrowser.Query("Overwrite file (y/n)?", player.queue.validate, 1)
+		if reply != "y" {
+			return
+		}
+	}
+
+	app.ShowInfo(fmt.Sprintf("%s: Exporting %d entries", name, len(entries)), true)
+
+	if err := writeExport(file, entries); err != nil {
+		app.ShowError(fmt.Errorf("%s: Unable to export to %s: %w", name, file, err))
+		return
+	}
+
+	app.ShowInfo(fmt.Sprintf("%s: Exported %d entries to %s", name, len(entries), file), false)
+
+	app.UI.FileBrowser.Hide()
+}
+
+// writeExport writes the provided entries to the provided file. Each entry
+// is encoded and written separately, so that large lists are streamed to the
+// file instead of being encoded in memory at once.
+func writeExport(file string, entries []cmd.PlayHistorySettings) error {
+	exportFile, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0664)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(exportFile)
+
+	if strings.EqualFold(filepath.Ext(file), ".csv") {
+		err = writeExportCSV(writer, entries)
+	} else {
+		err = writeExportJSON(writer, entries)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+
+	if closeErr := exportFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// writeExportJSON writes the provided entries as a JSON array.
+func writeExportJSON(writer *bufio.Writer, entries []cmd.PlayHistorySettings) error {
+	if _, err := writer.WriteString("[\n"); err != nil {
+		return err
+	}
+
+	for i, entry := range entries {
+		data, err := json.Marshal(newExportEntry(entry))
+		if err != nil {
+			return err
+		}
+
+		separator := ",\n"
+		if i == len(entries)-1 {
+			separator = "\n"
+		}
+
+		if _, err := writer.WriteString("  " + string(data) + separator); err != nil {
+			return err
+		}
+	}
+
+	_, err := writer.WriteString("]\n")
+
+	return err
+}
+
+// writeExportCSV writes the provided entries as CSV records, with a header record.
+func writeExportCSV(writer *bufio.Writer, entries []cmd.PlayHistorySettings) error {
+	csvWriter := csv.NewWriter(writer)
+
+	if err := csvWriter.Write(exportFields); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		e := newExportEntry(entry)
+
+		if err := csvWriter.Write([]string{
+			e.Title, e.ID, e.Type, e.MediaType,
+			e.Author, e.AuthorID, strconv.FormatInt(e.Timestamp, 10),
+		}); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// newExportEntry returns the exported form of the provided entry.
+func newExportEntry(entry cmd.PlayHistorySettings) exportEntry {
+	id := entry.VideoID
+	if entry.Type == "playlist" {
+		id = entry.PlaylistID
+	}
+
+	return exportEntry{
+		Title:     entry.Title,
+		ID:        id,
+		Type:      entry.Type,
+		MediaType: entry.MediaType,
+		Author:    entry.Author,
+		AuthorID:  entry.AuthorID,
+		Timestamp: entry.Timestamp,
+	}
+}
@@ -0,0 +1,92 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// openExternal opens the selected queue entry if the queue is focused, or the
+// currently playing entry otherwise, in the external player. If the entry is
+// currently playing, it is opened at the current playback position, and the
+// playback is stopped if the 'external-player-stop' option is enabled.
+func openExternal() {
+	pos := mp.Player().QueuePosition()
+	if IsQueueFocused() {
+		row, _ := player.queue.table.GetSelection()
+		pos = player.queue.position(row)
+	}
+
+	data := utils.GetDataFromURL(mp.Player().Title(pos))
+	if pos < 0 || data == nil || data.Get("id") == "" {
+		app.ShowError(fmt.Errorf("Player: No video is selected or playing"))
+		return
+	}
+
+	var position int64
+
+	playing := pos == mp.Player().QueuePosition()
+	if playing {
+		position = mp.Player().Position()
+	}
+
+	title := data.Get("title")
+	link := "https://youtube.com/watch?v=" + data.Get("id")
+
+	if err := runExternal(link, position); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	if playing && cmd.IsOptionEnabled("external-player-stop") {
+		stopPlayer()
+	}
+
+	app.ShowInfo("Player: Opened "+title+" in the external player", false)
+}
+
+// runExternal starts the command from the 'external-player' option with the provided
+// link and position in seconds, without waiting for it to exit. The link and position
+// are passed via the INVIDTUI_URL and INVIDTUI_POSITION environment variables, and as
+// arguments in the same order. If the option is not set, mpv is started instead.
+func runExternal(link string, position int64) error {
+	var external *exec.Cmd
+
+	seconds := strconv.FormatInt(position, 10)
+
+	switch command := cmd.GetOptionValue("external-player"); {
+	case command == "":
+		external = exec.Command(
+			cmd.GetOptionValue("mpv-path"),
+			"--start="+seconds,
+			"--script-opts=ytdl_hook-ytdl_path="+cmd.GetOptionValue("ytdl-path"),
+			link,
+		)
+
+	case runtime.GOOS == "windows":
+		external = exec.Command("cmd", "/C", command, link, seconds)
+
+	default:
+		external = exec.Command("sh", "-c", command, "invidtui", link, seconds)
+	}
+
+	external.Env = append(os.Environ(),
+		"INVIDTUI_URL="+link,
+		"INVIDTUI_POSITION="+seconds,
+	)
+
+	if err := external.Start(); err != nil {
+		return fmt.Errorf("Player: Unable to start the external player: %w", err)
+	}
+
+	go external.Wait()
+
+	return nil
+}
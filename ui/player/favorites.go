@@ -0,0 +1,330 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/ui/view"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Favorites describes the layout of the favorites popup
+// and stores the favorite videos.
+type Favorites struct {
+	entries  []cmd.PlayHistorySettings
+	filtered []cmd.PlayHistorySettings
+	ids      map[string]struct{}
+
+	modal *app.Modal
+	flex  *tview.Flex
+	table *tview.Table
+	input *tview.InputField
+}
+
+// loadFavorites loads the saved favorites.
+func loadFavorites() {
+	setFavorites(cmd.DeduplicateFavorites(cmd.Settings.Favorites))
+}
+
+// setFavorites sets the favorites, and indexes them by their video IDs.
+// This must be called with the player mutex held, or before the player is started.
+func setFavorites(entries []cmd.PlayHistorySettings) {
+	player.favorites.entries = entries
+	player.favorites.ids = make(map[string]struct{}, len(entries))
+
+	for _, entry := range entries {
+		player.favorites.ids[entry.VideoID] = struct{}{}
+	}
+
+	cmd.Settings.Favorites = entries
+}
+
+// isFavorite returns whether the video with the provided ID is a favorite.
+func isFavorite(id string) bool {
+	if id == "" {
+		return false
+	}
+
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	_, ok := player.favorites.ids[id]
+
+	return ok
+}
+
+// toggleFavorite adds the currently playing video to the favorites,
+// or removes it if it is already a favorite, and saves the favorites.
+func toggleFavorite() {
+	id := currentVideoID()
+	if id == "" {
+		app.ShowError(fmt.Errorf("Player: Only videos can be added to the favorites"))
+		return
+	}
+
+	if isFavorite(id) {
+		removeFavorite(id)
+		go saveFavorites("Player: Removed from favorites")
+
+		return
+	}
+
+	title, author, authorID := id, "", ""
+	if video := player.queue.currentVideo(id); video != nil {
+		title, author, authorID = video.Title, video.Author, video.AuthorID
+	}
+
+	entry := cmd.PlayHistorySettings{
+		Type:      "video",
+		Title:     title,
+		Author:    author,
+		VideoID:   id,
+		AuthorID:  authorID,
+		MediaType: strings.ToLower(mp.Player().MediaType()),
+		Timestamp: time.Now().Unix(),
+	}
+
+	addFavorite(entry)
+
+	go saveFavorites("Player: Added " + title + " to favorites")
+}
+
+// addFavorite adds the provided entry to the start of the favorites.
+// If the video is already a favorite, its previous entry is replaced.
+func addFavorite(entry cmd.PlayHistorySettings) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	setFavorites(cmd.DeduplicateFavorites(
+		append([]cmd.PlayHistorySettings{entry}, player.favorites.entries...),
+	))
+}
+
+// removeFavorite removes the video with the provided ID from the favorites.
+func removeFavorite(id string) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	entries := make([]cmd.PlayHistorySettings, 0, len(player.favorites.entries))
+	for _, entry := range player.favorites.entries {
+		if entry.VideoID != id {
+			entries = append(entries, entry)
+		}
+	}
+
+	setFavorites(entries)
+}
+
+// saveFavorites saves the favorites along with the rest of the settings.
+func saveFavorites(message string) {
+	player.mutex.Lock()
+	err := cmd.StoreSettings()
+	player.mutex.Unlock()
+
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	app.ShowInfo(message, false)
+	player.queue.sendStatus()
+}
+
+// showFavorites shows a popup with the favorite videos.
+func showFavorites() {
+	player.mutex.Lock()
+	count := len(player.favorites.entries)
+	player.mutex.Unlock()
+
+	if count == 0 {
+		app.ShowError(fmt.Errorf("Player: No favorites"))
+		return
+	}
+
+	if player.favorites.modal != nil {
+		if player.favorites.modal.Open {
+			return
+		}
+
+		goto Render
+	}
+
+	player.favorites.table = tview.NewTable()
+	player.favorites.table.SetSelectorWrap(true)
+	player.favorites.table.SetSelectable(true, false)
+	player.favorites.table.SetBackgroundColor(tcell.ColorDefault)
+	player.favorites.table.SetInputCapture(favoritesTableKeybindings)
+	player.favorites.table.SetFocusFunc(func() {
+		app.SetContextMenu(cmd.KeyContextFavorites, player.favorites.table)
+	})
+
+	player.favorites.input = tview.NewInputField()
+	player.favorites.input.SetLabel("[::b]Filter: ")
+	player.favorites.input.SetChangedFunc(favoritesFilter)
+	player.favorites.input.SetLabelColor(tcell.ColorWhite)
+	player.favorites.input.SetBackgroundColor(tcell.ColorDefault)
+	player.favorites.input.SetFieldBackgroundColor(tcell.ColorDefault)
+	player.favorites.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyEnter:
+			app.UI.SetFocus(player.favorites.table)
+		}
+
+		return event
+	})
+
+	player.favorites.flex = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(player.favorites.table, 10, 0, true).
+		AddItem(app.HorizontalLine(), 1, 0, false).
+		AddItem(player.favorites.input, 1, 0, false)
+
+	player.favorites.modal = app.NewModal("player_favorites", "Favorites", player.favorites.flex, 40, 0)
+
+Render:
+	player.favorites.modal.Show(true)
+	player.favorites.input.SetText("")
+	favoritesFilter("")
+}
+
+// favoritesTableKeybindings defines the keybindings for the favorites popup.
+func favoritesTableKeybindings(event *tcell.EventKey) *tcell.EventKey {
+	switch cmd.KeyOperation(event, cmd.KeyContextFavorites) {
+	case cmd.KeyQuery:
+		app.UI.SetFocus(player.favorites.input)
+
+	case cmd.KeyFavoritesPlay:
+		if entry, ok := selectedFavorite(); ok {
+			playFromHistory(entry)
+		}
+
+	case cmd.KeyFavoritesRemove:
+		removeSelectedFavorite()
+
+	case cmd.KeyFavoritesExport:
+		exportFavorites()
+
+	case cmd.KeyChannelVideos:
+		view.Channel.EventHandler("video", event.Modifiers() == tcell.ModAlt)
+
+	case cmd.KeyChannelPlaylists:
+		view.Channel.EventHandler("playlist", event.Modifiers() == tcell.ModAlt)
+
+	case cmd.KeyClose:
+		player.favorites.modal.Exit(false)
+	}
+
+	for _, k := range []cmd.Key{cmd.KeyChannelVideos, cmd.KeyChannelPlaylists} {
+		if cmd.KeyOperation(event) == k {
+			player.favorites.modal.Exit(false)
+			app.UI.Status.SwitchToPage("messages")
+
+			break
+		}
+	}
+
+	return event
+}
+
+// favoritesFilter filters the favorites according to the provided text.
+// This handler is attached to the favorites popup's input.
+func favoritesFilter(text string) {
+	var row int
+
+	player.favorites.table.Clear()
+	player.favorites.filtered = nil
+
+	player.mutex.Lock()
+	entries := player.favorites.entries
+	player.mutex.Unlock()
+
+	text = strings.ToLower(text)
+
+	for _, entry := range entries {
+		if text != "" && !strings.Contains(strings.ToLower(entry.Title), text) {
+			continue
+		}
+
+		mediaType := entry.MediaType
+		if mediaType == "" {
+			mediaType = "-"
+		}
+
+		info := inv.SearchData{
+			Type:     entry.Type,
+			Title:    entry.Title,
+			Author:   entry.Author,
+			VideoID:  entry.VideoID,
+			AuthorID: entry.AuthorID,
+		}
+
+		player.favorites.table.SetCell(row, 0, tview.NewTableCell("[yellow::b]★ [blue::b]"+tview.Escape(entry.Title)).
+			SetExpansion(1).
+			SetReference(info).
+			SetSelectedStyle(app.UI.SelectedStyle),
+		)
+
+		player.favorites.table.SetCell(row, 1, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+
+		player.favorites.table.SetCell(row, 2, tview.NewTableCell("[purple::b]"+tview.Escape(entry.Author)).
+			SetSelectedStyle(app.UI.ColumnStyle),
+		)
+
+		player.favorites.table.SetCell(row, 3, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+
+		player.favorites.table.SetCell(row, 4, tview.NewTableCell("[pink]"+mediaType).
+			SetSelectedStyle(app.UI.ColumnStyle),
+		)
+
+		player.favorites.filtered = append(player.favorites.filtered, entry)
+
+		row++
+	}
+
+	player.favorites.table.ScrollToBeginning()
+	app.ResizeModal()
+}
+
+// selectedFavorite returns the selected entry in the favorites popup.
+func selectedFavorite() (cmd.PlayHistorySettings, bool) {
+	row, _ := player.favorites.table.GetSelection()
+	if row < 0 || row >= len(player.favorites.filtered) {
+		return cmd.PlayHistorySettings{}, false
+	}
+
+	return player.favorites.filtered[row], true
+}
+
+// removeSelectedFavorite removes the selected entry from the favorites, and saves the favorites.
+func removeSelectedFavorite() {
+	entry, ok := selectedFavorite()
+	if !ok {
+		return
+	}
+
+	row, _ := player.favorites.table.GetSelection()
+
+	removeFavorite(entry.VideoID)
+
+	favoritesFilter(player.favorites.input.GetText())
+	if len(player.favorites.filtered) == 0 && player.favorites.input.GetText() == "" {
+		player.favorites.modal.Exit(false)
+	} else {
+		if row >= len(player.favorites.filtered) {
+			row = len(player.favorites.filtered) - 1
+		}
+		player.favorites.table.Select(row, 0)
+	}
+
+	go saveFavorites("Player: Removed " + entry.Title + " from favorites")
+}
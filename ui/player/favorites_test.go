@@ -0,0 +1,60 @@
+package player
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/darkhz/invidtui/cmd"
+)
+
+func TestFavorites(t *testing.T) {
+	favorite := func(id string, timestamp int64) cmd.PlayHistorySettings {
+		return cmd.PlayHistorySettings{Type: "video", VideoID: id, Title: "Video " + id, Timestamp: timestamp}
+	}
+
+	saved := cmd.Settings.Favorites
+	defer func() {
+		cmd.Settings.Favorites = saved
+		setFavorites(nil)
+	}()
+
+	cmd.Settings.Favorites = []cmd.PlayHistorySettings{favorite("a", 2), favorite("b", 1), favorite("a", 0)}
+	loadFavorites()
+
+	if want := []cmd.PlayHistorySettings{favorite("a", 2), favorite("b", 1)}; !reflect.DeepEqual(player.favorites.entries, want) {
+		t.Fatalf("loaded favorites = %v, want %v", player.favorites.entries, want)
+	}
+
+	addFavorite(favorite("c", 3))
+	addFavorite(favorite("b", 4))
+
+	want := []cmd.PlayHistorySettings{favorite("b", 4), favorite("c", 3), favorite("a", 2)}
+	if !reflect.DeepEqual(player.favorites.entries, want) {
+		t.Errorf("favorites after adding = %v, want %v", player.favorites.entries, want)
+	}
+	if !reflect.DeepEqual(cmd.Settings.Favorites, want) {
+		t.Errorf("saved favorites after adding = %v, want %v", cmd.Settings.Favorites, want)
+	}
+
+	removeFavorite("c")
+	removeFavorite("missing")
+
+	for _, test := range []struct {
+		id   string
+		want bool
+	}{
+		{id: "a", want: true},
+		{id: "b", want: true},
+		{id: "c", want: false},
+		{id: "missing", want: false},
+		{id: "", want: false},
+	} {
+		if isFavorite(test.id) != test.want {
+			t.Errorf("isFavorite(%q) = %v, want %v", test.id, !test.want, test.want)
+		}
+	}
+
+	if want := []cmd.PlayHistorySettings{favorite("b", 4), favorite("a", 2)}; !reflect.DeepEqual(cmd.Settings.Favorites, want) {
+		t.Errorf("saved favorites after removing = %v, want %v", cmd.Settings.Favorites, want)
+	}
+}
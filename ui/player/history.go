@@ -1,12 +1,16 @@
 package player
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/darkhz/invidtui/cmd"
 	inv "github.com/darkhz/invidtui/invidious"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/ui/view"
+	"github.com/darkhz/invidtui/utils"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
@@ -14,7 +18,13 @@ import (
 // History describes the layout of the history popup
 // and stores the entries.
 type History struct {
-	entries []cmd.PlayHistorySettings
+	entries  []cmd.PlayHistorySettings
+	filtered []cmd.PlayHistorySettings
+	matched  []cmd.PlayHistorySettings
+
+	query string
+
+	mediaFilter, dateFilter int
 
 	modal *app.Modal
 	flex  *tview.Flex
@@ -22,16 +32,37 @@ type History struct {
 	input *tview.InputField
 }
 
-// loadHistory loads the saved play history.
+// historyMediaFilters lists the media types the history entries can be filtered by.
+var historyMediaFilters = []string{"all", "audio", "video", "playlist"}
+
+// historyDateFilters lists the date ranges the history entries can be filtered by.
+var historyDateFilters = []struct {
+	Name  string
+	Range time.Duration
+}{
+	{"any time", 0},
+	{"past day", 24 * time.Hour},
+	{"past week", 7 * 24 * time.Hour},
+	{"past month", 30 * 24 * time.Hour},
+}
+
+// loadHistory loads the saved play history. Any duplicate entries
+// from older history files are removed here.
 func loadHistory() {
-	player.history.entries = cmd.Settings.PlayHistory
+	player.history.entries = cmd.DeduplicatePlayHistory(cmd.Settings.PlayHistory)
+	cmd.Settings.PlayHistory = player.history.entries
 }
 
 // addToHistory adds a currently playing item to the history.
-func addToHistory(data inv.SearchData) {
+func addToHistory(data inv.SearchData, audio bool) {
 	player.mutex.Lock()
 	defer player.mutex.Unlock()
 
+	mediaType := "video"
+	if audio {
+		mediaType = "audio"
+	}
+
 	info := cmd.PlayHistorySettings{
 		Type:       data.Type,
 		Title:      data.Title,
@@ -39,31 +70,14 @@ func addToHistory(data inv.SearchData) {
 		VideoID:    data.VideoID,
 		PlaylistID: data.PlaylistID,
 		AuthorID:   data.AuthorID,
+		MediaType:  mediaType,
+		Timestamp:  time.Now().Unix(),
 	}
 
-	if len(player.history.entries) != 0 && player.history.entries[0] == info {
-		return
-	}
-
-	prevInfo := info
-
-	for i, phInfo := range player.history.entries {
-		switch {
-		case i == 0:
-			player.history.entries[0] = info
-			prevInfo = phInfo
-
-		case phInfo == info:
-			player.history.entries[i] = prevInfo
-			return
-
-		default:
-			player.history.entries[i] = prevInfo
-			prevInfo = phInfo
-		}
-	}
-
-	player.history.entries = append(player.history.entries, prevInfo)
+	player.history.entries = cmd.DeduplicatePlayHistory(
+		append([]cmd.PlayHistorySettings{info}, player.history.entries...),
+	)
+	player.history.query = ""
 	cmd.Settings.PlayHistory = player.history.entries
 }
 
@@ -97,7 +111,6 @@ func showHistory() {
 	})
 
 	player.history.input = tview.NewInputField()
-	player.history.input.SetLabel("[::b]Filter: ")
 	player.history.input.SetChangedFunc(historyFilter)
 	player.history.input.SetLabelColor(tcell.ColorWhite)
 	player.history.input.SetBackgroundColor(tcell.ColorDefault)
@@ -122,14 +135,37 @@ func showHistory() {
 Render:
 	player.history.modal.Show(true)
 	player.history.input.SetText("")
+	setHistoryFilterLabel()
 }
 
 // historyTableKeybindings defines the keybindings for the history popup.
 func historyTableKeybindings(event *tcell.EventKey) *tcell.EventKey {
-	switch cmd.KeyOperation(event) {
+	switch cmd.KeyOperation(event, cmd.KeyContextHistory) {
 	case cmd.KeyQuery:
 		app.UI.SetFocus(player.history.input)
 
+	case cmd.KeyHistoryPlay:
+		playHistoryEntry()
+
+	case cmd.KeyHistoryFilterMedia:
+		player.history.mediaFilter = (player.history.mediaFilter + 1) % len(historyMediaFilters)
+		setHistoryFilterLabel()
+		historyFilter(player.history.input.GetText())
+
+	case cmd.KeyHistoryFilterDate:
+		player.history.dateFilter = (player.history.dateFilter + 1) % len(historyDateFilters)
+		setHistoryFilterLabel()
+		historyFilter(player.history.input.GetText())
+
+	case cmd.KeyHistoryRemoveEntry:
+		removeHistoryEntry()
+
+	case cmd.KeyHistoryClear:
+		clearHistory()
+
+	case cmd.KeyHistoryExport:
+		exportHistory()
+
 	case cmd.KeyChannelVideos:
 		view.Channel.EventHandler("video", event.Modifiers() == tcell.ModAlt)
 
@@ -156,15 +192,23 @@ func historyTableKeybindings(event *tcell.EventKey) *tcell.EventKey {
 // This handler is attached to the history popup's input.
 func historyFilter(text string) {
 	var row int
-	text = strings.ToLower(text)
 
 	player.history.table.Clear()
+	player.history.filtered = nil
 
-	for _, ph := range player.history.entries {
-		if text != "" && !strings.Contains(strings.ToLower(ph.Title), text) {
+	for _, ph := range historyMatches(text) {
+		if !historyEntryMatches(ph) {
 			continue
 		}
 
+		date, mediaType := "-", ph.MediaType
+		if ph.Timestamp > 0 {
+			date = time.Unix(ph.Timestamp, 0).Format("2006-01-02 15:04")
+		}
+		if mediaType == "" {
+			mediaType = "-"
+		}
+
 		info := inv.SearchData{
 			Type:       ph.Type,
 			Title:      ph.Title,
@@ -196,10 +240,235 @@ func historyFilter(text string) {
 			SetSelectedStyle(app.UI.ColumnStyle),
 		)
 
+		player.history.table.SetCell(row, 5, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+
+		player.history.table.SetCell(row, 6, tview.NewTableCell("[pink]"+mediaType).
+			SetSelectedStyle(app.UI.ColumnStyle),
+		)
+
+		player.history.table.SetCell(row, 7, tview.NewTableCell("").
+			SetSelectable(false),
+		)
+
+		player.history.table.SetCell(row, 8, tview.NewTableCell("[lightpink]"+date).
+			SetSelectedStyle(app.UI.ColumnStyle),
+		)
+
+		player.history.filtered = append(player.history.filtered, ph)
+
 		row++
 	}
 
 	player.history.table.ScrollToBeginning()
+	if text != "" && cmd.GetOptionValue("search-mode") == "fuzzy" {
+		player.history.table.Select(0, 0)
+	}
 
 	app.ResizeModal()
 }
+
+// historyMatches returns the history entries whose titles match the provided text.
+// If fuzzy searching is enabled, the entries are sorted by their match scores, with
+// the best match first. Since an entry which does not match a text cannot match a
+// longer text which starts with it, only the entries that matched the previous text
+// are searched again while the text is being typed.
+func historyMatches(text string) []cmd.PlayHistorySettings {
+	var matches []cmd.PlayHistorySettings
+
+	if text == "" {
+		player.history.query, player.history.matched = "", nil
+		return player.history.entries
+	}
+
+	if cmd.GetOptionValue("search-mode") != "fuzzy" {
+		text = strings.ToLower(text)
+
+		for _, ph := range player.history.entries {
+			if strings.Contains(strings.ToLower(ph.Title), text) {
+				matches = append(matches, ph)
+			}
+		}
+
+		return matches
+	}
+
+	candidates := player.history.entries
+	if query := player.history.query; query != "" && strings.HasPrefix(text, query) {
+		candidates = player.history.matched
+	}
+
+	scores := make(map[string]int, len(candidates))
+	for _, ph := range candidates {
+		score, _, ok := utils.FuzzyMatch(ph.Title, text)
+		if !ok {
+			continue
+		}
+
+		scores[cmd.PlayHistoryKey(ph)] = score
+		matches = append(matches, ph)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return scores[cmd.PlayHistoryKey(matches[i])] > scores[cmd.PlayHistoryKey(matches[j])]
+	})
+
+	player.history.query, player.history.matched = text, matches
+
+	return matches
+}
+
+// historyEntryMatches returns whether the history entry matches
+// the currently selected media type and date filters. Entries saved
+// without a timestamp are only shown when no date filter is selected.
+func historyEntryMatches(entry cmd.PlayHistorySettings) bool {
+	switch media := historyMediaFilters[player.history.mediaFilter]; media {
+	case "audio", "video":
+		if entry.MediaType != media {
+			return false
+		}
+
+	case "playlist":
+		if entry.Type != media {
+			return false
+		}
+	}
+
+	if dateRange := historyDateFilters[player.history.dateFilter].Range; dateRange > 0 {
+		if entry.Timestamp == 0 || time.Since(time.Unix(entry.Timestamp, 0)) > dateRange {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setHistoryFilterLabel displays the currently selected filters
+// within the history popup's input label.
+func setHistoryFilterLabel() {
+	player.history.input.SetLabel(
+		"[::b]Filter (" + historyMediaFilters[player.history.mediaFilter] +
+			", " + historyDateFilters[player.history.dateFilter].Name + "): ",
+	)
+}
+
+// removeHistoryEntry removes the selected entry from the history, and saves the history.
+func removeHistoryEntry() {
+	row, _ := player.history.table.GetSelection()
+	if row < 0 || row >= len(player.history.filtered) {
+		return
+	}
+
+	removeFromHistory(player.history.filtered[row])
+
+	historyFilter(player.history.input.GetText())
+	if row >= len(player.history.filtered) {
+		row = len(player.history.filtered) - 1
+	}
+	player.history.table.Select(row, 0)
+
+	go saveHistory("Removed entry from history")
+}
+
+// removeFromHistory removes the provided entry, along with its duplicates,
+// from the history and the settings. The settings are not saved.
+func removeFromHistory(entry cmd.PlayHistorySettings) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	entries := removeHistoryKey(player.history.entries, cmd.PlayHistoryKey(entry))
+	player.history.entries = entries
+	player.history.query = ""
+	cmd.Settings.PlayHistory = entries
+}
+
+// removeHistoryKey returns the history entries without the
+// entries which are identified by the provided key.
+func removeHistoryKey(entries []cmd.PlayHistorySettings, key string) []cmd.PlayHistorySettings {
+	kept := make([]cmd.PlayHistorySettings, 0, len(entries))
+	for _, entry := range entries {
+		if cmd.PlayHistoryKey(entry) != key {
+			kept = append(kept, entry)
+		}
+	}
+
+	return kept
+}
+
+// clearHistory removes all the entries from the history after
+// a confirmation, and saves the history.
+func clearHistory() {
+	app.UI.Status.SetInput("Clear the entire history? (y/n)", 1, true, func(reply string) {
+		if reply != "y" {
+			return
+		}
+
+		player.mutex.Lock()
+		player.history.entries = nil
+		player.history.query = ""
+		cmd.Settings.PlayHistory = nil
+		player.mutex.Unlock()
+
+		player.history.modal.Exit(false)
+
+		go saveHistory("Cleared history")
+	}, nil)
+}
+
+// saveHistory saves the history along with the rest of the settings, so that
+// the history file is kept in sync with the history that is shown.
+func saveHistory(message string) {
+	player.mutex.Lock()
+	err := cmd.StoreSettings()
+	player.mutex.Unlock()
+
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	app.ShowInfo(message, false)
+}
+
+// playHistoryEntry plays the selected history entry with the
+// media type it was previously played with.
+func playHistoryEntry() {
+	row, _ := player.history.table.GetSelection()
+	if row < 0 || row >= len(player.history.filtered) {
+		return
+	}
+
+	playFromHistory(player.history.filtered[row])
+}
+
+// replayLastEntry plays the most recent history entry again
+// with the media type it was previously played with.
+func replayLastEntry() {
+	player.mutex.Lock()
+	if len(player.history.entries) == 0 {
+		player.mutex.Unlock()
+		app.ShowError(fmt.Errorf("Player: No history entries to play again"))
+
+		return
+	}
+
+	entry := player.history.entries[0]
+	player.mutex.Unlock()
+
+	playFromHistory(entry)
+}
+
+// playFromHistory plays the provided history entry.
+func playFromHistory(entry cmd.PlayHistorySettings) {
+	info := inv.SearchData{
+		Type:       entry.Type,
+		Title:      entry.Title,
+		Author:     entry.Author,
+		VideoID:    entry.VideoID,
+		PlaylistID: entry.PlaylistID,
+		AuthorID:   entry.AuthorID,
+	}
+
+	Play(entry.MediaType == "audio", true, info)
+}
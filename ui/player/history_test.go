@@ -0,0 +1,108 @@
+package player
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/darkhz/invidtui/cmd"
+)
+
+func TestRemoveHistoryKey(t *testing.T) {
+	entries := []cmd.PlayHistorySettings{
+		{Type: "video", VideoID: "a", Timestamp: 3},
+		{Type: "playlist", PlaylistID: "b"},
+		{Type: "video", VideoID: "a", Timestamp: 1},
+		{Type: "channel", AuthorID: "c", Title: "Channel"},
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want []cmd.PlayHistorySettings
+	}{
+		{
+			name: "all entries with the key",
+			key:  cmd.PlayHistoryKey(entries[0]),
+			want: []cmd.PlayHistorySettings{entries[1], entries[3]},
+		},
+		{
+			name: "channel",
+			key:  cmd.PlayHistoryKey(entries[3]),
+			want: entries[:3],
+		},
+		{
+			name: "missing key",
+			key:  "video:missing",
+			want: entries,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			original := append([]cmd.PlayHistorySettings(nil), entries...)
+
+			if kept := removeHistoryKey(entries, test.key); !reflect.DeepEqual(kept, test.want) {
+				t.Errorf("removeHistoryKey() = %v, want %v", kept, test.want)
+			}
+			if !reflect.DeepEqual(entries, original) {
+				t.Errorf("removeHistoryKey() modified the entries: %v", entries)
+			}
+		})
+	}
+
+	if kept := removeHistoryKey(nil, "video:a"); len(kept) != 0 {
+		t.Errorf("removeHistoryKey() without entries = %v", kept)
+	}
+}
+
+func TestRemoveFromHistorySaved(t *testing.T) {
+	home := t.TempDir()
+	if err := os.Mkdir(filepath.Join(home, ".invidtui"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", home)
+	cmd.SetupConfig()
+
+	settings, entries := cmd.Settings, player.history.entries
+	defer func() {
+		cmd.Settings, player.history.entries = settings, entries
+	}()
+
+	history := []cmd.PlayHistorySettings{
+		{Type: "video", VideoID: "a", Title: "First", Timestamp: 3},
+		{Type: "playlist", PlaylistID: "b", Title: "Second", Timestamp: 2},
+		{Type: "video", VideoID: "c", Title: "Third", Timestamp: 1},
+	}
+
+	player.history.entries = history
+	cmd.Settings = cmd.SettingsData{PlayHistory: history}
+
+	removeFromHistory(history[1])
+	saveHistory("Removed entry from history")
+
+	want := []cmd.PlayHistorySettings{history[0], history[2]}
+	if !reflect.DeepEqual(player.history.entries, want) {
+		t.Errorf("history = %v, want %v", player.history.entries, want)
+	}
+	if !reflect.DeepEqual(cmd.Settings.PlayHistory, player.history.entries) {
+		t.Errorf("settings history = %v, want %v", cmd.Settings.PlayHistory, player.history.entries)
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".invidtui", "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var saved cmd.SettingsData
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("saved settings cannot be decoded: %v", err)
+	}
+
+	if !reflect.DeepEqual(saved.PlayHistory, player.history.entries) {
+		t.Errorf("saved history = %v, want %v", saved.PlayHistory, player.history.entries)
+	}
+}
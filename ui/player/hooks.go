@@ -0,0 +1,150 @@
+package player
+
+import (
+	"fmt"
+	"sync"
+
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// Event describes a player event.
+type Event int
+
+// The different types of player events.
+const (
+	// TrackStarted is sent when a track has been loaded and starts playing.
+	TrackStarted Event = iota
+
+	// TrackEnded is sent when a track has finished playing.
+	TrackEnded
+
+	// Paused is sent when the playback is paused or resumed.
+	Paused
+
+	// QueueChanged is sent when the queue has been modified.
+	QueueChanged
+)
+
+// EventData describes the data sent with a player event.
+type EventData struct {
+	Event Event
+
+	// Track and Video are set for the TrackStarted and TrackEnded events.
+	// Video is nil if the video information has not been loaded yet.
+	Track QueueData
+	Video *inv.VideoData
+
+	// Paused is set for the Paused event.
+	Paused bool
+
+	// Queue is set for the QueueChanged event.
+	Queue []QueueData
+}
+
+// Hooks stores the callbacks registered for player events.
+type Hooks struct {
+	callbacks map[Event][]func(EventData)
+
+	paused, pauseKnown bool
+
+	mutex sync.Mutex
+}
+
+var hooks Hooks
+
+// Subscribe registers a callback for the provided event. Callbacks are run
+// in their own goroutines, so they do not block the player, and a panic
+// within a callback is recovered and shown as an error.
+func Subscribe(event Event, callback func(EventData)) {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+
+	if hooks.callbacks == nil {
+		hooks.callbacks = make(map[Event][]func(EventData))
+	}
+
+	hooks.callbacks[event] = append(hooks.callbacks[event], callback)
+}
+
+// emitEvent runs the callbacks registered for the event with the provided data.
+func emitEvent(data EventData) {
+	hooks.mutex.Lock()
+	callbacks := hooks.callbacks[data.Event]
+	hooks.mutex.Unlock()
+
+	for _, callback := range callbacks {
+		go func(callback func(EventData)) {
+			defer func() {
+				if r := recover(); r != nil {
+					app.ShowError(fmt.Errorf("Player: Event handler failed: %v", r))
+				}
+			}()
+
+			callback(data)
+		}(callback)
+	}
+}
+
+// subscribed returns whether any callbacks are registered for the event.
+func subscribed(event Event) bool {
+	hooks.mutex.Lock()
+	defer hooks.mutex.Unlock()
+
+	return len(hooks.callbacks[event]) > 0
+}
+
+// emitTrackEvent sends a track event for the queue entry with the provided ID.
+// If the ID is negative, the currently playing entry is used.
+func emitTrackEvent(event Event, id int) {
+	if !subscribed(event) {
+		return
+	}
+
+	for _, track := range player.queue.getQueueData() {
+		if (id < 0 && !track.Playing) || (id >= 0 && track.ID != id) {
+			continue
+		}
+
+		emitEvent(EventData{
+			Event: event,
+			Track: track,
+			Video: player.queue.currentVideo(track.VideoID),
+		})
+
+		return
+	}
+}
+
+// emitPauseEvent sends a pause event if the paused state of the playback has changed.
+func emitPauseEvent() {
+	if !subscribed(Paused) {
+		return
+	}
+
+	paused := mp.Player().Paused()
+
+	hooks.mutex.Lock()
+	changed := hooks.pauseKnown && hooks.paused != paused
+	hooks.paused, hooks.pauseKnown = paused, true
+	hooks.mutex.Unlock()
+
+	if changed {
+		emitEvent(EventData{Event: Paused, Paused: paused})
+	}
+}
+
+// emitQueueEvent sends a queue event with the provided playlist data.
+func emitQueueEvent(pldata []map[string]interface{}) {
+	if !subscribed(QueueChanged) {
+		return
+	}
+
+	queue := make([]QueueData, len(pldata))
+	for i, data := range pldata {
+		queue[i] = player.queue.getData(i, data)
+	}
+
+	emitEvent(EventData{Event: QueueChanged, Queue: queue})
+}
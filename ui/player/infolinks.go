@@ -0,0 +1,137 @@
+package player
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+	"github.com/darkhz/tview"
+)
+
+// infoLink describes a URL or a timestamp within the description of the info view.
+type infoLink struct {
+	text      string
+	timestamp bool
+}
+
+// infoLinkRegex matches URLs and timestamps in the '[hh:]mm:ss' format.
+var infoLinkRegex = regexp.MustCompile(`https?://[^\s<>"]+|\b(?:\d{1,2}:)?\d{1,2}:\d{2}\b`)
+
+// descriptionLinks returns the escaped description with each URL and timestamp
+// marked as a region, and the links in the order of their regions.
+func descriptionLinks(description string) (string, []infoLink) {
+	var text strings.Builder
+	var links []infoLink
+
+	last := 0
+	for _, match := range infoLinkRegex.FindAllStringIndex(description, -1) {
+		start, end := match[0], match[1]
+
+		link := infoLink{text: description[start:end]}
+		if strings.HasPrefix(link.text, "http") {
+			trimmed := strings.TrimRight(link.text, ".,;:!?)]}'")
+			end -= len(link.text) - len(trimmed)
+			link.text = trimmed
+		} else {
+			link.timestamp = true
+		}
+
+		text.WriteString(tview.Escape(description[last:start]))
+		text.WriteString(`["` + strconv.Itoa(len(links)) + `"][aqua::bu]`)
+		text.WriteString(tview.Escape(link.text))
+		text.WriteString(`[-::b][""]`)
+
+		links = append(links, link)
+		last = end
+	}
+
+	text.WriteString(tview.Escape(description[last:]))
+
+	return text.String(), links
+}
+
+// selectInfoLink highlights the next or previous link in the info view.
+func selectInfoLink(next bool) {
+	if !IsInfoShown() || len(player.links) == 0 {
+		return
+	}
+
+	index := -1
+	if highlighted := player.info.GetHighlights(); len(highlighted) > 0 {
+		index, _ = strconv.Atoi(highlighted[0])
+	}
+
+	if next {
+		index++
+	} else {
+		index--
+	}
+
+	switch {
+	case index < 0:
+		index = len(player.links) - 1
+
+	case index >= len(player.links):
+		index = 0
+	}
+
+	player.info.Highlight(strconv.Itoa(index))
+	player.info.ScrollToHighlight()
+}
+
+// openInfoLink opens the highlighted link in the info view. Timestamps are seeked to
+// within the currently playing video, YouTube links are queued with the media type of
+// the currently playing track, and other links are opened in the browser.
+func openInfoLink() {
+	highlighted := player.info.GetHighlights()
+	if !IsInfoShown() || len(highlighted) == 0 {
+		return
+	}
+
+	index, err := strconv.Atoi(highlighted[0])
+	if err != nil || index < 0 || index >= len(player.links) {
+		return
+	}
+
+	go openLink(player.infoID, player.links[index])
+}
+
+// openLink opens the provided link from the info view of the provided video.
+func openLink(id string, link infoLink) {
+	if link.timestamp {
+		position, err := parseTimestamp(link.text)
+		if err != nil {
+			return
+		}
+
+		if id != currentVideoID() {
+			app.ShowError(fmt.Errorf("Player: Timestamps can only be seeked to in the playing video"))
+			return
+		}
+
+		mp.Player().SeekToPosition(position)
+		app.ShowInfo("Player: Seeked to "+link.text, false)
+
+		return
+	}
+
+	if uri, err := url.Parse(link.text); err == nil &&
+		(strings.HasSuffix(uri.Hostname(), "youtube.com") || uri.Hostname() == "youtu.be") {
+		if info, err := urlInfo(link.text); err == nil {
+			Play(mp.Player().MediaType() == "Audio", false, info)
+			return
+		}
+	}
+
+	if err := utils.OpenInBrowser(link.text); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	app.ShowInfo("Player: Opened "+link.text, false)
+}
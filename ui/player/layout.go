@@ -0,0 +1,142 @@
+package player
+
+import (
+	"fmt"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+	"github.com/darkhz/tview"
+)
+
+// playerLayouts lists the player layouts, in the order they are cycled through.
+// The compact layout combines the title and the progress bar into one line,
+// and the expanded layout adds a line with the author and video statistics.
+var playerLayouts = []string{"default", "compact", "expanded"}
+
+// layout returns the current player layout.
+func layout() string {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	for _, l := range playerLayouts {
+		if l == cmd.Settings.PlayerLayout {
+			return l
+		}
+	}
+
+	return playerLayouts[0]
+}
+
+// layoutHeight returns the height of the player for the current layout.
+func layoutHeight() int {
+	switch layout() {
+	case "compact":
+		return 1
+
+	case "expanded":
+		return 3
+	}
+
+	return 2
+}
+
+// cycleLayout switches to the next player layout, and stores it in the settings.
+func cycleLayout() {
+	current := layout()
+
+	for i, l := range playerLayouts {
+		if l != current {
+			continue
+		}
+
+		player.mutex.Lock()
+		cmd.Settings.PlayerLayout = playerLayouts[(i+1)%len(playerLayouts)]
+		player.mutex.Unlock()
+
+		break
+	}
+
+	applyLayout()
+
+	app.ShowInfo("Player: Layout set to "+layout(), false)
+}
+
+// applyLayout arranges the player according to the current layout,
+// and resizes it within the application layout if it is shown.
+func applyLayout() {
+	player.flex.Clear()
+
+	switch layout() {
+	case "compact":
+		player.flex.AddItem(player.desc, 1, 0, false)
+
+	case "expanded":
+		player.flex.
+			AddItem(player.title, 1, 0, false).
+			AddItem(player.stats, 1, 0, false).
+			AddItem(player.desc, 1, 0, false)
+
+	default:
+		player.flex.
+			AddItem(player.title, 1, 0, false).
+			AddItem(player.desc, 1, 0, false)
+	}
+
+	if playingStatus() {
+		app.UI.Layout.ResizeItem(player.flex, layoutHeight(), 0)
+		app.ResizeModal()
+	}
+
+	if IsInfoShown() {
+		Resize(0, struct{}{})
+	}
+
+	sendPlayerEvents()
+}
+
+// layoutWidth returns the width available to the progress bar
+// within the provided width, for the current layout.
+func layoutWidth(width int) int {
+	if layout() == "compact" {
+		return width / 2
+	}
+
+	return width
+}
+
+// renderLayout renders the title and progress of the currently playing
+// track according to the current layout.
+func renderLayout(id, title, progress string, width int) {
+	switch layout() {
+	case "compact":
+		if limit := width / 4; len([]rune(title)) > limit && limit > 3 {
+			title = string([]rune(title)[:limit-3]) + "..."
+		}
+
+		player.desc.SetText("[::b]" + tview.Escape(title) + "[-:-:-] " + progress)
+
+		return
+
+	case "expanded":
+		player.stats.SetText(statsText(id))
+	}
+
+	player.desc.SetText(progress)
+	player.title.SetText("[::b]" + tview.Escape(title))
+}
+
+// statsText returns the author and statistics of the provided video.
+func statsText(id string) string {
+	video := player.queue.currentVideo(id)
+	if video == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"[purple::b]%s[-:-:-] / [aqua::b]%s views[-:-:-] / [red::b]%s likes[-:-:-]",
+		tview.Escape(video.Author),
+		utils.FormatNumber(video.ViewCount),
+		utils.FormatNumber(video.LikeCount),
+	)
+}
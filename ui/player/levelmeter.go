@@ -0,0 +1,54 @@
+package player
+
+import (
+	"math"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// levelBlocks lists the blocks which represent the audio level, from the lowest to the highest.
+var levelBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// levelMeterFloor is the audio level in decibels at and below which the lowest block is shown.
+const levelMeterFloor = -50.0
+
+// setupLevelMeter adds the audio level filter to the player,
+// if the 'level-meter' option is set.
+func setupLevelMeter() {
+	if !cmd.IsOptionEnabled("level-meter") {
+		return
+	}
+
+	if err := mp.Player().SetAudioLevelMeter(true); err != nil {
+		utils.LogWarnf("Player: Audio level meter is unavailable: %v", err)
+	}
+}
+
+// levelMeter returns a block representing the current audio level. If the level
+// meter is disabled or the level is unavailable, an empty string is returned.
+func levelMeter() string {
+	if !cmd.IsOptionEnabled("level-meter") {
+		return ""
+	}
+
+	level, ok := mp.Player().AudioLevel()
+	if !ok || math.IsNaN(level) {
+		return ""
+	}
+
+	return string(levelBlocks[levelIndex(level)])
+}
+
+// levelIndex returns the index of the block representing the provided audio level.
+func levelIndex(level float64) int {
+	if math.IsInf(level, -1) || level <= levelMeterFloor {
+		return 0
+	}
+	if level >= 0 {
+		return len(levelBlocks) - 1
+	}
+
+	return int((1 - level/levelMeterFloor) * float64(len(levelBlocks)-1))
+}
@@ -0,0 +1,46 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+)
+
+func TestCancelLoads(t *testing.T) {
+	load := loadContext()
+	if load != loadContext() {
+		t.Fatal("loadContext() returned a different context before the queue was cleared")
+	}
+
+	cancelLoads()
+
+	if !errors.Is(load.Err(), context.Canceled) {
+		t.Errorf("previous load context error = %v, want %v", load.Err(), context.Canceled)
+	}
+
+	next := loadContext()
+	if next == load || next.Err() != nil {
+		t.Errorf("load context after clearing is %v with error %v, want a new active context", next, next.Err())
+	}
+
+	cancelLoads()
+
+	if !errors.Is(next.Err(), context.Canceled) {
+		t.Errorf("second load context error = %v, want %v", next.Err(), context.Canceled)
+	}
+}
+
+func TestAppendVideoCanceled(t *testing.T) {
+	load := loadContext()
+	cancelLoads()
+
+	// The media player is not started in tests, so this would panic
+	// if the video were appended after the queue was cleared.
+	err := appendVideo(load, inv.VideoData{Title: "Video"}, false, -1, mp.Trim{}, []string{"https://example.com/video"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("appendVideo() error = %v, want %v", err, context.Canceled)
+	}
+}
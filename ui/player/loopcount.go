@@ -0,0 +1,179 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// LoopCount stores the number of times the queue is still to be repeated,
+// when the queue is looped a set number of times instead of indefinitely.
+type LoopCount struct {
+	remaining int
+	reshuffle bool
+
+	mutex sync.Mutex
+}
+
+var loopCount LoopCount
+
+// loopCountInput displays an inputbox and loops the queue the entered number of times.
+func loopCountInput() {
+	dofunc := func(text string) {
+		count, err := parseLoopCount(text)
+		if err != nil {
+			app.ShowError(err)
+			return
+		}
+
+		setLoopCount(count)
+
+		if count == 0 {
+			app.ShowInfo("Player: Queue loop count cleared", false)
+		} else {
+			app.ShowInfo(fmt.Sprintf("Player: Repeating the queue %d times", count), false)
+		}
+
+		sendPlayerEvents()
+	}
+
+	app.UI.Status.SetInput("Repeat queue (times):", 0, true, dofunc, nil)
+}
+
+// setLoopCount loops the queue the provided number of times, after which the
+// playback stops at the end of the queue. A count of 0 disables the looping.
+func setLoopCount(count int) {
+	if repeatOnceStatus() {
+		setRepeatOnce(false)
+	}
+
+	loop := "no"
+	if count > 0 {
+		loop = "yes"
+	}
+
+	loopCount.set(count)
+
+	mp.Player().Set("loop-file", "no")
+	mp.Player().Set("loop-playlist", loop)
+}
+
+// clearLoopCount stops counting the queue loops, so that the queue
+// is looped according to the loop mode of the media player.
+func clearLoopCount() {
+	loopCount.set(0)
+}
+
+// remainingLoops returns the number of times the queue is still to be repeated.
+// If the queue is not being looped, 0 is returned.
+func remainingLoops() int {
+	if mp.Player().LoopMode() != "loop-playlist" {
+		return 0
+	}
+
+	loopCount.mutex.Lock()
+	defer loopCount.mutex.Unlock()
+
+	return loopCount.remaining
+}
+
+// countLoop is called when the track with the provided playlist entry ID has ended.
+// If it is the last track in the queue, the queue has wrapped around, and the loop
+// count is decremented. Once the last repetition has started, the queue is no longer
+// looped, so that the playback stops at the end of the queue.
+func countLoop(id int) {
+	if remainingLoops() == 0 || !isLastEntry(id) {
+		return
+	}
+
+	if loopCount.countDown(cmd.IsOptionEnabled("loop-reshuffle")) == 0 {
+		mp.Player().Set("loop-playlist", "no")
+	}
+
+	sendPlayerEvents()
+}
+
+// reshuffleLoop reshuffles the queue once a new repetition of the queue has
+// started playing, if the 'loop-reshuffle' option is enabled. The playing track
+// is kept at the top of the queue, so that the repetition is not cut short.
+func reshuffleLoop() {
+	if loopCount.takeReshuffle() {
+		mp.Player().ReshuffleKeepingCurrent()
+	}
+}
+
+// isLastEntry returns whether the track with the provided playlist
+// entry ID is the last track in the queue.
+func isLastEntry(id int) bool {
+	count := mp.Player().QueueCount()
+	if id < 0 || count <= 0 {
+		return false
+	}
+
+	last, err := mp.Player().Get(fmt.Sprintf("playlist/%d/id", count-1))
+	if err != nil {
+		return false
+	}
+
+	lastID, ok := last.(float64)
+
+	return ok && int(lastID) == id
+}
+
+// loadLoopCount restores the loop count from the provided player state.
+func loadLoopCount(state string) {
+	count, err := parseLoopCount(strings.TrimPrefix(state, "loop-count "))
+	if err == nil && count > 0 {
+		setLoopCount(count)
+	}
+}
+
+// parseLoopCount parses the provided number of times to loop the queue.
+func parseLoopCount(text string) (int, error) {
+	count, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("Player: Invalid loop count %s", text)
+	}
+
+	return count, nil
+}
+
+// set sets the number of times the queue is to be repeated.
+func (l *LoopCount) set(count int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.remaining, l.reshuffle = count, false
+}
+
+// countDown decrements the number of times the queue is still to be repeated,
+// once the queue has wrapped around, and marks the new repetition to be reshuffled
+// if reshuffle is set. It returns the number of remaining repetitions.
+func (l *LoopCount) countDown(reshuffle bool) int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.remaining > 0 {
+		l.remaining--
+	}
+	l.reshuffle = reshuffle
+
+	return l.remaining
+}
+
+// takeReshuffle returns whether the new repetition of the queue
+// is to be reshuffled, and clears the mark.
+func (l *LoopCount) takeReshuffle() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	reshuffle := l.reshuffle
+	l.reshuffle = false
+
+	return reshuffle
+}
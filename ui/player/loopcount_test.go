@@ -0,0 +1,74 @@
+package player
+
+import "testing"
+
+func TestParseLoopCount(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    int
+		wantErr bool
+	}{
+		{text: "3", want: 3},
+		{text: " 10 ", want: 10},
+		{text: "0", want: 0},
+		{text: "-1", wantErr: true},
+		{text: "twice", wantErr: true},
+		{text: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		count, err := parseLoopCount(test.text)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseLoopCount(%q) error = %v, want error %v", test.text, err, test.wantErr)
+			continue
+		}
+		if count != test.want {
+			t.Errorf("parseLoopCount(%q) = %d, want %d", test.text, count, test.want)
+		}
+	}
+}
+
+func TestLoopCountDown(t *testing.T) {
+	var l LoopCount
+
+	l.set(3)
+
+	for _, want := range []int{2, 1, 0, 0} {
+		if remaining := l.countDown(false); remaining != want {
+			t.Errorf("countDown() = %d, want %d", remaining, want)
+		}
+	}
+}
+
+func TestLoopCountReshuffle(t *testing.T) {
+	var l LoopCount
+
+	l.set(2)
+
+	if l.takeReshuffle() {
+		t.Error("queue is reshuffled before it has wrapped around")
+	}
+
+	l.countDown(true)
+	if !l.takeReshuffle() {
+		t.Error("queue is not reshuffled after it has wrapped around")
+	}
+	if l.takeReshuffle() {
+		t.Error("queue is reshuffled twice for the same repetition")
+	}
+
+	l.countDown(true)
+	l.set(0)
+	if l.takeReshuffle() {
+		t.Error("queue is reshuffled after the loop count was cleared")
+	}
+	if l.remaining != 0 {
+		t.Errorf("remaining = %d after clearing, want 0", l.remaining)
+	}
+
+	l.set(2)
+	l.countDown(false)
+	if l.takeReshuffle() {
+		t.Error("queue is reshuffled with reshuffling disabled")
+	}
+}
@@ -0,0 +1,288 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+	"github.com/darkhz/tview"
+)
+
+// Lyrics stores the lyrics of the video shown in the info view,
+// and whether they are shown in place of the video's description.
+type Lyrics struct {
+	shown, loaded bool
+	id, plain     string
+	lines         []lyricLine
+	current       int
+
+	cancel context.CancelFunc
+	mutex  sync.Mutex
+}
+
+// lyricLine describes a line of synced lyrics, along with
+// the position in milliseconds from which it is sung.
+type lyricLine struct {
+	position int64
+	text     string
+}
+
+var (
+	lyrics Lyrics
+
+	// lrcTimestampRegex matches a timestamp at the start of a line in the LRC format.
+	lrcTimestampRegex = regexp.MustCompile(`^\[(\d+):(\d{1,2}(?:\.\d+)?)\]`)
+
+	// lyricsTitleRegex matches the bracketed parts of a title, such as "(Official Video)".
+	lyricsTitleRegex = regexp.MustCompile(`\s*[\(\[][^\)\]]*[\)\]]`)
+)
+
+// toggleLyrics shows the lyrics of the video in place of its description
+// in the info view, or shows the description again. The info view is
+// shown if it is hidden.
+func toggleLyrics() {
+	lyrics.mutex.Lock()
+	lyrics.shown = !lyrics.shown
+	shown := lyrics.shown
+	lyrics.mutex.Unlock()
+
+	if shown {
+		app.ShowInfo("Player: Showing lyrics", false)
+	} else {
+		app.ShowInfo("Player: Showing description", false)
+	}
+
+	if !IsInfoShown() {
+		ToggleInfo()
+		return
+	}
+
+	refreshInfoText()
+}
+
+// refreshInfoText renders the text of the info view again,
+// without reloading the video information or the image.
+func refreshInfoText() {
+	video := infoVideo()
+	if video == nil {
+		return
+	}
+
+	player.info.SetText(infoHeader(video) + infoBody(video))
+	player.info.ScrollToBeginning()
+}
+
+// lyricsText returns the lyrics of the provided video, with each line of synced
+// lyrics marked as a region. If the lyrics are not shown or were not found,
+// false is returned, so that the description is shown instead.
+func lyricsText(video *inv.VideoData) (string, bool) {
+	lyrics.mutex.Lock()
+	defer lyrics.mutex.Unlock()
+
+	if !lyrics.shown {
+		return "", false
+	}
+
+	if lyrics.id != video.VideoID {
+		loadLyrics(video.VideoID, video.Title, video.Author)
+	}
+	if !lyrics.loaded {
+		return "[::b]Loading lyrics...", true
+	}
+
+	lyrics.current = -1
+
+	if lyrics.lines != nil {
+		var text strings.Builder
+
+		for i, line := range lyrics.lines {
+			text.WriteString(`["lyric-` + strconv.Itoa(i) + `"][::b]`)
+			text.WriteString(tview.Escape(line.text))
+			text.WriteString("[-:-:-][\"\"]\n")
+		}
+
+		return text.String(), true
+	}
+
+	if lyrics.plain != "" {
+		return "[::b]" + tview.Escape(lyrics.plain), true
+	}
+
+	return "", false
+}
+
+// loadLyrics loads the lyrics of the provided video in the background, and
+// renders the info view once they are loaded. This must be called with the
+// lyrics mutex held.
+func loadLyrics(id, title, author string) {
+	if lyrics.cancel != nil {
+		lyrics.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lyrics.id, lyrics.loaded, lyrics.cancel = id, false, cancel
+	lyrics.plain, lyrics.lines = "", nil
+
+	go func() {
+		data, err := cachedLyrics(ctx, id, title, author)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			app.ShowError(err)
+		}
+
+		lyrics.mutex.Lock()
+		if lyrics.id != id {
+			lyrics.mutex.Unlock()
+			return
+		}
+
+		lyrics.loaded = true
+		lyrics.plain, lyrics.lines = data.Plain, parseLRC(data.Synced)
+		lyrics.mutex.Unlock()
+
+		if err == nil && data.Plain == "" && data.Synced == "" {
+			app.ShowInfo("Player: No lyrics found for "+title+", showing description", false)
+		}
+
+		app.UI.QueueUpdateDraw(func() {
+			if video := infoVideo(); video != nil && video.VideoID == id {
+				refreshInfoText()
+			}
+		})
+	}()
+}
+
+// cachedLyrics returns the lyrics of the provided video from the lyrics cache. If the
+// lyrics are not cached, they are fetched from the provider and cached. Tracks without
+// lyrics are cached as well, so that the provider is not queried for them again.
+func cachedLyrics(ctx context.Context, id, title, author string) (inv.LyricsData, error) {
+	var data inv.LyricsData
+
+	dir, err := cmd.LyricsDir()
+	if err != nil {
+		return data, err
+	}
+
+	path := filepath.Join(dir, id+".json")
+
+	if cached, err := os.ReadFile(path); err == nil {
+		if err := utils.JSON().Unmarshal(cached, &data); err == nil {
+			return data, nil
+		}
+	}
+
+	track, artist := lyricsQuery(title, author)
+
+	data, err = inv.Lyrics(ctx, lyricsProvider(), track, artist)
+	if err != nil {
+		return data, fmt.Errorf("Player: Unable to fetch lyrics for %s: %w", title, err)
+	}
+
+	if cached, err := utils.JSON().Marshal(data); err == nil {
+		if err := utils.WriteFileAtomic(path, cached, 0644); err != nil {
+			utils.LogWarnf("Player: Unable to cache lyrics for %s: %v", id, err)
+		}
+	}
+
+	return data, nil
+}
+
+// lyricsProvider returns the lyrics endpoint from the 'lyrics-provider' option.
+func lyricsProvider() string {
+	if provider := cmd.GetOptionValue("lyrics-provider"); provider != "" {
+		return provider
+	}
+
+	return inv.LyricsAPI
+}
+
+// lyricsQuery returns the track name and artist to search lyrics for, from the
+// provided video title and author. Titles in the "Artist - Track" format are split,
+// and the " - Topic" suffix of auto-generated music channels is removed.
+func lyricsQuery(title, author string) (string, string) {
+	track, artist := title, strings.TrimSuffix(author, " - Topic")
+
+	if parts := strings.SplitN(title, " - ", 2); len(parts) == 2 {
+		artist, track = parts[0], parts[1]
+	}
+
+	return strings.TrimSpace(lyricsTitleRegex.ReplaceAllString(track, "")), strings.TrimSpace(artist)
+}
+
+// parseLRC returns the lines of the provided lyrics in the LRC format, sorted
+// by their positions. Lines with multiple timestamps are repeated for each
+// timestamp, and lines without timestamps, such as metadata tags, are skipped.
+func parseLRC(text string) []lyricLine {
+	var lines []lyricLine
+
+	for _, line := range strings.Split(text, "\n") {
+		var positions []int64
+
+		line = strings.TrimSpace(line)
+
+		for {
+			match := lrcTimestampRegex.FindStringSubmatch(line)
+			if match == nil {
+				break
+			}
+
+			minutes, _ := strconv.ParseInt(match[1], 10, 64)
+			seconds, _ := strconv.ParseFloat(match[2], 64)
+
+			positions = append(positions, minutes*60000+int64(seconds*1000))
+			line = line[len(match[0]):]
+		}
+
+		for _, position := range positions {
+			lines = append(lines, lyricLine{position: position, text: strings.TrimSpace(line)})
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].position < lines[j].position
+	})
+
+	return lines
+}
+
+// syncLyrics highlights the line of the synced lyrics which is sung at the provided
+// position in seconds, if the lyrics of the provided video are shown.
+func syncLyrics(id string, position int64) {
+	lyrics.mutex.Lock()
+	defer lyrics.mutex.Unlock()
+
+	if !lyrics.shown || !lyrics.loaded || lyrics.lines == nil ||
+		lyrics.id != id || player.infoID != id {
+		return
+	}
+
+	current := sort.Search(len(lyrics.lines), func(i int) bool {
+		return lyrics.lines[i].position > position*1000
+	}) - 1
+	if current == lyrics.current {
+		return
+	}
+
+	lyrics.current = current
+	if current < 0 {
+		player.info.Highlight()
+		return
+	}
+
+	player.info.Highlight("lyric-" + strconv.Itoa(current))
+	player.info.ScrollToHighlight()
+}
@@ -0,0 +1,99 @@
+package player
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// Notification describes the "now playing" desktop notification.
+type Notification struct {
+	timer *time.Timer
+	mutex sync.Mutex
+}
+
+// notifyDelay is the duration for which track changes are
+// debounced before a notification is shown.
+const notifyDelay = 2 * time.Second
+
+var notification Notification
+
+// notifyPlaying shows a desktop notification for the currently playing track,
+// if notifications are enabled. Rapid track changes are debounced, so that
+// only the track which is playing after the delay is notified.
+func notifyPlaying() {
+	if !cmd.IsOptionEnabled("notify") {
+		return
+	}
+
+	notification.mutex.Lock()
+	defer notification.mutex.Unlock()
+
+	if notification.timer != nil {
+		notification.timer.Stop()
+	}
+
+	notification.timer = time.AfterFunc(notifyDelay, sendNowPlaying)
+}
+
+// sendNowPlaying sends the notification with the title, author
+// and thumbnail of the currently playing track.
+func sendNowPlaying() {
+	var id, title, author string
+
+	pos := mp.Player().QueuePosition()
+	if pos < 0 {
+		return
+	}
+
+	title = mp.Player().Title(pos)
+	if data := utils.GetDataFromURL(title); data != nil {
+		id, title = data.Get("id"), data.Get("title")
+		author = data.Get("author")
+	}
+
+	sendNotification(title, author, notifyThumbnail(id))
+}
+
+// notifyThumbnail returns the path to the cached thumbnail of the provided video.
+// If the thumbnail is not cached, it is downloaded first.
+func notifyThumbnail(id string) string {
+	if id == "" {
+		return ""
+	}
+
+	thumbpath := filepath.Join(os.TempDir(), "invidtui-"+id+".jpg")
+	if _, err := os.Stat(thumbpath); err == nil {
+		return thumbpath
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := inv.VideoThumbnail(ctx, id, "mqdefault.jpg")
+	if err != nil {
+		return ""
+	}
+	defer res.Body.Close()
+
+	thumbnail, err := os.Create(thumbpath)
+	if err != nil {
+		return ""
+	}
+	defer thumbnail.Close()
+
+	if _, err := io.Copy(thumbnail, res.Body); err != nil {
+		os.Remove(thumbpath)
+		return ""
+	}
+
+	return thumbpath
+}
@@ -0,0 +1,12 @@
+//go:build notify
+// +build notify
+
+package player
+
+import "github.com/gen2brain/beeep"
+
+// sendNotification shows a desktop notification.
+// Any errors from the notification backend are ignored.
+func sendNotification(title, body, icon string) {
+	beeep.Notify(title, body, icon)
+}
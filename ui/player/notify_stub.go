@@ -0,0 +1,8 @@
+//go:build !notify
+// +build !notify
+
+package player
+
+// sendNotification does nothing, since notification
+// support was not included in this build.
+func sendNotification(title, body, icon string) {}
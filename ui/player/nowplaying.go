@@ -0,0 +1,88 @@
+package player
+
+import (
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/tview"
+	"github.com/gdamore/tcell/v2"
+)
+
+// NowPlaying describes the layout of the full-screen view of the
+// currently playing track, which shows the track information
+// along with a larger display of the track's progress.
+type NowPlaying struct {
+	shown, info bool
+
+	flex     *tview.Flex
+	progress *tview.TextView
+}
+
+// setupNowPlaying sets up the now playing view.
+func setupNowPlaying() {
+	player.nowPlaying.progress = tview.NewTextView()
+	player.nowPlaying.progress.SetDynamicColors(true)
+	player.nowPlaying.progress.SetTextAlign(tview.AlignCenter)
+	player.nowPlaying.progress.SetBackgroundColor(tcell.ColorDefault)
+
+	player.nowPlaying.flex = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(player.region, 0, 1, false).
+		AddItem(app.HorizontalLine(), 1, 0, false).
+		AddItem(player.nowPlaying.progress, 4, 0, false)
+	player.nowPlaying.flex.SetBackgroundColor(tcell.ColorDefault)
+}
+
+// toggleNowPlaying shows or hides the now playing view. When the view is
+// hidden, the information view is restored to its previous state.
+func toggleNowPlaying() {
+	if player.nowPlaying.shown {
+		hideNowPlaying()
+		return
+	}
+
+	if !playingStatus() {
+		return
+	}
+
+	player.nowPlaying.shown = true
+	player.nowPlaying.info = player.toggle
+
+	if !player.toggle {
+		player.toggle = true
+		player.infoID = ""
+	}
+
+	app.UI.Region.Clear().
+		AddItem(player.nowPlaying.flex, 0, 1, false)
+	app.UI.SetFocus(player.info)
+
+	sendPlayerEvents()
+}
+
+// hideNowPlaying hides the now playing view.
+func hideNowPlaying() {
+	player.nowPlaying.shown = false
+
+	if player.nowPlaying.info {
+		player.toggle = false
+		ToggleInfo()
+	} else {
+		ToggleInfo(struct{}{})
+	}
+
+	app.SetPrimaryFocus()
+}
+
+// renderNowPlaying renders the title, author and progress of the
+// currently playing track within the now playing view, if it is shown.
+func renderNowPlaying(id, title, progress string) {
+	if !player.nowPlaying.shown {
+		return
+	}
+
+	text := "[::b]" + tview.Escape(title) + "[-:-:-]\n"
+	if video := player.queue.currentVideo(id); video != nil && video.Author != "" {
+		text += "[purple::b]" + tview.Escape(video.Author) + "[-:-:-]"
+	}
+
+	player.nowPlaying.progress.SetText(text + "\n\n" + progress)
+}
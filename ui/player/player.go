@@ -2,8 +2,16 @@ package player
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"image/jpeg"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -15,10 +23,13 @@ import (
 	inv "github.com/darkhz/invidtui/invidious"
 	mp "github.com/darkhz/invidtui/mediaplayer"
 	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/ui/view"
 	"github.com/darkhz/invidtui/utils"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 	"golang.org/x/sync/semaphore"
+
+	_ "golang.org/x/image/webp"
 )
 
 // Player stores the layout for the player.
@@ -27,9 +38,16 @@ type Player struct {
 
 	infoID, thumbURI      string
 	init, playing, toggle bool
+	repeatOnce, hidden    bool
+	autoplay              bool
+	pauseAt               int
+	trims                 map[string]mp.Trim
 	width                 int
 	states                []string
+	links                 []infoLink
 	history               History
+	favorites             Favorites
+	nowPlaying            NowPlaying
 
 	channel chan bool
 	events  chan struct{}
@@ -39,12 +57,20 @@ type Player struct {
 	info         *tview.TextView
 	quality      *tview.DropDown
 	title, desc  *tview.TextView
+	stats        *tview.TextView
 
 	lock, render          *semaphore.Weighted
+	loadCtx               context.Context
 	infoCancel, imgCancel context.CancelFunc
-	mutex                 sync.Mutex
+	loadCancel            context.CancelFunc
+	mutex, load           sync.Mutex
+	updates               sync.WaitGroup
 }
 
+// stopTimeout is the maximum time to wait for the player
+// updates to stop, when the player is stopped.
+const stopTimeout = 3 * time.Second
+
 var player Player
 
 // setup sets up the player.
@@ -54,6 +80,7 @@ func setup() {
 	}
 
 	player.init = true
+	player.pauseAt = -1
 
 	player.channel = make(chan bool, 10)
 	player.events = make(chan struct{}, 100)
@@ -66,12 +93,18 @@ func setup() {
 	player.desc.SetBackgroundColor(tcell.ColorDefault)
 	player.title.SetBackgroundColor(tcell.ColorDefault)
 
+	player.stats = tview.NewTextView()
+	player.stats.SetDynamicColors(true)
+	player.stats.SetTextAlign(tview.AlignCenter)
+	player.stats.SetBackgroundColor(tcell.ColorDefault)
+
 	player.image = tview.NewImage()
 	player.image.SetBackgroundColor(tcell.ColorDefault)
-	player.image.SetDithering(tview.DitheringFloydSteinberg)
+	player.image.SetDithering(imageDithering())
 
 	player.info = tview.NewTextView()
 	player.info.SetDynamicColors(true)
+	player.info.SetRegions(true)
 	player.info.SetTextAlign(tview.AlignCenter)
 	player.info.SetBackgroundColor(tcell.ColorDefault)
 
@@ -86,36 +119,82 @@ func setup() {
 		SetBorder(true)
 
 	player.flex = tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(player.title, 1, 0, false).
-		AddItem(player.desc, 1, 0, false)
+		SetDirection(tview.FlexRow)
 	player.flex.SetBackgroundColor(tcell.ColorDefault)
+	applyLayout()
 
 	player.region = tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(player.image, 0, 1, false).
-		AddItem(player.info, 0, 1, false)
+		SetDirection(tview.FlexRow)
+	if IsImageEnabled() {
+		player.region.AddItem(player.image, 0, 1, false)
+	}
+	player.region.AddItem(player.info, 0, 1, false)
 	player.region.SetBackgroundColor(tcell.ColorDefault)
 
-	player.lock = semaphore.NewWeighted(10)
+	setupNowPlaying()
+
+	player.lock = semaphore.NewWeighted(loadConcurrency())
 	player.render = semaphore.NewWeighted(1)
 }
 
+// skipCount returns the number of tracks to skip in the queue at once.
+// If the configured value is invalid, the default is used.
+func skipCount() int {
+	count, err := strconv.Atoi(cmd.GetOptionValue("skip-count"))
+	if err != nil || count < 1 {
+		return 5
+	}
+
+	return count
+}
+
+// loadConcurrency returns the maximum number of entries that can be
+// loaded in parallel. If the configured value is invalid, the default is used.
+func loadConcurrency() int64 {
+	concurrency, err := strconv.ParseInt(cmd.GetOptionValue("load-concurrency"), 10, 64)
+	if err != nil || concurrency < 1 {
+		return 10
+	}
+
+	return concurrency
+}
+
+// IsImageEnabled returns whether thumbnails are shown in the information view.
+// If the 'disable-images' option is set, only the text information is shown.
+func IsImageEnabled() bool {
+	return !cmd.IsOptionEnabled("disable-images")
+}
+
 // Start starts the player and loads its history and states.
 func Start() {
 	setup()
 
 	loadState()
+	setupCache()
+	setupLevelMeter()
 	loadHistory()
+	loadFavorites()
+
+	setupTrackHook()
+	setupDowngrade()
+	startAutosave()
+	startStatusQuery()
+
+	go startupAction()
 
 	go playingStatusCheck()
 	go monitorMPVEvents()
 	go player.queue.Start()
 }
 
-// Stop stops the player.
+// Stop stops the player. The player updates are stopped first,
+// so that the player states are not modified while the settings are saved.
 func Stop() {
+	stopAutosave()
+	stopStatusQuery()
+	saveQueue()
 	sendPlayingStatus(false)
+	waitUpdates(stopTimeout)
 
 	mp.Player().Stop()
 	mp.Player().Exit()
@@ -131,13 +210,38 @@ func Show() {
 	sendPlayingStatus(true)
 
 	app.UI.QueueUpdateDraw(func() {
-		app.UI.Layout.AddItem(player.flex, 2, 0, false)
+		app.UI.Layout.AddItem(player.flex, layoutHeight(), 0, false)
 		app.ResizeModal()
 	})
 }
 
+// toggleVisible hides or shows the player without stopping the playback.
+// Unlike Hide, the player keeps playing and updating while it is hidden.
+func toggleVisible() {
+	if !playingStatus() {
+		return
+	}
+
+	player.hidden = !player.hidden
+
+	if player.hidden {
+		app.UI.Layout.RemoveItem(player.flex)
+		app.ShowInfo("Player: Hidden, playback continues", false)
+	} else {
+		app.UI.Layout.AddItem(player.flex, layoutHeight(), 0, false)
+		sendPlayerEvents()
+	}
+
+	app.ResizeModal()
+}
+
 // ToggleInfo toggle the player information view.
 func ToggleInfo(hide ...struct{}) {
+	if player.nowPlaying.shown {
+		player.nowPlaying.shown = false
+		app.SetPrimaryFocus()
+	}
+
 	if hide != nil || player.toggle {
 		player.toggle = false
 		player.infoID = ""
@@ -171,7 +275,8 @@ func ToggleInfo(hide ...struct{}) {
 	}
 }
 
-// Hide hides the player.
+// Hide hides the player and stops the playback.
+// The queue is not cleared, see closePlayer.
 func Hide() {
 	if !playingStatus() {
 		return
@@ -181,14 +286,83 @@ func Hide() {
 	sendPlayingStatus(false)
 
 	app.UI.QueueUpdateDraw(func() {
+		player.hidden = false
 		app.UI.Layout.RemoveItem(player.flex)
 		app.ResizeModal()
 	})
 
+	if repeatOnceStatus() {
+		setRepeatOnce(false)
+	}
+	clearLoopCount()
+
 	mp.Player().Stop()
+}
+
+// stopPlayer performs the action from the 'stop-action' option.
+func stopPlayer() {
+	if cmd.GetOptionValue("stop-action") == "stop" {
+		stopPlayback()
+		return
+	}
+
+	closePlayer()
+}
+
+// stopPlayback pauses the playback and rewinds the current track,
+// while keeping the queue and the player visible, so that playback can be resumed.
+func stopPlayback() {
+	if !mp.Player().Paused() {
+		mp.Player().TogglePaused()
+	}
+
+	mp.Player().SeekToPosition(0)
+	sendPlayerEvents()
+}
+
+// loadContext returns the context for loading entries into the queue.
+// It is cancelled whenever the queue is cleared.
+func loadContext() context.Context {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if player.loadCtx == nil {
+		player.loadCtx, player.loadCancel = context.WithCancel(context.Background())
+	}
+
+	return player.loadCtx
+}
+
+// clearQueue cancels any entries which are being loaded, and clears the queue.
+// Since entries are appended with the load lock held, the queue is cleared only
+// after an entry that is being appended is added, and any further entries that
+// were being loaded are not appended to the cleared queue.
+func clearQueue() {
+	player.load.Lock()
+	defer player.load.Unlock()
+
+	cancelLoads()
 	mp.Player().QueueClear()
 }
 
+// cancelLoads cancels the current load context, and replaces it
+// with a new one for entries that are loaded afterwards.
+func cancelLoads() {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if player.loadCancel != nil {
+		player.loadCancel()
+	}
+	player.loadCtx, player.loadCancel = context.WithCancel(context.Background())
+}
+
+// closePlayer stops the player updates, which hides
+// the player, stops the playback and clears the queue.
+func closePlayer() {
+	sendPlayingStatus(false)
+}
+
 // Resize resizes the player according to the screen width.
 func Resize(width int, force ...struct{}) {
 	if force != nil {
@@ -210,20 +384,62 @@ ResizePlayer:
 }
 
 // ParseQuery parses the play-audio or play-video commandline
-// parameters, and plays the provided URL.
+// parameters, and queues the provided URLs in order.
 func ParseQuery() {
 	setup()
 
-	mtype, uri, err := cmd.GetQueryParams("play")
-	if err != nil {
+	queries := cmd.GetPlayQueries()
+	if len(queries) == 0 {
+		return
+	}
+
+	go func() {
+		for _, query := range queries {
+			info, err := urlInfo(query.URL)
+			if err != nil {
+				app.ShowError(fmt.Errorf("Player: Cannot play %s: %w", query.URL, err))
+				continue
+			}
+
+			start := mp.Player().QueueCount()
+			loadSelected(info, query.Audio, false, false)
+
+			if index := playlistIndex(query.URL); info.Type == "playlist" && index > 0 {
+				switchToIndex(start, index)
+			}
+		}
+	}()
+}
+
+// switchToIndex switches playback to the entry at the provided index (starting from 1)
+// of the playlist, which was loaded into the queue from the provided start position.
+func switchToIndex(start, index int) {
+	pos := start + index - 1
+	if pos >= mp.Player().QueueCount() {
+		app.ShowError(fmt.Errorf("Player: Playlist index %d is out of range", index))
 		return
 	}
 
-	playFromURL(uri, mtype == "audio")
+	mp.Player().QueueSwitchToTrack(pos)
+	mp.Player().Play()
+
+	sendPlayerEvents()
 }
 
 // Play plays the currently selected audio/video entry.
 func Play(audio, current bool, mediaInfo ...inv.SearchData) {
+	playEntry(audio, current, false, mediaInfo...)
+}
+
+// PlayNext queues the currently selected audio/video entry
+// to play after the currently playing track.
+func PlayNext(audio bool, mediaInfo ...inv.SearchData) {
+	playEntry(audio, false, true, mediaInfo...)
+}
+
+// playEntry loads the provided or currently selected entry.
+// If next is true, the entry is inserted after the currently playing track.
+func playEntry(audio, current, next bool, mediaInfo ...inv.SearchData) {
 	var err error
 	var media string
 	var info inv.SearchData
@@ -243,12 +459,12 @@ func Play(audio, current bool, mediaInfo ...inv.SearchData) {
 		media = "video"
 	}
 
-	if info.Type == "channel" {
+	if info.Type == "channel" && info.AuthorID == "" {
 		app.ShowError(fmt.Errorf("Player: Cannot play %s for channel type", media))
 		return
 	}
 
-	go loadSelected(info, audio, current)
+	go loadSelected(info, audio, current, next)
 }
 
 // IsInfoShown returns whether the player information is shown.
@@ -276,20 +492,34 @@ func IsHistoryInputFocused() bool {
 	return player.history.input != nil && player.history.input.HasFocus()
 }
 
+// IsFavoritesInputFocused returns whether the favorites search bar is focused.
+func IsFavoritesInputFocused() bool {
+	return player.favorites.input != nil && player.favorites.input.HasFocus()
+}
+
 // Keybindings define the main player keybindings.
 func Keybindings(event *tcell.EventKey) *tcell.EventKey {
 	playerKeybindings(event)
 
-	switch cmd.KeyOperation(event, cmd.KeyContextQueue) {
+	switch operation := cmd.KeyOperation(event, cmd.KeyContextQueue); operation {
 	case cmd.KeyPlayerOpenPlaylist:
 		app.UI.FileBrowser.Show("Open playlist:", openPlaylist)
 
 	case cmd.KeyPlayerHistory:
 		showHistory()
 
+	case cmd.KeyPlayerFavorites:
+		showFavorites()
+
 	case cmd.KeyPlayerInfo:
 		ToggleInfo()
 
+	case cmd.KeyPlayerNowPlaying:
+		toggleNowPlaying()
+
+	case cmd.KeyPlayerLyrics:
+		toggleLyrics()
+
 	case cmd.KeyPlayerInfoScrollDown:
 		player.info.InputHandler()(tcell.NewEventKey(tcell.KeyDown, ' ', tcell.ModNone), nil)
 		return nil
@@ -298,15 +528,98 @@ func Keybindings(event *tcell.EventKey) *tcell.EventKey {
 		player.info.InputHandler()(tcell.NewEventKey(tcell.KeyUp, ' ', tcell.ModNone), nil)
 		return nil
 
+	case cmd.KeyPlayerInfoPageDown, cmd.KeyPlayerInfoPageUp, cmd.KeyPlayerInfoTop, cmd.KeyPlayerInfoBottom:
+		if !IsInfoShown() {
+			break
+		}
+
+		key := map[cmd.Key]tcell.Key{
+			cmd.KeyPlayerInfoPageDown: tcell.KeyPgDn,
+			cmd.KeyPlayerInfoPageUp:   tcell.KeyPgUp,
+			cmd.KeyPlayerInfoTop:      tcell.KeyHome,
+			cmd.KeyPlayerInfoBottom:   tcell.KeyEnd,
+		}[operation]
+
+		player.info.InputHandler()(tcell.NewEventKey(key, ' ', tcell.ModNone), nil)
+		return nil
+
+	case cmd.KeyPlayerInfoNextLink, cmd.KeyPlayerInfoPrevLink:
+		selectInfoLink(operation == cmd.KeyPlayerInfoNextLink)
+		return nil
+
+	case cmd.KeyPlayerInfoOpenLink:
+		openInfoLink()
+
+	case cmd.KeyPlayerInfoCopyTitle:
+		copyInfoTitle()
+
+	case cmd.KeyPlayerInfoOpenChannel:
+		openInfoChannel()
+
 	case cmd.KeyPlayerInfoChangeQuality:
 		changeImageQuality()
 
+	case cmd.KeyPlayerLoopCount:
+		loopCountInput()
+
+	case cmd.KeyPlayerShuffleSeed:
+		shuffleSeedInput()
+
+	case cmd.KeyPlayerUnshuffle:
+		if !mp.Player().IsShuffledWithSeed() {
+			app.ShowError(fmt.Errorf("Player: Queue was not shuffled with a seed"))
+			break
+		}
+
+		mp.Player().Unshuffle()
+		app.ShowInfo("Player: Restored queue order", false)
+
+	case cmd.KeyPlayerLayout:
+		cycleLayout()
+
+	case cmd.KeyPlayerCopyURL, cmd.KeyPlayerCopyURLTimestamp:
+		copyURL(operation == cmd.KeyPlayerCopyURLTimestamp)
+
+	case cmd.KeyPlayerOpenExternal:
+		openExternal()
+
 	case cmd.KeyPlayerQueueAudio, cmd.KeyPlayerQueueVideo, cmd.KeyPlayerPlayAudio, cmd.KeyPlayerPlayVideo:
 		playSelected(event.Rune())
 
+	case cmd.KeyPlayerAttachAudio:
+		playWithAudioFile()
+
+	case cmd.KeyPlayerQueueTrimmed:
+		queueTrimmed()
+
+	case cmd.KeyPlayerReplayLast:
+		replayLastEntry()
+
+	case cmd.KeyPlayerToggleVisible:
+		toggleVisible()
+
+	case cmd.KeyPlayerAudioDevices:
+		showAudioDevices()
+
+	case cmd.KeyPlayerToggleFavorite:
+		go toggleFavorite()
+
+	case cmd.KeyPlayerReload:
+		go reloadTrack()
+
+	case cmd.KeyPlayerQueueNextAudio, cmd.KeyPlayerQueueNextVideo:
+		PlayNext(operation == cmd.KeyPlayerQueueNextAudio)
+		selectNextEntry()
+
+	case cmd.KeyPlayerQueueAllAudio, cmd.KeyPlayerQueueAllVideo:
+		queueAll(operation == cmd.KeyPlayerQueueAllAudio)
+
 	case cmd.KeyQueue:
 		player.queue.Show()
 
+	case cmd.KeyPlayerToggleQueue:
+		player.queue.toggle()
+
 	case cmd.KeyAudioURL, cmd.KeyVideoURL:
 		playInputURL(event.Rune() == 'b')
 		return nil
@@ -322,7 +635,10 @@ func playerKeybindings(event *tcell.EventKey) {
 
 	switch cmd.KeyOperation(event, cmd.KeyContextPlayer) {
 	case cmd.KeyPlayerStop:
-		sendPlayingStatus(false)
+		stopPlayer()
+
+	case cmd.KeyPlayerClose:
+		closePlayer()
 
 	case cmd.KeyPlayerSeekForward:
 		mp.Player().SeekForward()
@@ -334,26 +650,83 @@ func playerKeybindings(event *tcell.EventKey) {
 		mp.Player().TogglePaused()
 
 	case cmd.KeyPlayerToggleLoop:
-		mp.Player().ToggleLoopMode()
+		toggleLoopMode()
 
 	case cmd.KeyPlayerToggleShuffle:
 		mp.Player().ToggleShuffled()
 
+	case cmd.KeyPlayerToggleAutoplay:
+		autoplayStatus(!autoplayStatus())
+
+	case cmd.KeyPlayerToggleHWDec:
+		toggleHWDec()
+
+	case cmd.KeyPlayerCycleAspect:
+		cycleAspect()
+
+	case cmd.KeyPlayerZoomIn:
+		changeZoom(zoomStep)
+
+	case cmd.KeyPlayerZoomOut:
+		changeZoom(-zoomStep)
+
+	case cmd.KeyPlayerIncreaseCache:
+		increaseCache()
+
+	case cmd.KeyPlayerLockQuality:
+		toggleQualityLock()
+
+	case cmd.KeyPlayerReshuffle:
+		mp.Player().ReshuffleKeepingCurrent()
+
 	case cmd.KeyPlayerToggleMute:
 		mp.Player().ToggleMuted()
 
+	case cmd.KeyPlayerToggleVolumeMute:
+		mp.Player().ToggleVolumeMute()
+
 	case cmd.KeyPlayerVolumeIncrease:
 		mp.Player().VolumeIncrease()
 
 	case cmd.KeyPlayerVolumeDecrease:
 		mp.Player().VolumeDecrease()
 
+	case cmd.KeyPlayerVolumeStepUp:
+		stepVolume(volumeStep)
+
+	case cmd.KeyPlayerVolumeStepDown:
+		stepVolume(-volumeStep)
+
+	case cmd.KeyPlayerVolumeSet:
+		setVolumeInput()
+
+	case cmd.KeyPlayerCommand:
+		commandInput()
+
+	case cmd.KeyPlayerAddToPlaylist:
+		addToPlaylist()
+
+	case cmd.KeyPlayerScreenshot:
+		go takeScreenshot()
+
+	case cmd.KeyPlayerRunTrackHook:
+		go testTrackHook()
+
+	case cmd.KeyPlayerDebugInfo:
+		showDebugInfo()
+
 	case cmd.KeyPlayerPrev:
 		mp.Player().Prev()
 
 	case cmd.KeyPlayerNext:
 		mp.Player().Next()
 
+	case cmd.KeyPlayerSkipBackward:
+		mp.Player().SkipBackward(skipCount())
+
+	case cmd.KeyPlayerSkipForward:
+		mp.Player().SkipForward(skipCount())
+
 	default:
 		nokey = true
 	}
@@ -363,6 +736,42 @@ func playerKeybindings(event *tcell.EventKey) {
 	}
 }
 
+// toggleLoopMode toggles the loop mode between none, loop-file,
+// loop-playlist and repeat-once. Since the media player does not
+// have a native repeat-once mode, it is handled by the player.
+func toggleLoopMode() {
+	clearLoopCount()
+
+	if repeatOnceStatus() {
+		setRepeatOnce(false)
+		return
+	}
+
+	if mp.Player().LoopMode() == "loop-playlist" {
+		mp.Player().ToggleLoopMode()
+		setRepeatOnce(true)
+
+		return
+	}
+
+	mp.Player().ToggleLoopMode()
+}
+
+// setRepeatOnce enables or disables the repeat-once mode.
+// When enabled, the currently playing track is looped once,
+// and the player is stopped after the track finishes playing.
+// If the track is switched manually, the newly playing track
+// is looped once instead.
+func setRepeatOnce(enable bool) {
+	loop := "no"
+	if enable {
+		loop = "1"
+	}
+
+	repeatOnceStatus(enable)
+	mp.Player().Set("loop-file", loop)
+}
+
 // playSelected determines the media type according
 // to the key pressed, and plays the currently selected entry.
 func playSelected(r rune) {
@@ -370,7 +779,90 @@ func playSelected(r rune) {
 	current := r == 'A' || r == 'V'
 
 	Play(audio, current)
+	selectNextEntry()
+}
+
+// shuffleSeedInput displays an inputbox and shuffles the queue with the entered seed.
+// The input is prefilled with a random seed, which can be entered to use it.
+func shuffleSeedInput() {
+	dofunc := func(text string) {
+		seed, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			app.ShowError(fmt.Errorf("Player: Invalid shuffle seed %s", text))
+			return
+		}
+
+		mp.Player().ShuffleWithSeed(seed)
+		app.ShowInfo(fmt.Sprintf("Player: Shuffled with seed %d", seed), false)
+	}
+
+	app.UI.Status.SetInput("Shuffle seed:", 0, false, dofunc, nil)
+	app.UI.Status.SetText(strconv.FormatInt(time.Now().UnixNano()%100000, 10))
+}
+
+// setVolumeInput displays an inputbox and sets the volume to the entered value.
+func setVolumeInput() {
+	dofunc := func(text string) {
+		volume, err := strconv.Atoi(strings.TrimSuffix(text, "%"))
+		if err != nil {
+			app.ShowError(fmt.Errorf("Player: Invalid volume %s", text))
+			return
+		}
+
+		mp.Player().SetVolume(volume)
+		sendPlayerEvents()
+	}
+
+	app.UI.Status.SetInput("Set volume (%):", 4, true, dofunc, nil)
+}
+
+// queueAll queues all the video and playlist entries in the focused table, in order.
+// The entries are loaded one after the other, so that the order is preserved.
+func queueAll(audio bool) {
+	var entries []inv.SearchData
+
+	table := app.FocusedTable()
+	if table == nil {
+		return
+	}
+
+	for row := 0; row < table.GetRowCount(); row++ {
+		for col := 0; col <= 1; col++ {
+			cell := table.GetCell(row, col)
+			if cell == nil {
+				continue
+			}
+
+			info, ok := cell.GetReference().(inv.SearchData)
+			if !ok {
+				continue
+			}
+
+			if info.Type == "video" || info.Type == "playlist" {
+				entries = append(entries, info)
+			}
+
+			break
+		}
+	}
+
+	if len(entries) == 0 {
+		app.ShowError(fmt.Errorf("Player: No entries to queue"))
+		return
+	}
+
+	go func() {
+		for i, info := range entries {
+			app.ShowInfo(fmt.Sprintf("Queueing %d/%d: %s", i+1, len(entries), info.Title), true)
+			loadSelected(info, audio, false, false)
+		}
+
+		app.ShowInfo(fmt.Sprintf("Queued %d entries", len(entries)), false)
+	}()
+}
 
+// selectNextEntry moves the selector to the next entry in the focused table.
+func selectNextEntry() {
 	table := app.FocusedTable()
 	if table != nil {
 		table.InputHandler()(
@@ -391,113 +883,668 @@ func playInputURL(audio bool) {
 		playFromURL(text, audio)
 	}
 
-	app.UI.Status.SetInput("Play "+media+" for video/playlist URL or ID:", 0, true, dofunc, nil)
+	app.UI.Status.SetInput("Play "+media+" for video/playlist URL or ID:", 0, true, dofunc, nil)
+}
+
+// playFromURL plays the given URL.
+func playFromURL(text string, audio bool) {
+	info, err := urlInfo(text)
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	Play(audio, false, info)
+}
+
+// urlInfo returns the video or playlist information for the provided URL.
+// If the URL is of a video within a playlist, and has a playlist index,
+// the playlist information is returned. If the URL of a video has a start
+// time or an end time, the video is trimmed to it once it is loaded.
+func urlInfo(text string) (inv.SearchData, error) {
+	id, mtype, err := utils.GetVPIDFromURL(text)
+	if err != nil {
+		return inv.SearchData{}, err
+	}
+
+	if data := queryFromURL(text); data != nil && mtype == "video" &&
+		data.Get("list") != "" && playlistIndex(text) > 0 {
+		id, mtype = data.Get("list"), "playlist"
+	}
+
+	info := inv.SearchData{
+		Title: text,
+		Type:  mtype,
+	}
+
+	if mtype == "video" {
+		info.VideoID = id
+
+		if start, end := utils.GetTimeRangeFromURL(text); start > 0 || end > 0 {
+			setTrim(id, mp.Trim{Start: start, End: end})
+		}
+	} else {
+		info.PlaylistID = id
+	}
+
+	return info, nil
+}
+
+// playlistIndex returns the playlist index from the 'index' parameter of the provided URL.
+func playlistIndex(text string) int {
+	data := queryFromURL(text)
+	if data == nil {
+		return 0
+	}
+
+	index, err := strconv.Atoi(data.Get("index"))
+	if err != nil || index < 1 {
+		return 0
+	}
+
+	return index
+}
+
+// queryFromURL returns the query parameters of the provided URL,
+// which may be provided without a scheme.
+func queryFromURL(text string) url.Values {
+	if !strings.HasPrefix(text, "https://") && !strings.HasPrefix(text, "http://") {
+		text = "https://" + text
+	}
+
+	return utils.GetDataFromURL(text)
+}
+
+// loadSelected loads the provided entry according to its type (video/playlist/channel).
+// If next is true, the entry is inserted after the currently playing track,
+// or is played directly if the queue is empty.
+func loadSelected(info inv.SearchData, audio, current, next bool) {
+	var title string
+
+	err := player.lock.Acquire(context.Background(), 1)
+	if err != nil {
+		return
+	}
+	defer player.lock.Release(1)
+
+	app.ShowInfo("Adding "+info.Type+" "+info.Title, true)
+
+	insert := -1
+	if next {
+		if pos := mp.Player().QueuePosition(); pos >= 0 {
+			insert = pos + 1
+		} else {
+			current = true
+		}
+	}
+
+	switch info.Type {
+	case "playlist":
+		title, err = loadPlaylist(info.PlaylistID, audio, insert)
+
+	case "video":
+		title, err = loadVideo(info.VideoID, audio, insert)
+
+	case "channel":
+		title, err = loadChannel(info.AuthorID, audio, insert)
+
+	default:
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		app.ShowInfo("Player: Loading cancelled", false)
+		return
+	}
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	info.Title = title
+	go addToHistory(info, audio)
+
+	app.ShowInfo("Added "+info.Title, false)
+
+	if current && info.Type == "video" {
+		mp.Player().QueuePlayLatest()
+	}
+}
+
+// loadVideo loads a video into the media player. If insert is not negative,
+// the video is moved to the provided queue position after it is appended.
+// If a context is provided, only the video information is loaded.
+func loadVideo(id string, audio bool, insert int, ctx ...context.Context) (string, error) {
+	return queueVideo(loadContext(), id, audio, insert, ctx...)
+}
+
+// queueVideo loads a video into the media player, like loadVideo. The video is
+// not appended if the provided load context is cancelled before it is loaded.
+func queueVideo(load context.Context, id string, audio bool, insert int, ctx ...context.Context) (string, error) {
+	var video inv.VideoData
+	var urls []string
+
+	retryCtx := client.Ctx()
+	if ctx != nil {
+		retryCtx = ctx[0]
+	}
+
+	var trim mp.Trim
+	if ctx == nil {
+		trim = takeTrim(id)
+	}
+
+	err := retryRateLimited(retryCtx, func() error {
+		var err error
+
+		video, urls, err = inv.VideoLoadParams(id, audio, ctx...)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	player.queue.currentVideo(id, &video)
+
+	if ctx == nil {
+		if err := videoTrim(video, trim); err != nil {
+			return "", err
+		}
+
+		if err := appendVideo(load, video, audio, insert, trim, urls); err != nil {
+			return "", err
+		}
+	}
+
+	return video.Title, nil
+}
+
+// appendVideo appends the provided video URLs to the media player's queue.
+// If insert is not negative, the video is moved to the provided queue position.
+// If the load context is cancelled, i.e. the queue was cleared while the video
+// was being loaded, the video is not appended.
+func appendVideo(load context.Context, video inv.VideoData, audio bool, insert int, trim mp.Trim, urls []string) error {
+	player.load.Lock()
+	defer player.load.Unlock()
+
+	if err := load.Err(); err != nil {
+		return err
+	}
+
+	err := mp.Player().LoadFile(
+		video.Title,
+		video.LengthSeconds,
+		audio && video.LiveNow,
+		trim,
+		urls...,
+	)
+	if err != nil {
+		return err
+	}
+
+	if last := mp.Player().QueueCount() - 1; insert >= 0 && insert < last {
+		mp.Player().QueueMove(insert, last)
+	}
+
+	return nil
+}
+
+// playWithAudioFile displays the file browser to select a local audio file,
+// and queues the currently selected video with the audio file attached.
+func playWithAudioFile() {
+	info, err := app.FocusedTableReference()
+	if err != nil {
+		return
+	}
+
+	if info.Type != "video" {
+		app.ShowError(fmt.Errorf("Player: Audio files can only be attached to videos"))
+		return
+	}
+
+	app.UI.FileBrowser.Show("Attach audio file:", func(file string) {
+		loadWithAudioFile(info, file)
+	})
+}
+
+// loadWithAudioFile loads the video stream of the provided video into the
+// media player, and uses the provided local audio file as its audio track.
+func loadWithAudioFile(info inv.SearchData, file string) {
+	var video inv.VideoData
+	var urls []string
+
+	if stat, err := os.Stat(file); err != nil || stat.IsDir() {
+		app.ShowError(fmt.Errorf("Player: Cannot access audio file %s", file))
+		return
+	}
+
+	app.UI.FileBrowser.Hide()
+	app.ShowInfo("Adding "+info.Title+" with "+filepath.Base(file), true)
+
+	load := loadContext()
+
+	err := retryRateLimited(client.Ctx(), func() error {
+		var err error
+
+		video, urls, err = inv.VideoLoadParams(info.VideoID, false)
+		return err
+	})
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	player.queue.currentVideo(info.VideoID, &video)
+
+	if err := appendVideo(load, video, false, -1, mp.Trim{}, []string{urls[0], file}); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	info.Title = video.Title
+	go addToHistory(info, false)
+
+	app.ShowInfo("Added "+info.Title+" with "+filepath.Base(file), false)
+}
+
+// loadPlaylist loads all the entries in the playlist into the media player.
+// The playlist is loaded page by page, so that the player can start playing
+// while the rest of the entries are being loaded. If insert is not negative,
+// the entries are inserted in order from the provided queue position.
+func loadPlaylist(plid string, audio bool, insert int) (string, error) {
+	var title string
+	var added, total int
+
+	ctx, load := client.Ctx(), loadContext()
+	seen := make(map[string]struct{})
+
+	for page := 1; ; page++ {
+		var loaded int
+		var playlist inv.PlaylistData
+
+		err := retryRateLimited(ctx, func() error {
+			var err error
+
+			playlist, err = inv.Playlist(plid, false, page)
+			return err
+		})
+		if err != nil {
+			if page == 1 {
+				return "", err
+			}
+
+			app.ShowError(err)
+			break
+		}
+
+		title = playlist.Title
+
+		for _, p := range playlist.Videos {
+			if _, ok := seen[p.IndexID+p.VideoID]; ok {
+				continue
+			}
+
+			seen[p.IndexID+p.VideoID] = struct{}{}
+			loaded++
+
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+
+			case <-load.Done():
+				return "", load.Err()
+
+			default:
+			}
+
+			if _, err := queueVideo(load, p.VideoID, audio, insert); err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, client.ErrRateLimited) {
+					return "", err
+				}
+
+				continue
+			}
+
+			added++
+			if insert >= 0 {
+				insert++
+			}
+		}
+
+		total += loaded
+		if loaded == 0 || total >= playlist.VideoCount {
+			break
+		}
+
+		app.ShowInfo(fmt.Sprintf("Added %d/%d videos from %s", added, playlist.VideoCount, title), true)
+	}
+
+	return title, nil
+}
+
+// loadChannel loads all the videos uploaded by the channel into the media player.
+// Similar to loadPlaylist, the videos are loaded page by page.
+func loadChannel(id string, audio bool, insert int) (string, error) {
+	var added int
+	var title, continuation string
+
+	ctx, load := client.Ctx(), loadContext()
+
+	for {
+		var channel inv.ChannelData
+
+		err := retryRateLimited(ctx, func() error {
+			var err error
+
+			channel, err = inv.ChannelUploads(ctx, id, continuation)
+			return err
+		})
+		if err != nil {
+			if continuation == "" {
+				return "", err
+			}
+
+			app.ShowError(err)
+			break
+		}
+
+		for _, v := range channel.Videos {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+
+			case <-load.Done():
+				return "", load.Err()
+
+			default:
+			}
+
+			if title == "" {
+				title = v.Author
+			}
+
+			if _, err := queueVideo(load, v.VideoID, audio, insert); err != nil {
+				if errors.Is(err, context.Canceled) || errors.Is(err, client.ErrRateLimited) {
+					return "", err
+				}
+
+				continue
+			}
+
+			added++
+			if insert >= 0 {
+				insert++
+			}
+		}
+
+		if len(channel.Videos) == 0 || channel.Continuation == "" {
+			break
+		}
+
+		continuation = channel.Continuation
+
+		app.ShowInfo(fmt.Sprintf("Added %d videos from %s", added, title), true)
+	}
+
+	if added == 0 {
+		return "", fmt.Errorf("Player: No videos were added from the channel")
+	}
+
+	return title, nil
+}
+
+// currentVideoID returns the ID of the currently playing video.
+func currentVideoID() string {
+	pos := mp.Player().QueuePosition()
+	if pos < 0 {
+		return ""
+	}
+
+	data := utils.GetDataFromURL(mp.Player().Title(pos))
+	if data == nil {
+		return ""
+	}
+
+	return data.Get("id")
+}
+
+// addToPlaylist adds the currently playing video to a user playlist.
+func addToPlaylist() {
+	pos := mp.Player().QueuePosition()
+	if pos < 0 {
+		app.ShowError(fmt.Errorf("Player: No video is playing"))
+		return
+	}
+
+	data := utils.GetDataFromURL(mp.Player().Title(pos))
+	if data == nil || data.Get("id") == "" {
+		app.ShowError(fmt.Errorf("Player: No video is playing"))
+		return
+	}
+
+	view.Dashboard.AddToPlaylist(inv.SearchData{
+		Type:    "video",
+		Title:   data.Get("title"),
+		VideoID: data.Get("id"),
+		Author:  data.Get("author"),
+	})
+}
+
+// toggleHWDec cycles the hardware decoding mode, if a video is playing.
+func toggleHWDec() {
+	if mp.Player().MediaType() == "Audio" {
+		app.ShowInfo("Player: Hardware decoding only applies to videos", false)
+		return
+	}
+
+	app.ShowInfo("Player: Hardware decoding set to "+mp.Player().ToggleHWDec(), false)
+	sendPlayerEvents()
+}
+
+// cycleAspect cycles the aspect ratio of the video, if a video is playing.
+func cycleAspect() {
+	if mp.Player().MediaType() == "Audio" {
+		app.ShowInfo("Player: Aspect ratio only applies to videos", false)
+		return
+	}
+
+	aspect := mp.Player().CycleAspect()
+	if aspect == "-1" {
+		aspect = "auto"
+	}
+
+	app.ShowInfo("Player: Aspect ratio set to "+aspect, false)
+	sendPlayerEvents()
+}
+
+// zoomStep is the amount by which the video is zoomed in or out.
+const zoomStep = 0.25
+
+// changeZoom zooms the video in or out by the provided step, if a video is playing.
+func changeZoom(step float64) {
+	if mp.Player().MediaType() == "Audio" {
+		app.ShowInfo("Player: Zoom only applies to videos", false)
+		return
+	}
+
+	zoom := mp.Player().ChangeZoom(step)
+
+	app.ShowInfo("Player: Zoom set to "+strconv.FormatFloat(zoom, 'f', -1, 64), false)
+	sendPlayerEvents()
+}
+
+// volumeStep is the amount by which the volume is stepped up or down.
+const volumeStep = 10
+
+// stepVolume changes the volume by the provided step. If the current
+// volume is unknown, the volume is not changed.
+func stepVolume(step int) {
+	if volume, ok := steppedVolume(mp.Player().Volume(), step); ok {
+		mp.Player().SetVolume(volume)
+	}
+}
+
+// steppedVolume returns the provided volume changed by the provided step,
+// and whether the volume is known, i.e. it is not -1.
+func steppedVolume(volume, step int) (int, bool) {
+	if volume == -1 {
+		return 0, false
+	}
+
+	return volume + step, true
+}
+
+// cacheStep is the number of seconds by which the cache is increased.
+const cacheStep = 60
+
+// setupCache sets the number of seconds to cache ahead from the 'cache-secs' option.
+// If the option is not set, the default number of seconds is cached ahead.
+func setupCache() {
+	secs, err := strconv.Atoi(cmd.GetOptionValue("cache-secs"))
+	if err != nil {
+		secs = mp.DefaultCacheSecs
+	}
+
+	mp.Player().SetCacheSecs(secs)
 }
 
-// playFromURL plays the given URL.
-func playFromURL(text string, audio bool) {
-	id, mtype, err := utils.GetVPIDFromURL(text)
-	if err != nil {
-		app.ShowError(err)
+// increaseCache increases the number of seconds to cache ahead
+// for the current session, up to the maximum allowed value.
+func increaseCache() {
+	secs, ok := nextCacheSecs(mp.Player().CacheSecs())
+	if !ok {
+		app.ShowInfo(fmt.Sprintf("Player: Cache is already at the maximum of %d seconds", mp.MaxCacheSecs), false)
 		return
 	}
 
-	info := inv.SearchData{
-		Title: text,
-		Type:  mtype,
+	mp.Player().SetCacheSecs(secs)
+
+	app.ShowInfo(fmt.Sprintf("Player: Cache set to %d seconds", mp.Player().CacheSecs()), false)
+}
+
+// nextCacheSecs returns the number of seconds to cache ahead after the provided
+// number is increased, and whether it could be increased. If the number is unknown
+// or beyond the maximum, like MPV's default of 1000 hours, it is increased from
+// the default number of seconds instead.
+func nextCacheSecs(secs int) (int, bool) {
+	if secs <= 0 || secs > mp.MaxCacheSecs {
+		secs = mp.DefaultCacheSecs
+	}
+	if secs >= mp.MaxCacheSecs {
+		return secs, false
 	}
 
-	if mtype == "video" {
-		info.VideoID = id
-	} else {
-		info.PlaylistID = id
+	secs += cacheStep
+	if secs > mp.MaxCacheSecs {
+		secs = mp.MaxCacheSecs
 	}
 
-	Play(audio, false, info)
+	return secs, true
 }
 
-// loadSelected loads the provided entry according to its type (video/playlist).
-func loadSelected(info inv.SearchData, audio, current bool) {
-	var title string
+// takeScreenshot saves the current video frame to the screenshot directory,
+// with the video ID and the current time in the filename.
+func takeScreenshot() {
+	if mp.Player().MediaType() == "Audio" {
+		app.ShowInfo("Player: Screenshots can only be taken of videos", false)
+		return
+	}
 
-	err := player.lock.Acquire(context.Background(), 1)
+	dir, err := cmd.ScreenshotDir()
 	if err != nil {
+		app.ShowError(err)
 		return
 	}
-	defer player.lock.Release(1)
 
-	app.ShowInfo("Adding "+info.Type+" "+info.Title, true)
+	name := "invidtui"
+	if id := currentVideoID(); id != "" {
+		name += "-" + id
+	}
 
-	switch info.Type {
-	case "playlist":
-		title, err = loadPlaylist(info.PlaylistID, audio)
+	file := filepath.Join(dir, name+"-"+time.Now().Format("20060102-150405")+".png")
+	if err := mp.Player().Screenshot(file); err != nil {
+		app.ShowError(err)
+		return
+	}
 
-	case "video":
-		title, err = loadVideo(info.VideoID, audio)
+	app.ShowInfo("Player: Screenshot saved to "+file, false)
+}
 
-	default:
+// copyURL copies the link to the currently playing video to the clipboard.
+// If timestamp is true, the current playback position is added to the link.
+func copyURL(timestamp bool) {
+	id := currentVideoID()
+	if id == "" {
+		app.ShowError(fmt.Errorf("Player: No video is playing"))
 		return
 	}
-	if err != nil {
-		if err.Error() != "Rate-limit exceeded" {
-			app.ShowError(err)
+
+	link := "https://youtube.com/watch?v=" + id
+	if timestamp {
+		if position := mp.Player().Position(); position > 0 {
+			link += "&t=" + strconv.FormatInt(position, 10)
 		}
+	}
 
+	if err := utils.CopyToClipboard(link); err != nil {
+		app.ShowError(err)
 		return
 	}
 
-	info.Title = title
-	go addToHistory(info)
-
-	app.ShowInfo("Added "+info.Title, false)
-
-	if current && info.Type == "video" {
-		mp.Player().QueuePlayLatest()
-	}
+	app.ShowInfo("Copied "+link, false)
 }
 
-// loadVideo loads a video into the media player.
-func loadVideo(id string, audio bool, ctx ...context.Context) (string, error) {
-	video, urls, err := inv.VideoLoadParams(id, audio, ctx...)
-	if err != nil {
-		return "", err
+// infoVideo returns the video shown in the info view.
+func infoVideo() *inv.VideoData {
+	if !IsInfoShown() || player.infoID == "" {
+		return nil
 	}
 
-	player.queue.currentVideo(id, &video)
+	return player.queue.currentVideo(player.infoID)
+}
 
-	if ctx == nil {
-		mp.Player().LoadFile(
-			video.Title,
-			video.LengthSeconds,
-			audio && video.LiveNow,
-			urls...,
-		)
-	}
+// IsInfoChannelAvailable returns whether the channel of the video
+// shown in the info view can be opened.
+func IsInfoChannelAvailable() bool {
+	video := infoVideo()
 
-	return video.Title, nil
+	return video != nil && video.AuthorID != ""
 }
 
-// loadPlaylist loads all the entries in the playlist into the media player.
-func loadPlaylist(plid string, audio bool) (string, error) {
-	var err error
+// copyInfoTitle copies the title and author of the video
+// shown in the info view to the clipboard.
+func copyInfoTitle() {
+	video := infoVideo()
+	if video == nil {
+		return
+	}
 
-	playlist, err := inv.Playlist(plid, false, 1)
-	if err != nil {
-		return "", err
+	text := video.Title
+	if video.Author != "" {
+		text += " - " + video.Author
 	}
 
-	for _, p := range playlist.Videos {
-		select {
-		case <-client.Ctx().Done():
-			return "", client.Ctx().Err()
+	if err := utils.CopyToClipboard(text); err != nil {
+		app.ShowError(err)
+		return
+	}
 
-		default:
-		}
+	app.ShowInfo("Copied "+text, false)
+}
+
+// openInfoChannel shows the channel of the video shown in the info view.
+func openInfoChannel() {
+	video := infoVideo()
+	if video == nil {
+		return
+	}
 
-		loadVideo(p.VideoID, audio)
+	if video.AuthorID == "" {
+		app.ShowError(fmt.Errorf("Player: No channel information for %s", video.Title))
+		return
 	}
 
-	return playlist.Title, nil
+	view.Channel.Show(video.AuthorID, "video")
+	app.ShowInfo("Player: Opening channel "+video.Author, false)
 }
 
 // renderPlayer renders the media player within the app.
@@ -506,20 +1553,33 @@ func renderPlayer(cancel context.CancelFunc) {
 	_, _, width, _ := player.desc.GetRect()
 	app.UI.RUnlock()
 
-	id, title, progress, states, err := updateProgressAndInfo(width)
+	id, title, progress, states, err := updateProgressAndInfo(layoutWidth(width))
 	if err != nil {
 		cancel()
 		return
 	}
 
+	skipSegments(id, mp.Player().Position())
+	checkBuffering(id)
+	savePosition(id, mp.Player().Position(), mp.Player().Duration())
+	rememberPosition(mp.Player().Position())
+	checkScrobble(mp.Player().Position())
+	emitPauseEvent()
+	renderDebugInfo()
+
 	player.mutex.Lock()
 	cmd.Settings.PlayerStates = states
 	player.mutex.Unlock()
 
+	writeStatus(states)
+
+	position := mp.Player().Position()
+
 	app.UI.QueueUpdateDraw(func() {
 		renderInfo(id, title)
-		player.desc.SetText(progress)
-		player.title.SetText("[::b]" + tview.Escape(title))
+		syncLyrics(id, position)
+		renderLayout(id, title, progress, width)
+		renderNowPlaying(id, title, progress)
 	})
 }
 
@@ -531,6 +1591,10 @@ func changeImageQuality(set ...struct{}) {
 	var prev string
 	var options []string
 
+	if !IsImageEnabled() {
+		return
+	}
+
 	video := player.queue.currentVideo(player.infoID)
 	if video == nil {
 		return
@@ -645,6 +1709,7 @@ func renderInfo(id, title string, force ...struct{}) {
 		player.region.RemoveItemIndex(1)
 	}
 
+	player.links = nil
 	player.info.SetText("[::b]Loading information...")
 
 	video := player.queue.currentVideo(id)
@@ -655,7 +1720,7 @@ func renderInfo(id, title string, force ...struct{}) {
 				return
 			}
 
-			_, err = loadVideo(id, true, ctx)
+			_, err = loadVideo(id, true, -1, ctx)
 			player.render.Release(1)
 
 			app.UI.QueueUpdateDraw(func() {
@@ -674,6 +1739,19 @@ func renderInfo(id, title string, force ...struct{}) {
 		return
 	}
 
+	player.info.SetText(infoHeader(video) + infoBody(video))
+	player.info.ScrollToBeginning()
+
+	if !IsImageEnabled() {
+		return
+	}
+
+	changeImageQuality(struct{}{})
+	go renderInfoImage(infoContext(true), id, filepath.Base(player.thumbURI))
+}
+
+// infoHeader returns the author, statistics and stream information of the provided video.
+func infoHeader(video *inv.VideoData) string {
 	text := "\n"
 	if video.Author != "" {
 		text += fmt.Sprintf("[::bu]%s[-:-:-]\n\n", video.Author)
@@ -687,13 +1765,47 @@ func renderInfo(id, title string, force ...struct{}) {
 		utils.FormatNumber(video.LikeCount),
 		video.SubCountText,
 	)
-	text += "[::b]" + tview.Escape(video.Description)
 
-	player.info.SetText(text)
-	player.info.ScrollToBeginning()
+	return text + streamInfo()
+}
 
-	changeImageQuality(struct{}{})
-	go renderInfoImage(infoContext(true), id, filepath.Base(player.thumbURI))
+// infoBody returns the lyrics of the provided video if they are shown and found,
+// and its description otherwise, and sets the links within the description.
+func infoBody(video *inv.VideoData) string {
+	if text, ok := lyricsText(video); ok {
+		player.links = nil
+		return text
+	}
+
+	description, links := descriptionLinks(video.Description)
+	player.links = links
+
+	return "[::b]" + description
+}
+
+// streamInfo returns the codec and bitrate information
+// of the currently playing stream.
+func streamInfo() string {
+	var codecs []string
+
+	audio := mp.Player().AudioCodec()
+	if audio == "" {
+		return ""
+	}
+
+	if mp.Player().MediaType() == "Video" {
+		if video := mp.Player().VideoCodec(); video != "" {
+			codecs = append(codecs, video)
+		}
+	}
+	codecs = append(codecs, audio)
+
+	text := "[green::b]" + tview.Escape(strings.Join(codecs, " / ")) + "[-:-:-]"
+	if bitrate := mp.Player().Bitrate(); bitrate > 0 {
+		text += fmt.Sprintf(" / [yellow::b]%d kbps[-:-:-]", bitrate/1000)
+	}
+
+	return text + "\n\n"
 }
 
 // renderInfoImage renders the image for the track information display.
@@ -704,30 +1816,70 @@ func renderInfoImage(ctx context.Context, id, image string, change ...struct{})
 
 	app.ShowInfo("Player: Loading image", true, change != nil)
 
-	thumbdata, err := inv.VideoThumbnail(ctx, id, image)
+	thumbnail, err := fetchThumbnail(ctx, id, image)
 	if err != nil {
-		if ctx.Err() != context.Canceled {
-			app.ShowError(fmt.Errorf("Player: Unable to download thumbnail"))
+		if ctx.Err() == context.Canceled {
+			app.ShowInfo("", false, change != nil)
+			return
 		}
 
-		app.ShowInfo("", false, change != nil)
-
-		return
+		app.ShowError(err)
+		thumbnail = placeholderImage()
 	}
 
-	thumbnail, err := jpeg.Decode(thumbdata.Body)
-	if err != nil {
-		app.ShowError(fmt.Errorf("Player: Unable to decode thumbnail"))
-		return
+	if cmd.GetOptionValue("image-dithering") == "ordered" {
+		thumbnail = orderedDither(thumbnail)
 	}
 
 	app.UI.QueueUpdateDraw(func() {
+		player.image.SetDithering(imageDithering())
 		player.image.SetImage(thumbnail)
 	})
 
 	app.ShowInfo("Player: Image loaded", false, change != nil)
 }
 
+// fetchThumbnail downloads and decodes the provided thumbnail image for a video.
+// If the download or decoding fails, it is attempted once more.
+func fetchThumbnail(ctx context.Context, id, file string) (image.Image, error) {
+	var err error
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var thumbdata *http.Response
+		var thumbnail image.Image
+
+		thumbdata, err = inv.VideoThumbnail(ctx, id, file)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			err = fmt.Errorf("Player: Unable to download thumbnail")
+			continue
+		}
+
+		thumbnail, _, err = image.Decode(thumbdata.Body)
+		thumbdata.Body.Close()
+		if err != nil {
+			err = fmt.Errorf("Player: Unable to decode thumbnail")
+			continue
+		}
+
+		return thumbnail, nil
+	}
+
+	return nil, err
+}
+
+// placeholderImage returns a solid-colored image, which is displayed
+// in place of a thumbnail that could not be loaded.
+func placeholderImage() image.Image {
+	placeholder := image.NewRGBA(image.Rect(0, 0, 16, 9))
+	draw.Draw(placeholder, placeholder.Bounds(), image.NewUniform(color.RGBA{48, 48, 48, 255}), image.Point{}, draw.Src)
+
+	return placeholder
+}
+
 // playingStatusCheck monitors the playing status.
 func playingStatusCheck() {
 	var ctx context.Context
@@ -748,27 +1900,44 @@ func playingStatusCheck() {
 		}
 
 		ctx, cancel = context.WithCancel(context.Background())
+
+		player.updates.Add(1)
 		go playerUpdateLoop(ctx, cancel)
 	}
 }
 
-// playerUpdateLoop updates the player.
+// playerUpdateLoop updates the player. Updates are primarily triggered by changes
+// in the media player's properties, and periodically at the configured interval.
 func playerUpdateLoop(ctx context.Context, cancel context.CancelFunc) {
-	t := time.NewTicker(1 * time.Second)
+	interval, err := time.ParseDuration(cmd.GetOptionValue("update-interval"))
+	if err != nil || interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	t := time.NewTicker(interval)
 	defer t.Stop()
+	defer player.updates.Done()
 
 	for {
 		select {
 		case <-ctx.Done():
 			Hide()
+			clearQueue()
 			ToggleInfo(struct{}{})
 			player.desc.SetText("")
 			player.title.SetText("")
+			player.stats.SetText("")
+			clearStatus()
 			return
 
 		case <-player.events:
 			renderPlayer(cancel)
-			t.Reset(1 * time.Second)
+			t.Reset(interval)
+			continue
+
+		case <-mp.Events.PropertyEvent:
+			renderPlayer(cancel)
+			t.Reset(interval)
 			continue
 
 		case <-t.C:
@@ -777,6 +1946,17 @@ func playerUpdateLoop(ctx context.Context, cancel context.CancelFunc) {
 	}
 }
 
+// showRestrictedError shows an error for a track which failed to play because
+// it is age-restricted or requires signing in, which cannot be played without cookies.
+func showRestrictedError() {
+	if cmd.GetOptionValue("cookies") == "" {
+		app.ShowError(fmt.Errorf("Player: Video requires signing in, set a cookies file with the 'cookies' option"))
+		return
+	}
+
+	app.ShowError(fmt.Errorf("Player: Video requires signing in, the cookies file may be invalid or expired"))
+}
+
 // monitorMPVEvents monitors events sent from MPV.
 func monitorMPVEvents() {
 	for {
@@ -788,12 +1968,76 @@ func monitorMPVEvents() {
 
 			app.ShowError(fmt.Errorf("Player: Unable to play %s", msg))
 
+		case track, ok := <-mp.Events.FailedEvent:
+			if !ok {
+				return
+			}
+
+			if track.Restricted {
+				showRestrictedError()
+				player.queue.markFailed(track.ID)
+
+				break
+			}
+
+			if !renewFailedTrack(track) {
+				player.queue.markFailed(track.ID)
+			}
+
 		case _, ok := <-mp.Events.FileLoadedEvent:
 			if !ok {
 				return
 			}
 
 			Show()
+			pauseLoadedTrack()
+			notifyPlaying()
+			scrobbleNowPlaying()
+			setPlayingEntry()
+			reshuffleLoop()
+			if !resumeReload() && !restoreQueuePosition() {
+				resumePosition()
+			}
+			emitTrackEvent(TrackStarted, -1)
+
+		case id, ok := <-mp.Events.FileEndEvent:
+			if !ok {
+				return
+			}
+
+			scrobbleFinished(id)
+			forgetPosition(id)
+			emitTrackEvent(TrackEnded, id)
+			countLoop(id)
+			autoplayNext(id)
+
+			if repeatOnceStatus() {
+				setRepeatOnce(false)
+				sendPlayingStatus(false)
+
+				continue
+			}
+
+			player.queue.removeFinished(id)
+
+		case request, ok := <-mp.Events.EnqueueEvent:
+			if !ok {
+				return
+			}
+
+			playFromURL(request.URL, request.Audio)
+
+		case attempt, ok := <-mp.Events.ReconnectEvent:
+			if !ok {
+				return
+			}
+
+			if attempt == 0 {
+				app.ShowInfo("Player: Reconnected", false)
+				continue
+			}
+
+			app.ShowInfo(fmt.Sprintf("Player: Reconnecting (attempt %d)", attempt), true)
 		}
 	}
 }
@@ -811,7 +2055,7 @@ func openPlaylist(file string) {
 	Show()
 
 	app.UI.QueueUpdateDraw(func() {
-		player.queue.Show()
+		player.queue.autoShow()
 	})
 
 	app.UI.FileBrowser.Hide()
@@ -825,7 +2069,10 @@ func checkLiveURL(uri string, audio bool) bool {
 	id, expired := inv.CheckLiveURL(uri, audio)
 
 	if expired {
-		if _, err := loadVideo(id, audio); err != nil {
+		utils.LogInfof("Player: Renewing live URL for video %s", id)
+
+		if _, err := loadVideo(id, audio, -1); err != nil {
+			utils.LogErrorf("Player: Unable to renew live URL for video %s: %v", id, err)
 			app.ShowError(fmt.Errorf("Player: Unable to renew live URL for video %s", id))
 		}
 	}
@@ -854,6 +2101,7 @@ func updateProgressAndInfo(width int) (string, string, string, []string, error)
 	shuffle := mp.Player().Shuffled()
 	loop := mp.Player().LoopMode()
 	mute := mp.Player().Muted()
+	premute := mp.Player().PreMuteVolume()
 	volume := mp.Player().Volume()
 
 	duration := mp.Player().Duration()
@@ -861,11 +2109,11 @@ func updateProgressAndInfo(width int) (string, string, string, []string, error)
 	currtime := utils.FormatDuration(timepos)
 
 	if volume < 0 {
-		vol = "0"
+		vol = "--"
 	} else {
 		vol = strconv.Itoa(volume)
+		states = append(states, "volume "+vol)
 	}
-	states = append(states, "volume "+vol)
 	vol += "%"
 
 	if timepos < 0 {
@@ -905,12 +2153,6 @@ func updateProgressAndInfo(width int) (string, string, string, []string, error)
 	mtype = "(" + mtype + ")"
 
 	width /= 2
-	length := width * int(timepos) / int(duration)
-
-	endlength := width - length
-	if endlength < 0 {
-		endlength = width
-	}
 
 	if shuffle {
 		lhs += " S"
@@ -920,9 +2162,42 @@ func updateProgressAndInfo(width int) (string, string, string, []string, error)
 	if mute {
 		lhs += " M"
 		states = append(states, "mute")
+	} else if premute > 0 {
+		lhs += " M"
+	}
+
+	if premute > 0 {
+		states = append(states, "premute "+strconv.Itoa(premute))
+	}
+
+	if autoplayStatus() {
+		lhs += " ∞"
+		states = append(states, "autoplay")
+	}
+
+	if isFavorite(data.Get("id")) {
+		lhs += " ★"
+	}
+
+	if hwdec := mp.Player().HWDec(); hwdec != "" && hwdec != configuredHWDec() {
+		states = append(states, "hwdec "+hwdec)
 	}
 
-	if loop != "" {
+	if device := mp.Player().CurrentAudioDevice(); device != "" && device != "auto" {
+		states = append(states, "audio-device "+device)
+	}
+
+	if aspect := mp.Player().Aspect(); aspect != "-1" {
+		states = append(states, "aspect "+aspect)
+	}
+
+	if zoom := mp.Player().Zoom(); zoom != 0 {
+		states = append(states, "zoom "+strconv.FormatFloat(zoom, 'f', -1, 64))
+	}
+
+	if repeatOnceStatus() {
+		loop = "R-1"
+	} else if loop != "" {
 		states = append(states, loop)
 
 		switch loop {
@@ -931,6 +2206,10 @@ func updateProgressAndInfo(width int) (string, string, string, []string, error)
 
 		case "loop-playlist":
 			loop = "R-P"
+			if count := remainingLoops(); count > 0 {
+				loop += "×" + strconv.Itoa(count)
+				states = append(states, "loop-count "+strconv.Itoa(count))
+			}
 		}
 	}
 
@@ -947,12 +2226,123 @@ func updateProgressAndInfo(width int) (string, string, string, []string, error)
 	}
 
 	rhs = " " + vol + " " + mtype
+	if meter := levelMeter(); meter != "" {
+		rhs = " " + meter + rhs
+	}
 	lhs = loop + lhs + " " + state + " "
-	progress := currtime + " |" + strings.Repeat("█", length) + strings.Repeat(" ", endlength) + "| " + totaltime
+	progress := joinProgress(currtime, progressBar(width, timepos, duration), totaltime)
 
 	return data.Get("id"), title, (lhs + progress + rhs), states, nil
 }
 
+// joinProgress returns the playback position and duration, along with
+// the progress bar between them. If the bar is empty, it is left out.
+func joinProgress(currtime, bar, totaltime string) string {
+	if bar == "" {
+		return currtime + " " + totaltime
+	}
+
+	return currtime + " " + bar + " " + totaltime
+}
+
+// progressBar returns the progress bar for the provided width, position and duration,
+// rendered with the configured characters and style.
+func progressBar(width int, timepos, duration int64) string {
+	return drawProgress(
+		width, timepos, duration,
+		cmd.GetOptionValue("progress-style"),
+		cmd.GetOptionValue("progress-fill"),
+		cmd.GetOptionValue("progress-empty"),
+		cmd.GetOptionValue("progress-delimiters"),
+	)
+}
+
+// drawProgress returns a progress bar for the provided width, position and duration,
+// rendered with the provided style and characters. If the fill or empty characters
+// are not set, or the delimiters are not two characters, the defaults are used instead.
+func drawProgress(width int, timepos, duration int64, style, fill, empty, delimiters string) string {
+	var bar string
+
+	if width < minProgressWidth || duration <= 0 {
+		return ""
+	}
+
+	enclose := []rune(delimiters)
+	if len(enclose) != 2 {
+		enclose = []rune("||")
+	}
+	if fill == "" {
+		fill = "█"
+	}
+	if empty == "" {
+		empty = " "
+	}
+
+	if style == "fine" {
+		bar = fineProgress(width, timepos, duration, empty)
+	} else {
+		length := int(int64(width) * timepos / duration)
+		if length < 0 {
+			length = 0
+		} else if length > width {
+			length = width
+		}
+
+		bar = strings.Repeat(fill, length) + strings.Repeat(empty, width-length)
+	}
+
+	return tview.Escape(string(enclose[0]) + bar + string(enclose[1]))
+}
+
+// minProgressWidth is the minimum width of the progress bar. If less width
+// is available, only the playback position and duration are shown.
+const minProgressWidth = 4
+
+// fineProgress returns a progress bar for the provided width, which uses
+// partial block characters to display progress at sub-cell precision.
+func fineProgress(width int, timepos, duration int64, empty string) string {
+	partials := []rune("▏▎▍▌▋▊▉")
+
+	if width <= 0 || duration <= 0 {
+		return ""
+	}
+
+	eighths := int64(width) * 8 * timepos / duration
+	if eighths < 0 {
+		eighths = 0
+	}
+	if total := int64(width) * 8; eighths > total {
+		eighths = total
+	}
+
+	full, remainder := int(eighths/8), int(eighths%8)
+
+	bar := strings.Repeat("█", full)
+	if remainder > 0 {
+		bar += string(partials[remainder-1])
+		full++
+	}
+
+	return bar + strings.Repeat(empty, width-full)
+}
+
+// waitUpdates waits until the player updates have stopped,
+// or until the provided timeout has elapsed.
+func waitUpdates(timeout time.Duration) {
+	done := make(chan struct{})
+
+	go func() {
+		player.updates.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+
+	case <-time.After(timeout):
+	}
+}
+
 // sendPlayingStatus sends status events to the player.
 // If playing is true, the player is shown and vice-versa.
 func sendPlayingStatus(playing bool) {
@@ -986,6 +2376,31 @@ func playingStatus(set ...bool) bool {
 	return player.playing
 }
 
+// repeatOnceStatus sets or returns whether the repeat-once mode is enabled.
+func repeatOnceStatus(set ...bool) bool {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if set != nil {
+		player.repeatOnce = set[0]
+	}
+
+	return player.repeatOnce
+}
+
+// autoplayStatus returns whether the autoplay mode is enabled,
+// and optionally enables or disables it.
+func autoplayStatus(set ...bool) bool {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if set != nil {
+		player.autoplay = set[0]
+	}
+
+	return player.autoplay
+}
+
 // infoContext returns a new context for loading the player information.
 func infoContext(image bool, all ...struct{}) context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -0,0 +1,193 @@
+package player
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	mp "github.com/darkhz/invidtui/mediaplayer"
+)
+
+func TestFineProgress(t *testing.T) {
+	const duration = 120
+
+	for _, width := range []int{0, 1, 4, 5, 7, 13} {
+		for _, timepos := range []int64{0, duration / 2, duration} {
+			bar := fineProgress(width, timepos, duration, " ")
+
+			if n := utf8.RuneCountInString(bar); n != width {
+				t.Errorf("width %d, position %d: bar %q has %d cells", width, timepos, bar, n)
+			}
+
+			switch timepos {
+			case 0:
+				if strings.Trim(bar, " ") != "" {
+					t.Errorf("width %d: bar %q at the start is not empty", width, bar)
+				}
+
+			case duration:
+				if strings.Trim(bar, "█") != "" {
+					t.Errorf("width %d: bar %q at the end is not full", width, bar)
+				}
+
+			default:
+				filled := utf8.RuneCountInString(strings.TrimRight(bar, " "))
+				if want := (width + 1) / 2; filled != want {
+					t.Errorf("width %d: bar %q in the middle fills %d cells, want %d", width, bar, filled, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFineProgressPartial(t *testing.T) {
+	tests := []struct {
+		timepos int64
+		want    string
+	}{
+		{timepos: -5, want: "    "},
+		{timepos: 1, want: "    "},
+		{timepos: 2, want: "▏   "},
+		{timepos: 16, want: "█   "},
+		{timepos: 24, want: "█▌  "},
+		{timepos: 63, want: "███▉"},
+		{timepos: 100, want: "████"},
+	}
+
+	for _, test := range tests {
+		if bar := fineProgress(4, test.timepos, 64, " "); bar != test.want {
+			t.Errorf("fineProgress(4, %d, 64) = %q, want %q", test.timepos, bar, test.want)
+		}
+	}
+}
+
+func TestDrawProgress(t *testing.T) {
+	tests := []struct {
+		name                          string
+		width                         int
+		timepos, duration             int64
+		style, fill, empty, delimiter string
+		want                          string
+	}{
+		{name: "too narrow", width: 3, timepos: 1, duration: 2, want: ""},
+		{name: "no duration", width: 10, timepos: 1, duration: 0, want: ""},
+		{name: "negative duration", width: 10, timepos: 1, duration: -1, want: ""},
+		{
+			name: "block", width: 4, timepos: 1, duration: 2,
+			fill: "#", empty: "-", delimiter: "<>", want: "<##-->",
+		},
+		{
+			name: "position past the end", width: 4, timepos: 3, duration: 2,
+			fill: "#", empty: "-", delimiter: "<>", want: "<####>",
+		},
+		{
+			name: "negative position", width: 4, timepos: -1, duration: 2,
+			fill: "#", empty: "-", delimiter: "<>", want: "<---->",
+		},
+		{
+			name: "default characters", width: 4, timepos: 1, duration: 2,
+			want: "|██  |",
+		},
+		{
+			name: "invalid delimiters", width: 4, timepos: 1, duration: 2,
+			fill: "#", empty: "-", delimiter: "<", want: "|##--|",
+		},
+		{
+			name: "fine", width: 4, timepos: 5, duration: 8,
+			style: "fine", empty: "-", delimiter: "()", want: "(██▌-)",
+		},
+		{
+			name: "fine with default characters", width: 4, timepos: 1, duration: 2,
+			style: "fine", want: "|██  |",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bar := drawProgress(
+				test.width, test.timepos, test.duration,
+				test.style, test.fill, test.empty, test.delimiter,
+			)
+			if bar != test.want {
+				t.Errorf("drawProgress() = %q, want %q", bar, test.want)
+			}
+		})
+	}
+}
+
+func TestDrawProgressEdges(t *testing.T) {
+	for _, style := range []string{"block", "fine"} {
+		for width := 0; width <= 5; width++ {
+			for _, duration := range []int64{-10, -1, 0, 60} {
+				bar := drawProgress(width, 30, duration, style, "#", "-", "||")
+
+				wantEmpty := width < minProgressWidth || duration <= 0
+				if wantEmpty && bar != "" {
+					t.Errorf("%s, width %d, duration %d: bar = %q, want none", style, width, duration, bar)
+				}
+				if !wantEmpty && utf8.RuneCountInString(bar) != width+2 {
+					t.Errorf("%s, width %d, duration %d: bar %q is not %d cells wide", style, width, duration, bar, width+2)
+				}
+			}
+		}
+	}
+
+	for _, duration := range []int64{-1, 0} {
+		if bar := fineProgress(10, 5, duration, " "); bar != "" {
+			t.Errorf("fineProgress() with duration %d = %q, want none", duration, bar)
+		}
+	}
+}
+
+func TestJoinProgress(t *testing.T) {
+	if text := joinProgress("00:30", "", "01:00"); text != "00:30 01:00" {
+		t.Errorf("joinProgress() without a bar = %q", text)
+	}
+	if text := joinProgress("00:30", "|##--|", "01:00"); text != "00:30 |##--| 01:00" {
+		t.Errorf("joinProgress() with a bar = %q", text)
+	}
+}
+
+func TestNextCacheSecs(t *testing.T) {
+	tests := []struct {
+		name string
+		secs int
+		want int
+		ok   bool
+	}{
+		{name: "player default", secs: 3600000, want: mp.DefaultCacheSecs + cacheStep, ok: true},
+		{name: "unknown", secs: 0, want: mp.DefaultCacheSecs + cacheStep, ok: true},
+		{name: "configured", secs: 120, want: 120 + cacheStep, ok: true},
+		{name: "near the maximum", secs: mp.MaxCacheSecs - 10, want: mp.MaxCacheSecs, ok: true},
+		{name: "maximum", secs: mp.MaxCacheSecs, want: mp.MaxCacheSecs, ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			secs, ok := nextCacheSecs(test.secs)
+			if secs != test.want || ok != test.ok {
+				t.Errorf("nextCacheSecs(%d) = %d, %v, want %d, %v", test.secs, secs, ok, test.want, test.ok)
+			}
+		})
+	}
+}
+
+func TestSteppedVolume(t *testing.T) {
+	tests := []struct {
+		volume, step, want int
+		ok                 bool
+	}{
+		{volume: 50, step: volumeStep, want: 60, ok: true},
+		{volume: 50, step: -volumeStep, want: 40, ok: true},
+		{volume: 0, step: volumeStep, want: 10, ok: true},
+		{volume: -1, step: volumeStep, ok: false},
+		{volume: -1, step: -volumeStep, ok: false},
+	}
+
+	for _, test := range tests {
+		volume, ok := steppedVolume(test.volume, test.step)
+		if ok != test.ok || ok && volume != test.want {
+			t.Errorf("steppedVolume(%d, %d) = %d, %v, want %d, %v", test.volume, test.step, volume, ok, test.want, test.ok)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package player
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// QueuePositions stores the playback positions of the queue entries within
+// the current session, so that switching back to an entry resumes it.
+type QueuePositions struct {
+	entry     int
+	positions map[int]int64
+
+	mutex sync.Mutex
+}
+
+var queuePositions = QueuePositions{
+	entry:     -1,
+	positions: make(map[int]int64),
+}
+
+// setPlayingEntry stores the playlist entry ID of the currently playing track.
+// The position of the previous entry is not updated after this, so its last
+// position before the track was switched is kept.
+func setPlayingEntry() {
+	entry := -1
+
+	if pos := mp.Player().QueuePosition(); pos >= 0 {
+		if id, err := mp.Player().Get(fmt.Sprintf("playlist/%d/id", pos)); err == nil {
+			if id, ok := id.(float64); ok {
+				entry = int(id)
+			}
+		}
+	}
+
+	queuePositions.mutex.Lock()
+	queuePositions.entry = entry
+	queuePositions.mutex.Unlock()
+}
+
+// rememberPosition stores the provided position for the currently playing entry.
+// Positions near the start of a track are not stored, since the position of the
+// newly switched track is reported before it is loaded.
+func rememberPosition(position int64) {
+	if position < resumeMinPosition || !cmd.IsOptionEnabled("remember-positions") {
+		return
+	}
+
+	queuePositions.mutex.Lock()
+	defer queuePositions.mutex.Unlock()
+
+	if queuePositions.entry >= 0 {
+		queuePositions.positions[queuePositions.entry] = position
+	}
+}
+
+// forgetPosition removes the stored position of the provided entry.
+// This is called when the entry has finished playing.
+func forgetPosition(entry int) {
+	queuePositions.mutex.Lock()
+	defer queuePositions.mutex.Unlock()
+
+	delete(queuePositions.positions, entry)
+}
+
+// restoreQueuePosition seeks the currently playing entry to its stored position,
+// and returns whether the position was restored.
+func restoreQueuePosition() bool {
+	if !cmd.IsOptionEnabled("remember-positions") {
+		return false
+	}
+
+	queuePositions.mutex.Lock()
+	position, ok := queuePositions.positions[queuePositions.entry]
+	queuePositions.mutex.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	mp.Player().SeekToPosition(position)
+	app.ShowInfo("Player: Restored position "+utils.FormatDuration(position), false)
+
+	return true
+}
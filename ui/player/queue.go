@@ -3,8 +3,11 @@ package player
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/darkhz/invidtui/cmd"
@@ -20,14 +23,20 @@ import (
 // Queue describes the layout of the player queue.
 type Queue struct {
 	init, moveMode bool
-	prevrow        int
+	playerOnly     bool
+	grabbed        int
+	rows           []int
 	data           []map[string]interface{}
 	videos         map[string]*inv.VideoData
+	failed         map[int]struct{}
 
 	status chan struct{}
 
 	modal *app.Modal
+	flex  *tview.Flex
 	table *tview.Table
+	stats *tview.TextView
+	input *tview.InputField
 
 	lock *semaphore.Weighted
 }
@@ -41,6 +50,13 @@ type QueueData struct {
 	inv.SearchData
 }
 
+// queueMatch stores a queue entry which matches the queue filter.
+type queueMatch struct {
+	position, score int
+	title           string
+	data            QueueData
+}
+
 // setup sets up the player queue.
 func (q *Queue) setup() {
 	if q.init {
@@ -49,6 +65,7 @@ func (q *Queue) setup() {
 
 	q.status = make(chan struct{}, 100)
 	q.videos = make(map[string]*inv.VideoData)
+	q.failed = make(map[int]struct{})
 
 	q.table = tview.NewTable()
 	q.table.SetInputCapture(q.Keybindings)
@@ -58,7 +75,41 @@ func (q *Queue) setup() {
 		app.SetContextMenu(cmd.KeyContextQueue, q.table)
 	})
 
-	q.modal = app.NewModal("queue", "Queue", q.table, 40, 0)
+	q.stats = tview.NewTextView()
+	q.stats.SetDynamicColors(true)
+	q.stats.SetTextAlign(tview.AlignCenter)
+	q.stats.SetBackgroundColor(tcell.ColorDefault)
+
+	q.input = tview.NewInputField()
+	q.input.SetLabel("[::b]Filter: ")
+	q.input.SetLabelColor(tcell.ColorWhite)
+	q.input.SetBackgroundColor(tcell.ColorDefault)
+	q.input.SetFieldBackgroundColor(tcell.ColorDefault)
+	q.input.SetChangedFunc(func(text string) {
+		if cmd.GetOptionValue("search-mode") == "fuzzy" {
+			q.table.Select(0, 0)
+		}
+
+		q.render(q.data)
+	})
+	q.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyEnter:
+			app.UI.SetFocus(q.table)
+		}
+
+		return event
+	})
+
+	q.flex = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(q.stats, 1, 0, false).
+		AddItem(app.HorizontalLine(), 1, 0, false).
+		AddItem(q.table, 0, 1, true).
+		AddItem(app.HorizontalLine(), 1, 0, false).
+		AddItem(q.input, 1, 0, false)
+
+	q.modal = app.NewModal("queue", "Queue", q.flex, 40, 0)
 
 	q.lock = semaphore.NewWeighted(1)
 
@@ -76,6 +127,8 @@ func (q *Queue) Start() {
 				q.render(data)
 			})
 
+			emitQueueEvent(data)
+
 		case <-q.status:
 			app.UI.QueueUpdateDraw(func() {
 				q.render(q.data)
@@ -91,6 +144,7 @@ func (q *Queue) Show() {
 	}
 
 	q.modal.Show(true)
+	q.input.SetText("")
 	q.sendStatus()
 }
 
@@ -99,6 +153,41 @@ func (q *Queue) Hide() {
 	q.modal.Exit(false)
 }
 
+// autoShow shows the player queue after entries are loaded into it,
+// unless the queue has been toggled to show only the player.
+func (q *Queue) autoShow() {
+	if q.playerOnly {
+		return
+	}
+
+	q.Show()
+}
+
+// toggle switches between showing the player queue and showing only the player.
+// The mode is remembered, so that the queue is not shown automatically after
+// entries are loaded into it while only the player is shown.
+func (q *Queue) toggle() {
+	if q.modal.Open {
+		q.playerOnly = true
+		q.Hide()
+
+		app.ShowInfo("Queue: Showing only the player", false)
+
+		return
+	}
+
+	q.playerOnly = false
+
+	if len(q.data) == 0 {
+		app.ShowInfo("Queue: The queue will be shown when entries are loaded", false)
+		return
+	}
+
+	q.Show()
+
+	app.ShowInfo("Queue: Showing the queue", false)
+}
+
 // Keybindings define the keybindings for the queue.
 func (q *Queue) Keybindings(event *tcell.EventKey) *tcell.EventKey {
 	operation := cmd.KeyOperation(event, cmd.KeyContextQueue)
@@ -130,7 +219,28 @@ func (q *Queue) Keybindings(event *tcell.EventKey) *tcell.EventKey {
 	case cmd.KeyQueueMove:
 		q.move()
 
-	case cmd.KeyPlayerStop, cmd.KeyClose:
+	case cmd.KeyQueueMoveUp:
+		q.shift(-1)
+		return nil
+
+	case cmd.KeyQueueMoveDown:
+		q.shift(1)
+		return nil
+
+	case cmd.KeyQueueSearch:
+		app.UI.SetFocus(q.input)
+
+	case cmd.KeyQueueRetryFailed:
+		go q.retryFailed()
+
+	case cmd.KeyQueueToggleMediaType:
+		q.toggleMediaType()
+
+	case cmd.KeyQueueClearOthers, cmd.KeyQueueRemoveDuplicates,
+		cmd.KeyQueueRemoveAbove, cmd.KeyQueueRemoveBelow:
+		q.bulkRemove(operation)
+
+	case cmd.KeyPlayerStop, cmd.KeyPlayerClose, cmd.KeyClose:
 		q.Hide()
 	}
 
@@ -149,26 +259,18 @@ func (q *Queue) Keybindings(event *tcell.EventKey) *tcell.EventKey {
 
 // play handles the 'Enter' key event within the queue.
 // If the move mode is enabled, the currently moving item
-// is set to the position where the selector rests.
+// is dropped at the position where the selector rests.
 // Otherwise, it plays the currently selected queue item.
 func (q *Queue) play() {
 	row, _ := q.table.GetSelection()
+	pos := q.position(row)
 
 	if q.moveMode {
-		selected := row
-		if row > q.prevrow {
-			row++
-		}
-
-		mp.Player().QueueMove(row, q.prevrow)
-
-		q.moveMode = false
-		q.table.Select(selected, 0)
-
+		q.drop(row)
 		return
 	}
 
-	mp.Player().QueueSwitchToTrack(row)
+	mp.Player().QueueSwitchToTrack(pos)
 	mp.Player().Play()
 
 	sendPlayerEvents()
@@ -179,6 +281,7 @@ func (q *Queue) play() {
 func (q *Queue) remove() {
 	rows := q.table.GetRowCount()
 	row, _ := q.table.GetSelection()
+	pos := q.position(row)
 
 	switch {
 	case row >= rows-1:
@@ -191,22 +294,297 @@ func (q *Queue) remove() {
 		q.table.Select(row, 0)
 	}
 
-	q.removeVideo(row)
+	q.removeVideo(pos)
 
-	mp.Player().QueueDelete(row)
+	mp.Player().QueueDelete(pos)
 
-	pos := mp.Player().QueuePosition()
-	if pos == row {
+	if mp.Player().QueuePosition() == pos {
 		sendPlayerEvents()
 	}
 }
 
+// toggleMediaType reloads the selected entry with the other media type, i.e. an
+// audio entry is reloaded as video and vice versa.
+func (q *Queue) toggleMediaType() {
+	row, _ := q.table.GetSelection()
+	pos := q.position(row)
+
+	list := q.getQueueData()
+	if pos < 0 || pos >= len(list) {
+		return
+	}
+
+	entry := list[pos]
+	if entry.VideoID == "" || entry.VideoID == "-" {
+		app.ShowError(fmt.Errorf("Queue: Only videos can be switched between audio and video"))
+		return
+	}
+
+	go q.reloadEntry(pos, entry, entry.Type != "Audio")
+}
+
+// reloadEntry replaces the entry at the provided position with the same video
+// loaded with the provided media type. If the entry is playing, the playback
+// is resumed from the same position.
+func (q *Queue) reloadEntry(pos int, entry QueueData, audio bool) {
+	media := "video"
+	if audio {
+		media = "audio"
+	}
+
+	app.ShowInfo("Switching "+entry.Title+" to "+media, true)
+
+	if err := replaceTrack(pos, entry.VideoID, audio, entry.Playing, mp.Player().Position()); err != nil {
+		app.ShowError(fmt.Errorf("Queue: Unable to switch %s to %s", entry.Title, media))
+		return
+	}
+
+	app.ShowInfo("Switched "+entry.Title+" to "+media, false)
+}
+
+// bulkRemove selects the tracks to be removed for the provided operation,
+// and removes them from the queue once the user confirms the removal.
+func (q *Queue) bulkRemove(operation cmd.Key) {
+	var description string
+
+	list := q.getQueueData()
+	row, _ := q.table.GetSelection()
+	positions := bulkPositions(operation, list, q.rows, row, mp.Player().QueuePosition())
+
+	switch operation {
+	case cmd.KeyQueueClearOthers:
+		description = "other"
+
+	case cmd.KeyQueueRemoveDuplicates:
+		description = "duplicate"
+
+	case cmd.KeyQueueRemoveAbove:
+		description = "above"
+
+	case cmd.KeyQueueRemoveBelow:
+		description = "below"
+	}
+
+	if len(positions) == 0 {
+		app.ShowInfo("Queue: No tracks to remove", false)
+		return
+	}
+
+	label := fmt.Sprintf("Remove %d %s tracks? (y/n)", len(positions), description)
+	if description == "above" || description == "below" {
+		label = fmt.Sprintf("Remove %d tracks %s the selection? (y/n)", len(positions), description)
+	}
+
+	app.UI.Status.SetInput(label, 1, true, func(reply string) {
+		if reply != "y" {
+			return
+		}
+
+		q.removePositions(list, positions)
+		q.table.Select(0, 0)
+
+		app.ShowInfo(fmt.Sprintf("Queue: Removed %d tracks", len(positions)), false)
+	}, nil)
+}
+
+// removePositions removes the tracks at the provided positions from the queue.
+// The positions must be in ascending order, and are removed from the last position
+// onwards, so that the positions of the remaining tracks do not change in between.
+// Videos are only removed from the store if no remaining track refers to them.
+func (q *Queue) removePositions(list []QueueData, positions []int) {
+	remove := make(map[int]struct{}, len(positions))
+	for _, pos := range positions {
+		remove[pos] = struct{}{}
+	}
+
+	remaining := make(map[string]struct{})
+	for i, data := range list {
+		if _, ok := remove[i]; !ok {
+			remaining[data.VideoID] = struct{}{}
+		}
+	}
+
+	player.mutex.Lock()
+	for _, pos := range positions {
+		data := list[pos]
+
+		if _, ok := remaining[data.VideoID]; !ok {
+			delete(q.videos, data.VideoID)
+		}
+		delete(q.failed, data.ID)
+	}
+	player.mutex.Unlock()
+
+	for i := len(positions) - 1; i >= 0; i-- {
+		mp.Player().QueueDelete(positions[i])
+	}
+}
+
+// bulkPositions returns the positions of the tracks within the provided list which
+// are removed by the provided operation, in ascending order. The rows map the rows
+// shown in the queue to the positions of their tracks, so that if the queue is filtered,
+// only the shown tracks are removed, relative to the selected row. The track at the
+// current position is never removed.
+func bulkPositions(operation cmd.Key, list []QueueData, rows []int, row, current int) []int {
+	var shown []int
+
+	switch operation {
+	case cmd.KeyQueueRemoveDuplicates:
+		return duplicatePositions(list, current)
+
+	case cmd.KeyQueueClearOthers:
+		shown = rows
+
+	case cmd.KeyQueueRemoveAbove:
+		if row > 0 && row <= len(rows) {
+			shown = rows[:row]
+		}
+
+	case cmd.KeyQueueRemoveBelow:
+		if row >= 0 && row < len(rows) {
+			shown = rows[row+1:]
+		}
+	}
+
+	positions := make([]int, 0, len(shown))
+	for _, pos := range shown {
+		if pos != current && pos >= 0 && pos < len(list) {
+			positions = append(positions, pos)
+		}
+	}
+
+	sort.Ints(positions)
+
+	return positions
+}
+
+// duplicatePositions returns the positions of the tracks within the provided list,
+// which refer to a video that already appears in the list. If the track at the current
+// position is a duplicate, its other occurrences are selected instead, so that playback
+// is not interrupted.
+func duplicatePositions(list []QueueData, current int) []int {
+	var positions []int
+
+	seen := make(map[string]struct{}, len(list))
+	if current >= 0 && current < len(list) && list[current].VideoID != "" {
+		seen[list[current].VideoID] = struct{}{}
+	}
+
+	for i, data := range list {
+		if data.VideoID == "" || i == current {
+			continue
+		}
+
+		if _, ok := seen[data.VideoID]; ok {
+			positions = append(positions, i)
+			continue
+		}
+
+		seen[data.VideoID] = struct{}{}
+	}
+
+	return positions
+}
+
 // move handles the 'M' key within the queue.
-// It enables the move mode, and starts moving the selected entry.
+// It enables the move mode and grabs the selected entry, so that the selector
+// can be moved freely. If the move mode is already enabled, the grabbed entry
+// is dropped at the position where the selector rests.
 func (q *Queue) move() {
-	q.prevrow, _ = q.table.GetSelection()
+	row, _ := q.table.GetSelection()
+
+	if q.moveMode {
+		q.drop(row)
+		return
+	}
+
+	pos := q.position(row)
+	if pos < 0 || pos >= len(q.data) {
+		return
+	}
+
+	id, ok := q.data[pos]["id"].(float64)
+	if !ok {
+		return
+	}
+
+	q.grabbed = int(id)
 	q.moveMode = true
-	q.table.Select(q.prevrow, 0)
+
+	q.render(q.data)
+	q.table.Select(row, 0)
+}
+
+// drop disables the move mode, and moves the grabbed entry to the position
+// of the provided row. The grabbed entry is looked up by its playlist entry ID,
+// so that it is moved correctly even if the queue has changed since it was grabbed.
+func (q *Queue) drop(row int) {
+	from := -1
+	for i, pldata := range q.data {
+		if id, ok := pldata["id"].(float64); ok && int(id) == q.grabbed {
+			from = i
+			break
+		}
+	}
+
+	q.moveMode = false
+
+	if from < 0 {
+		app.ShowError(fmt.Errorf("Queue: The grabbed entry is no longer in the queue"))
+	} else if err := q.reorder(from, q.position(row)); err != nil {
+		app.ShowError(err)
+	}
+
+	q.render(q.data)
+	q.table.Select(row, 0)
+}
+
+// shift moves the selected entry up or down by one row.
+func (q *Queue) shift(offset int) {
+	row, _ := q.table.GetSelection()
+	target := row + offset
+
+	if target < 0 || target >= q.table.GetRowCount() {
+		return
+	}
+
+	if err := q.reorder(q.position(row), q.position(target)); err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	q.table.Select(target, 0)
+}
+
+// reorder moves the track at the 'from' position to the 'to' position within the queue.
+// The error monitor tracks entries by their playlist entry IDs, and the video store
+// tracks entries by their video IDs, both of which do not change when a track is moved,
+// so no other bookkeeping is required.
+func (q *Queue) reorder(from, to int) error {
+	target, err := moveTarget(from, to, mp.Player().QueueCount())
+	if err != nil || from == to {
+		return err
+	}
+
+	mp.Player().QueueMove(target, from)
+
+	return nil
+}
+
+// moveTarget returns the index which is passed to MPV's 'playlist-move' command, to
+// move the track at the 'from' position to the 'to' position within a queue of the
+// provided length. Since the index refers to the entry before which the track is
+// placed, it is one more than the 'to' position when the track is moved down.
+func moveTarget(from, to, count int) (int, error) {
+	if from < 0 || from >= count || to < 0 || to >= count {
+		return 0, fmt.Errorf("Queue: Cannot move entry from %d to %d", from+1, to+1)
+	}
+
+	if to > from {
+		to++
+	}
+
+	return to, nil
 }
 
 // selectorHandler checks whether the move mode is enabled or not,
@@ -238,7 +616,9 @@ func (q *Queue) selectorHandler(row, col int) {
 // render renders the player queue.
 func (q *Queue) render(data []map[string]interface{}) {
 	q.data = data
+	q.rows = nil
 	q.table.Clear()
+	q.renderStats(data)
 
 	if len(data) == 0 {
 		q.removeVideo(-1, struct{}{})
@@ -253,17 +633,23 @@ func (q *Queue) render(data []map[string]interface{}) {
 	pos, _ := q.table.GetSelection()
 	q.table.SetSelectable(false, false)
 
-	for i, pldata := range data {
-		var marker string
+	for row, match := range q.filter(data) {
+		var marker, status string
 
-		data := q.getData(i, pldata)
-		if data == (QueueData{}) {
-			continue
-		}
+		i, data, title := match.position, match.data, match.title
 
 		if data.Playing {
 			marker = " [white::b](playing)"
 		}
+		if isFavorite(data.VideoID) {
+			marker = " [yellow::b]★" + marker
+		}
+		if q.moveMode && data.ID == q.grabbed {
+			marker += " [yellow::b](moving)"
+		}
+		if q.isFailed(data.ID) {
+			status = "[red::b]✗"
+		}
 
 		info := inv.SearchData{
 			Title:   data.Title,
@@ -272,7 +658,7 @@ func (q *Queue) render(data []map[string]interface{}) {
 			VideoID: data.VideoID,
 		}
 
-		q.table.SetCell(i, 1, tview.NewTableCell("[blue::b]"+tview.Escape(data.Title)+marker).
+		q.table.SetCell(row, 1, tview.NewTableCell("[blue::b]"+title+marker).
 			SetExpansion(1).
 			SetMaxWidth(w/7).
 			SetReference(info).
@@ -280,34 +666,45 @@ func (q *Queue) render(data []map[string]interface{}) {
 			SetSelectedStyle(app.UI.ColumnStyle),
 		)
 
-		q.table.SetCell(i, 2, tview.NewTableCell(" ").
+		q.table.SetCell(row, 2, tview.NewTableCell(" ").
 			SetSelectable(false),
 		)
 
-		q.table.SetCell(i, 3, tview.NewTableCell("[purple::b]"+tview.Escape(data.Author)).
+		q.table.SetCell(row, 3, tview.NewTableCell("[purple::b]"+tview.Escape(data.Author)).
 			SetMaxWidth(w/5).
 			SetSelectable(true).
 			SetSelectedStyle(app.UI.ColumnStyle),
 		)
 
-		q.table.SetCell(i, 4, tview.NewTableCell(" ").
+		q.table.SetCell(row, 4, tview.NewTableCell(" ").
 			SetSelectable(false),
 		)
 
-		q.table.SetCell(i, 5, tview.NewTableCell("[pink::b]"+tview.Escape(data.Type)).
+		q.table.SetCell(row, 5, tview.NewTableCell("[pink::b]"+tview.Escape(data.Type)).
 			SetMaxWidth(w/5).
 			SetSelectable(true).
 			SetSelectedStyle(app.UI.ColumnStyle),
 		)
 
-		q.table.SetCell(i, 6, tview.NewTableCell(" ").
+		q.table.SetCell(row, 6, tview.NewTableCell(" ").
 			SetSelectable(false),
 		)
 
-		q.table.SetCell(i, 7, tview.NewTableCell("[pink::b]"+data.Duration).
+		q.table.SetCell(row, 7, tview.NewTableCell("[pink::b]"+data.Duration).
 			SetSelectable(true).
 			SetSelectedStyle(app.UI.ColumnStyle),
 		)
+
+		q.table.SetCell(row, 8, tview.NewTableCell(" ").
+			SetSelectable(false),
+		)
+
+		q.table.SetCell(row, 9, tview.NewTableCell(status).
+			SetSelectable(true).
+			SetSelectedStyle(app.UI.ColumnStyle),
+		)
+
+		q.rows = append(q.rows, i)
 	}
 
 	q.table.SetSelectable(true, false)
@@ -316,6 +713,93 @@ func (q *Queue) render(data []map[string]interface{}) {
 	app.ResizeModal()
 }
 
+// filter returns the queue entries which match the text in the queue filter input,
+// along with their titles highlighted according to the match. If fuzzy searching is
+// enabled, the entries are sorted by their match scores, with the best match first.
+func (q *Queue) filter(data []map[string]interface{}) []queueMatch {
+	var matches []queueMatch
+
+	filter := q.input.GetText()
+	fuzzy := cmd.GetOptionValue("search-mode") == "fuzzy" && filter != ""
+
+	for i, pldata := range data {
+		var score int
+		var title string
+		var matched bool
+
+		entry := q.getData(i, pldata)
+		if entry == (QueueData{}) {
+			continue
+		}
+
+		if fuzzy {
+			var positions []int
+
+			score, positions, matched = utils.FuzzyMatch(entry.Title, filter)
+			title = highlightPositions(entry.Title, positions)
+		} else {
+			title, matched = highlightMatch(entry.Title, strings.ToLower(filter))
+		}
+		if !matched {
+			continue
+		}
+
+		matches = append(matches, queueMatch{i, score, title, entry})
+	}
+
+	if fuzzy {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+	}
+
+	return matches
+}
+
+// renderStats renders the track count and the total duration of the queue.
+// Live tracks have no known duration, and are only counted separately.
+func (q *Queue) renderStats(data []map[string]interface{}) {
+	var total int64
+	var tracks, live int
+
+	for _, pldata := range data {
+		filename, _ := pldata["filename"].(string)
+
+		tracks++
+
+		urlData := utils.GetDataFromURL(filename)
+		if urlData == nil {
+			continue
+		}
+
+		length := urlData.Get("length")
+		if length == "Live" {
+			live++
+			continue
+		}
+
+		total += parseDuration(length)
+	}
+
+	text := fmt.Sprintf("[::b]%d tracks, %s", tracks, utils.FormatDuration(total))
+	if live > 0 {
+		text += fmt.Sprintf(" (+%d live)", live)
+	}
+
+	q.stats.SetText(text)
+}
+
+// position returns the position of the track within the queue
+// for the provided row. This is required since the rows within
+// the queue view may be filtered.
+func (q *Queue) position(row int) int {
+	if row < 0 || row >= len(q.rows) {
+		return row
+	}
+
+	return q.rows[row]
+}
+
 // getData organises and returns the queue data from the provided playlist data map.
 func (q *Queue) getData(row int, pldata map[string]interface{}) QueueData {
 	var id int
@@ -333,24 +817,26 @@ func (q *Queue) getData(row int, pldata map[string]interface{}) QueueData {
 		playing = p
 	}
 
+	// Entries from external playlists may be local files, or URLs
+	// without any invidtui-specific data, so only their title is shown.
 	urlData := utils.GetDataFromURL(filename)
 	if urlData == nil {
-		return (QueueData{})
+		urlData = make(url.Values)
 	}
 
 	for _, udata := range []string{
-		"id",
 		"title",
 		"author",
 		"length",
 		"mediatype",
 	} {
-		if urlData.Get("id") == "" {
-			continue
-		}
-
 		if udata == "title" && urlData.Get(udata) == "" {
-			urlData.Set(udata, mp.Player().Title(row))
+			title, _ := pldata["title"].(string)
+			if title == "" {
+				title = filepath.Base(mp.Player().Title(row))
+			}
+
+			urlData.Set(udata, title)
 			continue
 		}
 
@@ -377,7 +863,7 @@ func (q *Queue) appendFrom(file string) {
 	app.ShowInfo("Loading "+filepath.Base(file), true)
 
 	app.UI.QueueUpdateDraw(func() {
-		player.queue.Show()
+		player.queue.autoShow()
 	})
 
 	err := mp.Player().LoadPlaylist(file, false, checkLiveURL)
@@ -408,7 +894,12 @@ func (q *Queue) saveAs(file string) {
 		return
 	}
 
-	entries, err := q.generatePlaylist(file, list, appendToFile)
+	portable, confirm := q.confirmFormat()
+	if !confirm {
+		return
+	}
+
+	entries, err := q.generatePlaylist(file, list, appendToFile, portable)
 	if err != nil {
 		app.ShowError(err)
 		return
@@ -431,6 +922,10 @@ func (q *Queue) saveAs(file string) {
 		message = " appended to "
 	}
 
+	if portable {
+		message = " (stream URLs may expire)" + message
+	}
+
 	app.ShowInfo("Playlist"+message+file, false)
 
 	app.UI.FileBrowser.Hide()
@@ -467,6 +962,29 @@ func (q *Queue) confirmOverwrite(file string) (int, bool, bool, bool) {
 	return flags, appendToFile, reply != "", true
 }
 
+// confirmFormat displays a playlist format selection message within the file browser.
+// The "invidtui" format stores the track data within the entries' URLs, so that the
+// playlist can be loaded into invidtui again, and the "portable" format stores the
+// track data within EXTINF headers and the direct stream URLs, for use in other players.
+func (q *Queue) confirmFormat() (bool, bool) {
+	reply := app.UI.FileBrowser.Query("Playlist format (i)nvidtui/(p)ortable?", q.validateFormat, 1)
+
+	return reply == "p", reply != ""
+}
+
+// validateFormat validates the playlist format selection.
+func (q *Queue) validateFormat(text string, reply chan string) {
+	if text != "i" && text != "p" {
+		return
+	}
+
+	select {
+	case reply <- text:
+
+	default:
+	}
+}
+
 // validate validates the overwrite confirmation reply.
 func (q *Queue) validate(text string, reply chan string) {
 	for _, option := range []string{"y", "n", "a"} {
@@ -503,8 +1021,8 @@ func (q *Queue) getQueueData() []QueueData {
 
 	for i := range data {
 		data[i] = q.getData(i, map[string]interface{}{
-			"id":       data[i].ID,
-			"playing":  data[i].Playing,
+			"id":       float64(data[i].ID),
+			"current":  data[i].Playing,
 			"filename": data[i].Filename,
 		})
 	}
@@ -513,7 +1031,7 @@ func (q *Queue) getQueueData() []QueueData {
 }
 
 // generatePlaylist generates a playlist file.
-func (q *Queue) generatePlaylist(file string, list []QueueData, appendToFile bool) (string, error) {
+func (q *Queue) generatePlaylist(file string, list []QueueData, appendToFile, portable bool) (string, error) {
 	var skipped int
 	var entries string
 	var fileEntries map[string]struct{}
@@ -540,23 +1058,33 @@ func (q *Queue) generatePlaylist(file string, list []QueueData, appendToFile boo
 		}
 	}
 
-	if !appendToFile {
+	switch {
+	case appendToFile:
+		entries += "\n"
+
+	case portable:
+		entries += "#EXTM3U\n\n"
+
+	default:
 		entries += "#EXTM3U\n\n"
 		entries += "# Autogenerated by invidtui. DO NOT EDIT.\n\n"
-	} else {
-		entries += "\n"
 	}
 
 	for i, data := range list {
+		filename, extinf := data.Filename, "#EXTINF:,"+data.Title
+		if portable {
+			filename, extinf = portableEntry(data)
+		}
+
 		if appendToFile && fileEntries != nil {
-			if _, ok := fileEntries[data.Filename]; ok {
+			if _, ok := fileEntries[filename]; ok {
 				skipped++
 				continue
 			}
 		}
 
-		entries += "#EXTINF:," + data.Title + "\n"
-		entries += data.Filename + "\n"
+		entries += extinf + "\n"
+		entries += filename + "\n"
 
 		if i != len(list)-1 {
 			entries += "\n"
@@ -570,6 +1098,39 @@ func (q *Queue) generatePlaylist(file string, list []QueueData, appendToFile boo
 	return entries, nil
 }
 
+// portableEntry returns the stream URL and the EXTINF header of the provided
+// queue entry, without any invidtui-specific data in the URL.
+func portableEntry(data QueueData) (string, string) {
+	duration := int64(-1)
+	if data.Duration != "Live" {
+		duration = parseDuration(data.Duration)
+	}
+
+	title := data.Title
+	if data.Author != "" && data.Author != "-" {
+		title = data.Author + " - " + title
+	}
+
+	extinf := "#EXTINF:" + strconv.FormatInt(duration, 10) + "," + title
+
+	uri, err := url.Parse(data.Filename)
+	if err != nil || uri.Scheme == "" {
+		return data.Filename, extinf
+	}
+
+	query := uri.Query()
+	for _, key := range []string{"title", "author", "mediatype", "length", "options"} {
+		query.Del(key)
+	}
+	if ids := query["id"]; len(ids) > 1 {
+		query["id"] = ids[:1]
+	}
+
+	uri.RawQuery = query.Encode()
+
+	return uri.String(), extinf
+}
+
 // currentVideo sets or returns the video to/from the store
 // according to the provided ID.
 func (q *Queue) currentVideo(id string, set ...*inv.VideoData) *inv.VideoData {
@@ -593,8 +1154,14 @@ func (q *Queue) removeVideo(pos int, reset ...struct{}) {
 	player.mutex.Lock()
 	defer player.mutex.Unlock()
 
-	if reset != nil && len(q.videos) > 0 {
-		q.videos = make(map[string]*inv.VideoData)
+	if reset != nil {
+		if len(q.videos) > 0 {
+			q.videos = make(map[string]*inv.VideoData)
+		}
+		if len(q.failed) > 0 {
+			q.failed = make(map[int]struct{})
+		}
+
 		return
 	}
 
@@ -609,6 +1176,145 @@ func (q *Queue) removeVideo(pos int, reset ...struct{}) {
 	delete(q.videos, id)
 }
 
+// removeFinished removes the track with the provided playlist entry ID from the queue,
+// if the 'queue-autoclear' option is enabled. Tracks are not removed if they are looped.
+func (q *Queue) removeFinished(id int) {
+	if id < 0 || !cmd.IsOptionEnabled("queue-autoclear") || mp.Player().LoopMode() != "" {
+		return
+	}
+
+	list := q.getQueueData()
+	for i, data := range list {
+		if data.ID == id {
+			q.removePositions(list, []int{i})
+			break
+		}
+	}
+}
+
+// parseDuration parses a hh:mm:ss or mm:ss string and returns the duration in seconds.
+func parseDuration(text string) int64 {
+	var seconds int64
+
+	for _, part := range strings.Split(text, ":") {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	return seconds
+}
+
+// highlightMatch returns the escaped text with the part that matches the filter
+// highlighted, and whether the text matched the filter or not.
+func highlightMatch(text, filter string) (string, bool) {
+	if filter == "" {
+		return tview.Escape(text), true
+	}
+
+	lower := strings.ToLower(text)
+
+	index := strings.Index(lower, filter)
+	if index < 0 {
+		return "", false
+	}
+	if len(lower) != len(text) {
+		return tview.Escape(text), true
+	}
+
+	end := index + len(filter)
+
+	return tview.Escape(text[:index]) +
+		"[yellow::bu]" + tview.Escape(text[index:end]) + "[-:-:-][blue::b]" +
+		tview.Escape(text[end:]), true
+}
+
+// highlightPositions returns the escaped text with the runes
+// at the provided positions highlighted.
+func highlightPositions(text string, positions []int) string {
+	var highlighted strings.Builder
+
+	matched := make(map[int]struct{}, len(positions))
+	for _, pos := range positions {
+		matched[pos] = struct{}{}
+	}
+
+	for i, r := range []rune(text) {
+		if _, ok := matched[i]; ok {
+			highlighted.WriteString("[yellow::bu]" + tview.Escape(string(r)) + "[-:-:-][blue::b]")
+			continue
+		}
+
+		highlighted.WriteString(tview.Escape(string(r)))
+	}
+
+	return highlighted.String()
+}
+
+// markFailed marks the track with the provided playlist entry ID as failed.
+// Since playlist entry IDs do not change when tracks are moved within the queue,
+// the failed tracks can be tracked accurately across reorders.
+func (q *Queue) markFailed(id int) {
+	player.mutex.Lock()
+	q.failed[id] = struct{}{}
+	player.mutex.Unlock()
+
+	q.sendStatus()
+}
+
+// isFailed returns whether the track with the provided playlist entry ID has failed.
+func (q *Queue) isFailed(id int) bool {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	_, ok := q.failed[id]
+
+	return ok
+}
+
+// retryFailed removes the failed tracks from the queue, and reloads
+// each of them at their original position within the queue.
+func (q *Queue) retryFailed() {
+	var retried int
+
+	data := q.getQueueData()
+
+	for pos := len(data) - 1; pos >= 0; pos-- {
+		entry := data[pos]
+		if !q.isFailed(entry.ID) {
+			continue
+		}
+
+		player.mutex.Lock()
+		delete(q.failed, entry.ID)
+		player.mutex.Unlock()
+
+		if entry.VideoID == "" || entry.VideoID == "-" {
+			continue
+		}
+
+		app.ShowInfo("Retrying "+entry.Title, true)
+
+		mp.Player().QueueDelete(pos)
+		if _, err := loadVideo(entry.VideoID, entry.Type == "Audio", pos); err != nil {
+			app.ShowError(fmt.Errorf("Queue: Unable to retry %s", entry.Title))
+			continue
+		}
+
+		retried++
+	}
+
+	if retried == 0 {
+		app.ShowInfo("", false)
+		return
+	}
+
+	app.ShowInfo(fmt.Sprintf("Retried %d failed tracks", retried), false)
+}
+
 // sendStatus sends status events to the queue.
 func (q *Queue) sendStatus() {
 	select {
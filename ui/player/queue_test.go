@@ -0,0 +1,301 @@
+package player
+
+import (
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+)
+
+// playlistMove moves the entry at index1 of the provided playlist so that it takes
+// the place of the entry at index2, like MPV's 'playlist-move' command.
+func playlistMove(playlist []int, index1, index2 int) []int {
+	moved := make([]int, 0, len(playlist))
+	entry := playlist[index1]
+
+	for i, e := range playlist {
+		if i == index2 {
+			moved = append(moved, entry)
+		}
+		if i != index1 {
+			moved = append(moved, e)
+		}
+	}
+	if index2 >= len(playlist) {
+		moved = append(moved, entry)
+	}
+
+	return moved
+}
+
+func TestMoveTarget(t *testing.T) {
+	const count = 5
+
+	for from := 0; from < count; from++ {
+		for to := 0; to < count; to++ {
+			target, err := moveTarget(from, to, count)
+			if err != nil {
+				t.Fatalf("moveTarget(%d, %d) returned error: %v", from, to, err)
+			}
+
+			playlist := []int{0, 1, 2, 3, 4}
+			moved := playlistMove(playlist, from, target)
+
+			if moved[to] != from {
+				t.Errorf("moving %d to %d placed it at %d: %v", from, to, indexOf(moved, from), moved)
+			}
+			if len(moved) != count {
+				t.Errorf("moving %d to %d changed the queue length: %v", from, to, moved)
+			}
+		}
+	}
+}
+
+func TestMoveTargetShift(t *testing.T) {
+	tests := []struct {
+		from, to int
+		want     []int
+	}{
+		{from: 0, to: 1, want: []int{1, 0, 2, 3}},
+		{from: 1, to: 0, want: []int{1, 0, 2, 3}},
+		{from: 2, to: 3, want: []int{0, 1, 3, 2}},
+		{from: 3, to: 2, want: []int{0, 1, 3, 2}},
+	}
+
+	for _, test := range tests {
+		target, err := moveTarget(test.from, test.to, 4)
+		if err != nil {
+			t.Fatalf("moveTarget(%d, %d) returned error: %v", test.from, test.to, err)
+		}
+
+		if moved := playlistMove([]int{0, 1, 2, 3}, test.from, target); !reflect.DeepEqual(moved, test.want) {
+			t.Errorf("moving %d to %d = %v, want %v", test.from, test.to, moved, test.want)
+		}
+	}
+}
+
+func TestMoveTargetOutOfRange(t *testing.T) {
+	tests := []struct {
+		from, to, count int
+	}{
+		{from: -1, to: 0, count: 3},
+		{from: 0, to: -1, count: 3},
+		{from: 3, to: 0, count: 3},
+		{from: 0, to: 3, count: 3},
+		{from: 0, to: 0, count: 0},
+	}
+
+	for _, test := range tests {
+		if _, err := moveTarget(test.from, test.to, test.count); err == nil {
+			t.Errorf("moveTarget(%d, %d, %d) returned no error", test.from, test.to, test.count)
+		}
+	}
+}
+
+func indexOf(list []int, value int) int {
+	for i, v := range list {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func TestQueuePositionMapping(t *testing.T) {
+	q := &Queue{rows: []int{4, 1, 3}}
+
+	tests := []struct {
+		row, want int
+	}{
+		{row: 0, want: 4},
+		{row: 1, want: 1},
+		{row: 2, want: 3},
+		{row: 3, want: 3},
+		{row: -1, want: -1},
+	}
+
+	for _, test := range tests {
+		if pos := q.position(test.row); pos != test.want {
+			t.Errorf("position(%d) = %d, want %d", test.row, pos, test.want)
+		}
+	}
+}
+
+func TestBulkPositions(t *testing.T) {
+	var list []QueueData
+	for _, id := range []string{"a", "b", "a", "c", "b", "", "", "c"} {
+		list = append(list, QueueData{SearchData: inv.SearchData{VideoID: id}})
+	}
+	all := []int{0, 1, 2, 3, 4, 5, 6, 7}
+
+	tests := []struct {
+		name      string
+		operation cmd.Key
+		rows      []int
+		row       int
+		current   int
+		want      []int
+	}{
+		{name: "others", operation: cmd.KeyQueueClearOthers, rows: all, row: 0, current: 3, want: []int{0, 1, 2, 4, 5, 6, 7}},
+		{name: "others without current", operation: cmd.KeyQueueClearOthers, rows: all, row: 0, current: -1, want: all},
+		{name: "duplicates", operation: cmd.KeyQueueRemoveDuplicates, rows: all, current: -1, want: []int{2, 4, 7}},
+		{name: "duplicates of current", operation: cmd.KeyQueueRemoveDuplicates, rows: all, current: 2, want: []int{0, 4, 7}},
+		{name: "above", operation: cmd.KeyQueueRemoveAbove, rows: all, row: 3, current: 1, want: []int{0, 2}},
+		{name: "above first row", operation: cmd.KeyQueueRemoveAbove, rows: all, row: 0, current: -1, want: []int{}},
+		{name: "below", operation: cmd.KeyQueueRemoveBelow, rows: all, row: 4, current: 6, want: []int{5, 7}},
+		{name: "below last row", operation: cmd.KeyQueueRemoveBelow, rows: all, row: 7, current: -1, want: []int{}},
+		{name: "no selection", operation: cmd.KeyQueueRemoveBelow, rows: all, row: -1, current: -1, want: []int{}},
+
+		{name: "filtered others", operation: cmd.KeyQueueClearOthers, rows: []int{1, 4, 6}, row: 0, current: 4, want: []int{1, 6}},
+		{name: "filtered above", operation: cmd.KeyQueueRemoveAbove, rows: []int{1, 4, 6}, row: 2, current: -1, want: []int{1, 4}},
+		{name: "filtered below", operation: cmd.KeyQueueRemoveBelow, rows: []int{1, 4, 6}, row: 0, current: 6, want: []int{4}},
+		{name: "sorted above", operation: cmd.KeyQueueRemoveAbove, rows: []int{7, 2, 5, 0}, row: 2, current: -1, want: []int{2, 7}},
+		{name: "sorted below", operation: cmd.KeyQueueRemoveBelow, rows: []int{7, 2, 5, 0}, row: 1, current: -1, want: []int{0, 5}},
+		{name: "filtered duplicates", operation: cmd.KeyQueueRemoveDuplicates, rows: []int{0}, row: 0, current: -1, want: []int{2, 4, 7}},
+		{name: "stale rows", operation: cmd.KeyQueueClearOthers, rows: []int{1, 9}, row: 0, current: -1, want: []int{1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			positions := bulkPositions(test.operation, list, test.rows, test.row, test.current)
+			if len(positions) == 0 && len(test.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(positions, test.want) {
+				t.Errorf("bulkPositions() = %v, want %v", positions, test.want)
+			}
+		})
+	}
+}
+
+// playlistEntries returns the entry lines of the provided playlist,
+// along with the EXTINF headers preceding them.
+func playlistEntries(playlist string) ([]string, []string) {
+	var entries, headers []string
+
+	for _, line := range strings.Split(playlist, "\n") {
+		switch {
+		case strings.HasPrefix(line, "#EXTINF:"):
+			headers = append(headers, line)
+
+		case line != "" && !strings.HasPrefix(line, "#"):
+			entries = append(entries, line)
+		}
+	}
+
+	return entries, headers
+}
+
+// queueEntry returns the queue data of an entry with the provided properties,
+// with its filename in the format in which it is loaded into the player.
+func queueEntry(id, title, author, mediatype, length string) QueueData {
+	options := "force-media-title=%" + strconv.Itoa(len(title)) + "%" + title
+	if mediatype == "Audio" {
+		options += ",vid=no"
+	}
+
+	filename := "https://inv.example.com/latest_version?id=" + id + "&itag=251&local=true" +
+		"&id=" + url.QueryEscape(id) + "&title=" + url.QueryEscape(title) +
+		"&author=" + url.QueryEscape(author) + "&mediatype=" + mediatype +
+		"&length=" + url.QueryEscape(length) + "&options=" + url.QueryEscape(options)
+
+	return QueueData{
+		Filename: filename,
+		SearchData: inv.SearchData{
+			VideoID:  id,
+			Title:    title,
+			Author:   author,
+			Type:     mediatype,
+			Duration: length,
+		},
+	}
+}
+
+func TestGeneratePlaylistRoundTrip(t *testing.T) {
+	q := &Queue{}
+	list := []QueueData{
+		queueEntry("abc", "First & Video", "Author", "Video", "3:05"),
+		queueEntry("def", "Second, Audio", "Other Author", "Audio", "1:02:03"),
+		queueEntry("ghi", "Stream", "Streamer", "Video", "Live"),
+	}
+
+	playlist, err := q.generatePlaylist("", list, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(playlist, "#EXTM3U\n\n# Autogenerated by invidtui. DO NOT EDIT.\n\n") {
+		t.Errorf("playlist has an invalid header:\n%s", playlist)
+	}
+
+	entries, headers := playlistEntries(playlist)
+	if len(entries) != len(list) || len(headers) != len(list) {
+		t.Fatalf("playlist has %d entries and %d headers, want %d:\n%s", len(entries), len(headers), len(list), playlist)
+	}
+
+	for i, entry := range entries {
+		if want := "#EXTINF:," + list[i].Title; headers[i] != want {
+			t.Errorf("header %d = %q, want %q", i, headers[i], want)
+		}
+
+		data := q.getData(i, map[string]interface{}{"filename": entry})
+		if !reflect.DeepEqual(data, list[i]) {
+			t.Errorf("entry %d = %+v, want %+v", i, data, list[i])
+		}
+	}
+}
+
+func TestGeneratePortablePlaylist(t *testing.T) {
+	q := &Queue{}
+	list := []QueueData{
+		queueEntry("abc", "First & Video", "Author", "Video", "3:05"),
+		queueEntry("def", "Second, Audio", "-", "Audio", "1:02:03"),
+		queueEntry("ghi", "Stream", "Streamer", "Video", "Live"),
+		{Filename: "/music/local.mp3", SearchData: inv.SearchData{Title: "local.mp3", Duration: "-"}},
+	}
+
+	playlist, err := q.generatePlaylist("", list, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "#EXTM3U\n\n" +
+		"#EXTINF:185,Author - First & Video\n" +
+		"https://inv.example.com/latest_version?id=abc&itag=251&local=true\n\n" +
+		"#EXTINF:3723,Second, Audio\n" +
+		"https://inv.example.com/latest_version?id=def&itag=251&local=true\n\n" +
+		"#EXTINF:-1,Streamer - Stream\n" +
+		"https://inv.example.com/latest_version?id=ghi&itag=251&local=true\n\n" +
+		"#EXTINF:0,local.mp3\n" +
+		"/music/local.mp3\n"
+
+	if playlist != want {
+		t.Errorf("playlist =\n%s\nwant\n%s", playlist, want)
+	}
+}
+
+func TestHighlightPositions(t *testing.T) {
+	tests := []struct {
+		text      string
+		positions []int
+		want      string
+	}{
+		{text: "abc", positions: nil, want: "abc"},
+		{text: "abc", positions: []int{0, 2}, want: "[yellow::bu]a[-:-:-][blue::b]b[yellow::bu]c[-:-:-][blue::b]"},
+		{text: "Über", positions: []int{1}, want: "Ü[yellow::bu]b[-:-:-][blue::b]er"},
+		{text: "[a]", positions: []int{1}, want: "[[yellow::bu]a[-:-:-][blue::b]]"},
+		{text: "ab", positions: []int{5}, want: "ab"},
+	}
+
+	for _, test := range tests {
+		if highlighted := highlightPositions(test.text, test.positions); highlighted != test.want {
+			t.Errorf("highlightPositions(%q, %v) = %q, want %q", test.text, test.positions, highlighted, test.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// RateLimit stores the time until which requests
+// to load media should be delayed.
+type RateLimit struct {
+	until time.Time
+
+	// now and sleep return the current time and wait for the provided
+	// duration. If they are not set, the system clock is used.
+	now   func() time.Time
+	sleep func(ctx context.Context, delay time.Duration) error
+
+	mutex sync.Mutex
+}
+
+var rateLimit RateLimit
+
+// retryRateLimited runs the fetch function, and retries it with an exponential
+// backoff if the instance's rate-limit is exceeded. The backoff is shared across
+// all the loaders, so that concurrent loads wait together instead of retrying at once.
+func retryRateLimited(ctx context.Context, fetch func() error) error {
+	retries, err := strconv.Atoi(cmd.GetOptionValue("rate-limit-retries"))
+	if err != nil {
+		retries = 3
+	}
+
+	delay, err := time.ParseDuration(cmd.GetOptionValue("rate-limit-delay"))
+	if err != nil {
+		delay = 2 * time.Second
+	}
+
+	return rateLimit.retry(ctx, retries, delay, fetch, func(backoff time.Duration, attempt int) {
+		utils.LogWarnf("Player: Rate-limited, retrying in %s (%d/%d)", backoff, attempt, retries)
+
+		app.ShowInfo(
+			fmt.Sprintf("Player: Rate-limited, retrying in %s (%d/%d)",
+				backoff.Round(time.Second), attempt, retries,
+			), true,
+		)
+	})
+}
+
+// retry runs the fetch function, and retries it up to the provided number of times
+// if the rate-limit is exceeded. The backoff starts from the provided delay and doubles
+// after every attempt, with a random jitter of up to the delay added to it. The notify
+// function is called with the backoff and the attempt number before every retry.
+func (r *RateLimit) retry(
+	ctx context.Context, retries int, delay time.Duration,
+	fetch func() error, notify func(backoff time.Duration, attempt int),
+) error {
+	for attempt := 0; ; attempt++ {
+		if err := r.wait(ctx); err != nil {
+			return err
+		}
+
+		err := fetch()
+		if !errors.Is(err, client.ErrRateLimited) || attempt >= retries {
+			return err
+		}
+
+		backoff := delay*time.Duration(1<<attempt) + time.Duration(rand.Int63n(int64(delay)))
+		r.extend(backoff)
+
+		notify(backoff, attempt+1)
+	}
+}
+
+// wait waits until the rate-limit delay has passed, or the context is canceled.
+func (r *RateLimit) wait(ctx context.Context) error {
+	r.mutex.Lock()
+	delay := r.until.Sub(r.clock())
+	sleep := r.sleep
+	r.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if sleep != nil {
+		return sleep(ctx, delay)
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-t.C:
+	}
+
+	return nil
+}
+
+// extend extends the rate-limit delay by the provided duration,
+// if it ends later than the current delay.
+func (r *RateLimit) extend(delay time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if until := r.clock().Add(delay); until.After(r.until) {
+		r.until = until
+	}
+}
+
+// clock returns the current time. It must be called with the mutex held.
+func (r *RateLimit) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+
+	return time.Now()
+}
@@ -0,0 +1,134 @@
+package player
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+)
+
+// fakeClock returns a rate-limit whose clock only advances when it sleeps,
+// along with the slice to which the durations it slept for are appended.
+func fakeClock() (*RateLimit, *[]time.Duration) {
+	var slept []time.Duration
+
+	now := time.Unix(0, 0)
+	r := &RateLimit{
+		now: func() time.Time { return now },
+		sleep: func(ctx context.Context, delay time.Duration) error {
+			slept = append(slept, delay)
+			now = now.Add(delay)
+
+			return ctx.Err()
+		},
+	}
+
+	return r, &slept
+}
+
+func TestRateLimitRetryBackoff(t *testing.T) {
+	const delay = time.Second
+
+	for _, retries := range []int{0, 1, 3, 5} {
+		r, slept := fakeClock()
+
+		var fetches int
+		var notified []int
+
+		err := r.retry(context.Background(), retries, delay, func() error {
+			fetches++
+			return client.ErrRateLimited
+		}, func(backoff time.Duration, attempt int) {
+			notified = append(notified, attempt)
+		})
+
+		if !errors.Is(err, client.ErrRateLimited) {
+			t.Errorf("retries %d: got error %v, want %v", retries, err, client.ErrRateLimited)
+		}
+		if fetches != retries+1 {
+			t.Errorf("retries %d: fetched %d times, want %d", retries, fetches, retries+1)
+		}
+		if len(*slept) != retries || len(notified) != retries {
+			t.Fatalf("retries %d: slept %d and notified %d times, want %d",
+				retries, len(*slept), len(notified), retries,
+			)
+		}
+
+		for attempt, backoff := range *slept {
+			min := delay * time.Duration(1<<attempt)
+			if backoff < min || backoff >= min+delay {
+				t.Errorf("retries %d: backoff %d is %s, want within [%s, %s)",
+					retries, attempt, backoff, min, min+delay,
+				)
+			}
+			if notified[attempt] != attempt+1 {
+				t.Errorf("retries %d: notified attempt %d, want %d", retries, notified[attempt], attempt+1)
+			}
+		}
+	}
+}
+
+func TestRateLimitRetryStops(t *testing.T) {
+	other := errors.New("other")
+
+	tests := []struct {
+		name    string
+		results []error
+		want    error
+		fetches int
+	}{
+		{name: "success", results: []error{nil}, want: nil, fetches: 1},
+		{name: "other error", results: []error{other}, want: other, fetches: 1},
+		{
+			name:    "success after rate-limit",
+			results: []error{client.ErrRateLimited, client.ErrRateLimited, nil},
+			want:    nil,
+			fetches: 3,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, _ := fakeClock()
+
+			var fetches int
+
+			err := r.retry(context.Background(), 5, time.Second, func() error {
+				err := test.results[fetches]
+				fetches++
+
+				return err
+			}, func(time.Duration, int) {})
+
+			if err != test.want {
+				t.Errorf("got error %v, want %v", err, test.want)
+			}
+			if fetches != test.fetches {
+				t.Errorf("fetched %d times, want %d", fetches, test.fetches)
+			}
+		})
+	}
+}
+
+func TestRateLimitRetryCanceled(t *testing.T) {
+	r, _ := fakeClock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var fetches int
+
+	err := r.retry(ctx, 3, time.Second, func() error {
+		fetches++
+		return client.ErrRateLimited
+	}, func(time.Duration, int) {})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if fetches != 1 {
+		t.Errorf("fetched %d times, want 1", fetches)
+	}
+}
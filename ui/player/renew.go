@@ -0,0 +1,197 @@
+package player
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// Renewal stores the times at which the stream URLs of videos were renewed.
+type Renewal struct {
+	renewed map[string]time.Time
+
+	mutex sync.Mutex
+}
+
+// renewInterval is the minimum interval between the renewals of a video's stream URLs.
+// If a video fails again within this interval, its failure is not due to an expired URL.
+const renewInterval = 5 * time.Minute
+
+var renewal Renewal
+
+// renewFailedTrack checks whether the provided track failed due to its stream URL
+// having expired, or pointing to an instance which was switched from, and if so,
+// reloads it with renewed stream URLs and resumes it from the position at which
+// it failed. It returns whether the track is being renewed.
+func renewFailedTrack(track mp.FailedTrack) bool {
+	var entry QueueData
+
+	pos := -1
+	for i, data := range player.queue.getQueueData() {
+		if data.ID == track.ID {
+			pos, entry = i, data
+			break
+		}
+	}
+
+	if pos < 0 || entry.VideoID == "" || entry.VideoID == "-" {
+		return false
+	}
+
+	renewal.mutex.Lock()
+	defer renewal.mutex.Unlock()
+
+	if renewal.renewed == nil {
+		renewal.renewed = make(map[string]time.Time)
+	}
+
+	if !urlExpired(track, renewal.renewed[entry.VideoID]) && !instanceSwitched(entry) {
+		return false
+	}
+
+	renewal.renewed[entry.VideoID] = time.Now()
+
+	go renewTrack(pos, entry, track.Position)
+
+	return true
+}
+
+// expiredErrors lists the error texts with which the requests
+// for expired stream URLs are rejected.
+var expiredErrors = []string{
+	"http 403",
+	"http error 403",
+	"forbidden",
+}
+
+// urlExpired returns whether the provided track failed due to an expired stream URL,
+// which is rejected with an HTTP 403 (Forbidden) error. Since the stream URLs are valid
+// when a track starts playing, a track which failed before it was played is unavailable.
+// A track which fails again shortly after its URLs were renewed is also considered
+// unavailable, so that it is not renewed endlessly.
+func urlExpired(track mp.FailedTrack, renewed time.Time) bool {
+	if track.Position <= 0 || !isExpiredError(track.Error) {
+		return false
+	}
+
+	return renewed.IsZero() || time.Since(renewed) >= renewInterval
+}
+
+// isExpiredError returns whether the provided error text
+// indicates that the stream URL has expired.
+func isExpiredError(text string) bool {
+	text = strings.ToLower(text)
+
+	for _, expired := range expiredErrors {
+		if strings.Contains(text, expired) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// instanceSwitched returns whether the stream URL of the provided entry points to
+// an instance other than the current one, which happens if the instance was switched
+// after the entry was queued. The stream URLs of live streams are not instance URLs.
+func instanceSwitched(entry QueueData) bool {
+	if entry.Duration == "Live" {
+		return false
+	}
+
+	uri, err := url.Parse(entry.Filename)
+	if err != nil || uri.Hostname() == "" {
+		return false
+	}
+
+	return uri.Hostname() != utils.GetHostname(client.Instance())
+}
+
+// renewTrack reloads the provided queue entry at its position, switches to it and
+// resumes it from the provided position. If the video cannot be loaded, the entry
+// is marked as failed.
+func renewTrack(pos int, entry QueueData, position int64) {
+	utils.LogInfof("Player: Renewing stream URLs for video %s", entry.VideoID)
+	app.ShowInfo("Player: Renewing expired stream for "+entry.Title, true)
+
+	if err := replaceTrack(pos, entry.VideoID, entry.Type == "Audio", true, position); err != nil {
+		utils.LogErrorf("Player: Unable to renew stream URLs for video %s: %v", entry.VideoID, err)
+		app.ShowError(fmt.Errorf("Player: Unable to renew stream for %s", entry.Title))
+		player.queue.markFailed(entry.ID)
+
+		return
+	}
+
+	app.ShowInfo("Player: Renewed stream for "+entry.Title, false)
+}
+
+// reloadTrack reloads the currently playing track with renewed stream URLs,
+// and resumes it from the current position. Live streams are resumed from
+// the live position instead.
+func reloadTrack() {
+	pos := mp.Player().QueuePosition()
+
+	list := player.queue.getQueueData()
+	if pos < 0 || pos >= len(list) {
+		return
+	}
+
+	entry := list[pos]
+	if entry.VideoID == "" || entry.VideoID == "-" {
+		app.ShowError(fmt.Errorf("Player: Only videos can be reloaded"))
+		return
+	}
+
+	var position int64
+	if entry.Duration != "Live" {
+		position = mp.Player().Position()
+	}
+
+	app.ShowInfo("Player: Reloading "+entry.Title, true)
+
+	if err := replaceTrack(pos, entry.VideoID, entry.Type == "Audio", true, position); err != nil {
+		app.ShowError(fmt.Errorf("Player: Unable to reload %s", entry.Title))
+		return
+	}
+
+	app.ShowInfo("Player: Reloaded "+entry.Title, false)
+}
+
+// replaceTrack loads the provided video with the provided media type after the queue
+// entry at the provided position, and removes the entry. The start and end times of
+// the entry are preserved. If the entry is playing, the playback is switched to the
+// loaded video, and is resumed from the provided position.
+func replaceTrack(pos int, id string, audio, playing bool, position int64) error {
+	if data := utils.GetDataFromURL(mp.Player().Title(pos)); data != nil {
+		start, _ := strconv.ParseInt(data.Get("start"), 10, 64)
+		end, _ := strconv.ParseInt(data.Get("end"), 10, 64)
+
+		if start > 0 || end > 0 {
+			setTrim(id, mp.Trim{Start: start, End: end})
+		}
+	}
+
+	if _, err := loadVideo(id, audio, pos+1); err != nil {
+		return err
+	}
+
+	if playing {
+		if position > 0 {
+			seekOnReload(id, position)
+		}
+
+		mp.Player().QueueSwitchToTrack(pos + 1)
+	}
+
+	mp.Player().QueueDelete(pos)
+
+	return nil
+}
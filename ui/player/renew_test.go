@@ -0,0 +1,98 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/darkhz/invidtui/client"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+)
+
+func TestURLExpired(t *testing.T) {
+	const expired = "loading failed: https: HTTP error 403 Forbidden"
+
+	tests := []struct {
+		name    string
+		track   mp.FailedTrack
+		renewed time.Time
+		want    bool
+	}{
+		{
+			name:  "expired while playing",
+			track: mp.FailedTrack{Position: 120, Error: expired},
+			want:  true,
+		},
+		{
+			name:  "forbidden",
+			track: mp.FailedTrack{Position: 120, Error: "loading failed: Server returned 403 Forbidden (access denied)"},
+			want:  true,
+		},
+		{
+			name:  "HTTP 403",
+			track: mp.FailedTrack{Position: 120, Error: "HTTP 403"},
+			want:  true,
+		},
+		{
+			name:    "renewed long ago",
+			track:   mp.FailedTrack{Position: 120, Error: expired},
+			renewed: time.Now().Add(-2 * renewInterval),
+			want:    true,
+		},
+		{
+			name:  "unavailable before playing",
+			track: mp.FailedTrack{Position: 0, Error: expired},
+			want:  false,
+		},
+		{
+			name:  "unavailable while playing",
+			track: mp.FailedTrack{Position: 120, Error: "loading failed"},
+			want:  false,
+		},
+		{
+			name:  "not found",
+			track: mp.FailedTrack{Position: 120, Error: "loading failed: https: HTTP error 404 Not Found"},
+			want:  false,
+		},
+		{
+			name:    "failed again after renewal",
+			track:   mp.FailedTrack{Position: 120, Error: expired},
+			renewed: time.Now().Add(-time.Minute),
+			want:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if expired := urlExpired(test.track, test.renewed); expired != test.want {
+				t.Errorf("urlExpired(%+v) = %v, want %v", test.track, expired, test.want)
+			}
+		})
+	}
+}
+
+func TestInstanceSwitched(t *testing.T) {
+	client.SetHost("https://inv.example.com")
+
+	tests := []struct {
+		entry QueueData
+		want  bool
+	}{
+		{entry: QueueData{Filename: "https://inv.example.com/latest_version?id=abc"}, want: false},
+		{entry: QueueData{Filename: "https://other.example.com/latest_version?id=abc"}, want: true},
+		{
+			entry: QueueData{
+				Filename:   "https://manifest.example.com/live/abc.m3u8",
+				SearchData: inv.SearchData{Duration: "Live"},
+			},
+			want: false,
+		},
+		{entry: QueueData{Filename: "/music/local.mp3"}, want: false},
+	}
+
+	for _, test := range tests {
+		if switched := instanceSwitched(test.entry); switched != test.want {
+			t.Errorf("instanceSwitched(%s) = %v, want %v", test.entry.Filename, switched, test.want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package player
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// Scrobbler describes the scrobbling state of the currently playing track.
+type Scrobbler struct {
+	entry     int
+	track     inv.ScrobbleTrack
+	valid     bool
+	scrobbled bool
+
+	mutex sync.Mutex
+}
+
+const (
+	// scrobbleMinDuration is the minimum duration in seconds of a track
+	// for it to be scrobbled.
+	scrobbleMinDuration = 30
+
+	// scrobbleMaxThreshold is the playback position in seconds after which
+	// a track is scrobbled, if it is reached before half of the track is played.
+	scrobbleMaxThreshold = 240
+
+	// scrobbleTimeout is the time after which a scrobble request is cancelled.
+	scrobbleTimeout = 10 * time.Second
+)
+
+var scrobbler Scrobbler
+
+// scrobbleNowPlaying updates the currently playing track on the scrobbling service.
+// Live streams and tracks shorter than the minimum duration are not scrobbled.
+func scrobbleNowPlaying() {
+	auth, ok := scrobbleAuth()
+	if !ok {
+		return
+	}
+
+	entry, track, ok := scrobbleTrack()
+
+	scrobbler.mutex.Lock()
+	scrobbler.entry, scrobbler.track = entry, track
+	scrobbler.valid, scrobbler.scrobbled = ok, false
+	scrobbler.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), scrobbleTimeout)
+		defer cancel()
+
+		if inv.ScrobbleNowPlaying(ctx, auth, track) == nil {
+			submitPendingScrobbles(auth)
+		}
+	}()
+}
+
+// checkScrobble scrobbles the currently playing track, if the provided
+// position has reached half of the track or the maximum threshold.
+func checkScrobble(position int64) {
+	auth, ok := scrobbleAuth()
+	if !ok {
+		return
+	}
+
+	scrobbler.mutex.Lock()
+	defer scrobbler.mutex.Unlock()
+
+	if !scrobbler.valid || scrobbler.scrobbled {
+		return
+	}
+
+	threshold := scrobbler.track.Duration / 2
+	if threshold > scrobbleMaxThreshold {
+		threshold = scrobbleMaxThreshold
+	}
+	if position < threshold {
+		return
+	}
+
+	scrobbler.scrobbled = true
+
+	go submitScrobble(auth, scrobbler.track)
+}
+
+// scrobbleFinished scrobbles the track with the provided playlist entry ID once it has finished
+// playing, if it is the currently scrobbled track and it was not already scrobbled.
+func scrobbleFinished(entry int) {
+	scrobbler.mutex.Lock()
+	current := scrobbler.entry == entry
+	scrobbler.mutex.Unlock()
+
+	if current {
+		checkScrobble(scrobbleMaxThreshold)
+	}
+}
+
+// submitScrobble submits the provided track. If the submission fails,
+// the track is stored to be submitted later.
+func submitScrobble(auth inv.ScrobbleAuth, track inv.ScrobbleTrack) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrobbleTimeout)
+	defer cancel()
+
+	if err := inv.Scrobble(ctx, auth, track); err != nil {
+		player.mutex.Lock()
+		cmd.Settings.PendingScrobbles = append(cmd.Settings.PendingScrobbles, cmd.ScrobbleSettings(track))
+		player.mutex.Unlock()
+
+		return
+	}
+
+	submitPendingScrobbles(auth)
+}
+
+// submitPendingScrobbles submits the tracks which could not be submitted earlier.
+func submitPendingScrobbles(auth inv.ScrobbleAuth) {
+	var failed []cmd.ScrobbleSettings
+
+	player.mutex.Lock()
+	pending := cmd.Settings.PendingScrobbles
+	cmd.Settings.PendingScrobbles = nil
+	player.mutex.Unlock()
+
+	for i, track := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), scrobbleTimeout)
+		err := inv.Scrobble(ctx, auth, inv.ScrobbleTrack(track))
+		cancel()
+
+		if err != nil {
+			failed = pending[i:]
+			break
+		}
+	}
+
+	if failed == nil {
+		return
+	}
+
+	player.mutex.Lock()
+	cmd.Settings.PendingScrobbles = append(failed, cmd.Settings.PendingScrobbles...)
+	player.mutex.Unlock()
+}
+
+// scrobbleTrack returns the playlist entry ID and the track information of the currently
+// playing track, and whether the track can be scrobbled.
+func scrobbleTrack() (int, inv.ScrobbleTrack, bool) {
+	pos := mp.Player().QueuePosition()
+	if pos < 0 {
+		return -1, inv.ScrobbleTrack{}, false
+	}
+
+	list := player.queue.getQueueData()
+	if pos >= len(list) {
+		return -1, inv.ScrobbleTrack{}, false
+	}
+
+	data := utils.GetDataFromURL(list[pos].Filename)
+	if data == nil || data.Get("length") == "Live" {
+		return -1, inv.ScrobbleTrack{}, false
+	}
+
+	track := inv.ScrobbleTrack{
+		Title:     data.Get("title"),
+		Artist:    data.Get("author"),
+		VideoID:   data.Get("id"),
+		Duration:  parseDuration(data.Get("length")),
+		Timestamp: time.Now().Unix(),
+	}
+
+	return list[pos].ID, track, track.Title != "" && track.Artist != "" && track.Duration >= scrobbleMinDuration
+}
+
+// scrobbleAuth returns the scrobbling credentials, and whether scrobbling is enabled.
+func scrobbleAuth() (inv.ScrobbleAuth, bool) {
+	service := cmd.GetOptionValue("scrobbler")
+	if service == "" {
+		return inv.ScrobbleAuth{}, false
+	}
+
+	return inv.ScrobbleAuth{
+		Service:   service,
+		Token:     cmd.GetOptionValue("scrobble-token"),
+		APIKey:    cmd.GetOptionValue("lastfm-api-key"),
+		APISecret: cmd.GetOptionValue("lastfm-api-secret"),
+	}, true
+}
@@ -0,0 +1,117 @@
+package player
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/darkhz/invidtui/cmd"
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// SponsorBlock describes the SponsorBlock segments of the currently playing video.
+type SponsorBlock struct {
+	id       string
+	position int64
+	segments []inv.SponsorSegment
+
+	suppressed map[string]struct{}
+
+	cancel context.CancelFunc
+	mutex  sync.Mutex
+}
+
+// seekThreshold is the difference in seconds between two consecutive
+// positions, after which the position change is considered to be a seek.
+const seekThreshold = 2
+
+var sponsorBlock SponsorBlock
+
+// skipSegments checks whether the provided position of the currently playing video
+// is within a SponsorBlock segment, and seeks past the segment if it is.
+// If the position was manually seeked into a segment, the segment is not skipped.
+func skipSegments(id string, position int64) {
+	categories := sponsorBlockCategories()
+	if categories == nil || id == "" {
+		return
+	}
+
+	sponsorBlock.mutex.Lock()
+	defer sponsorBlock.mutex.Unlock()
+
+	if id != sponsorBlock.id {
+		fetchSegments(id, categories)
+		return
+	}
+
+	seeked := position-sponsorBlock.position > seekThreshold ||
+		sponsorBlock.position-position > seekThreshold
+	sponsorBlock.position = position
+
+	for _, segment := range sponsorBlock.segments {
+		start, end := int64(segment.Segment[0]), int64(math.Ceil(segment.Segment[1]))
+		if position < start || position >= end {
+			continue
+		}
+
+		if _, ok := sponsorBlock.suppressed[segment.UUID]; ok {
+			continue
+		}
+
+		if seeked && position > start {
+			sponsorBlock.suppressed[segment.UUID] = struct{}{}
+			continue
+		}
+
+		mp.Player().SeekToPosition(end)
+		sponsorBlock.position = end
+
+		app.ShowInfo("Skipped "+strings.ReplaceAll(segment.Category, "_", " "), false)
+
+		return
+	}
+}
+
+// fetchSegments resets the SponsorBlock segments and retrieves
+// the segments for the provided video in the background.
+func fetchSegments(id string, categories []string) {
+	if sponsorBlock.cancel != nil {
+		sponsorBlock.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sponsorBlock.id, sponsorBlock.position = id, 0
+	sponsorBlock.segments, sponsorBlock.cancel = nil, cancel
+	sponsorBlock.suppressed = make(map[string]struct{})
+
+	go func() {
+		segments, err := inv.SponsorSegments(ctx, id, categories)
+		if err != nil {
+			return
+		}
+
+		sponsorBlock.mutex.Lock()
+		defer sponsorBlock.mutex.Unlock()
+
+		if sponsorBlock.id == id {
+			sponsorBlock.segments = segments
+		}
+	}()
+}
+
+// sponsorBlockCategories returns the SponsorBlock segment categories to skip.
+func sponsorBlockCategories() []string {
+	var categories []string
+
+	for _, category := range strings.Split(cmd.GetOptionValue("sponsorblock-categories"), ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories
+}
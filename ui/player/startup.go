@@ -0,0 +1,163 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// queueFile is the name of the file within the config directory,
+// in which the queue is saved on exit to be resumed on startup.
+const queueFile = "queue.m3u8"
+
+// startupAction performs the action from the 'startup-action' option, once the
+// player states and history are loaded. If URLs were provided to be played via
+// the command-line, no action is performed.
+func startupAction() {
+	if len(cmd.GetPlayQueries()) > 0 {
+		return
+	}
+
+	switch action := cmd.GetOptionValue("startup-action"); action {
+	case "resume":
+		resumeQueue()
+
+	case "play-audio", "play-video":
+		uri := cmd.GetOptionValue("startup-url")
+
+		info, err := urlInfo(uri)
+		if err != nil {
+			app.ShowError(fmt.Errorf("Player: Cannot play %s: %w", uri, err))
+			return
+		}
+
+		Play(action == "play-audio", false, info)
+	}
+}
+
+// resumeQueue loads the queue saved in the previous session, and switches to the
+// track which was playing. Expired live stream URLs are renewed while loading.
+// Unless the 'startup-autoplay' option is enabled, the playback is paused.
+func resumeQueue() {
+	file, err := cmd.GetPath(queueFile, struct{}{})
+	if err != nil {
+		return
+	}
+
+	app.ShowInfo("Player: Resuming queue", true)
+
+	player.mutex.Lock()
+	pos := cmd.Settings.QueuePosition
+	player.mutex.Unlock()
+
+	autoplay := cmd.IsOptionEnabled("startup-autoplay")
+	if !autoplay {
+		pauseAtTrack(pos)
+	}
+
+	if err := mp.Player().LoadPlaylist(file, true, checkLiveURL); err != nil {
+		pauseAtTrack(-1)
+		app.ShowError(fmt.Errorf("Player: Unable to resume queue"))
+
+		return
+	}
+
+	if pos < 0 || pos >= mp.Player().QueueCount() {
+		if !autoplay {
+			pauseAtTrack(0)
+		}
+
+		pos = 0
+	}
+
+	if pos > 0 {
+		mp.Player().QueueSwitchToTrack(pos)
+	}
+
+	if !autoplay {
+		app.ShowInfo("Player: Resumed queue (paused)", false)
+		return
+	}
+
+	app.ShowInfo("Player: Resumed queue", false)
+}
+
+// saveQueue saves the queue and the position of the playing track, so that they can be
+// resumed on the next startup. The queue is only saved if the 'startup-action' option is
+// set to resume, and the saved queue is removed if the queue is empty.
+func saveQueue() {
+	if cmd.GetOptionValue("startup-action") != "resume" || mp.Player().Exited() {
+		return
+	}
+
+	file, err := cmd.GetPath(queueFile)
+	if err != nil {
+		utils.LogErrorf("%s", err.Error())
+		return
+	}
+
+	list := player.queue.getQueueData()
+	if len(list) == 0 {
+		os.Remove(file)
+		return
+	}
+
+	var pos int
+	for i, data := range list {
+		if data.Playing {
+			pos = i
+			break
+		}
+	}
+
+	entries, err := player.queue.generatePlaylist(file, list, false, false)
+	if err != nil {
+		utils.LogErrorf("%s", err.Error())
+		return
+	}
+
+	if err := utils.WriteFileAtomic(file, []byte(strings.TrimSpace(entries)+"\n"), 0664); err != nil {
+		utils.LogErrorf("Player: Unable to save queue: %v", err)
+		return
+	}
+
+	player.mutex.Lock()
+	cmd.Settings.QueuePosition = pos
+	player.mutex.Unlock()
+}
+
+// pauseAtTrack sets the queue position of the track which should be paused once it
+// is loaded. This is required since the playback is started whenever a track starts.
+// If the position is negative, no track is paused.
+func pauseAtTrack(pos int) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	player.pauseAt = pos
+}
+
+// pauseLoadedTrack pauses the playback if the loaded track is the one set via pauseAtTrack,
+// and updates the player so that the paused state is shown.
+func pauseLoadedTrack() {
+	pos := mp.Player().QueuePosition()
+
+	player.mutex.Lock()
+	if player.pauseAt < 0 || player.pauseAt != pos {
+		player.mutex.Unlock()
+		return
+	}
+
+	player.pauseAt = -1
+	player.mutex.Unlock()
+
+	if !mp.Player().Paused() {
+		mp.Player().TogglePaused()
+	}
+
+	sendPlayerEvents()
+}
@@ -1,23 +1,88 @@
 package player
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/darkhz/invidtui/cmd"
 	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+const (
+	// resumeMinPosition is the minimum position in seconds
+	// from which a video can be resumed.
+	resumeMinPosition = 10
+
+	// resumeCompletion is the percentage of a video after which
+	// it is considered to be watched, and is not resumed.
+	resumeCompletion = 95
 )
 
 // loadState loads the saved player states.
 func loadState() {
+	hwdec := cmd.GetOptionValue("hwdec")
+	defer func() {
+		if hwdec != "" {
+			mp.Player().SetHWDec(hwdec)
+		}
+	}()
+
 	states := cmd.Settings.PlayerStates
 	if len(states) == 0 {
 		return
 	}
 
 	for _, s := range states {
+		if s == "autoplay" {
+			autoplayStatus(true)
+			continue
+		}
+
+		if strings.HasPrefix(s, "audio-device ") {
+			restoreAudioDevice(strings.TrimPrefix(s, "audio-device "))
+			continue
+		}
+
+		if strings.HasPrefix(s, "aspect ") {
+			mp.Player().SetAspect(strings.TrimPrefix(s, "aspect "))
+			continue
+		}
+
+		if strings.HasPrefix(s, "zoom ") {
+			if zoom, err := strconv.ParseFloat(strings.TrimPrefix(s, "zoom "), 64); err == nil {
+				mp.Player().SetZoom(zoom)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(s, "hwdec") {
+			hwdec = strings.TrimPrefix(s, "hwdec ")
+			continue
+		}
+
+		if strings.HasPrefix(s, "premute") {
+			if premute, err := strconv.Atoi(strings.TrimPrefix(s, "premute ")); err == nil {
+				mp.Player().PreMuteVolume(premute)
+			}
+
+			continue
+		}
+
 		if strings.Contains(s, "volume") {
-			vol := strings.Split(s, " ")[1]
-			mp.Player().Set("volume", vol)
+			if vol := strings.Split(s, " "); len(vol) == 2 {
+				mp.Player().Set("volume", vol[1])
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(s, "loop-count ") {
+			loadLoopCount(s)
+			continue
 		}
 
 		if strings.Contains(s, "loop") {
@@ -28,3 +93,97 @@ func loadState() {
 		mp.Player().Call("cycle", s)
 	}
 }
+
+// configuredHWDec returns the hardware decoding mode set in the 'hwdec' option.
+// If it is not set, MPV's default mode is returned.
+func configuredHWDec() string {
+	if hwdec := cmd.GetOptionValue("hwdec"); hwdec != "" {
+		return hwdec
+	}
+
+	return "no"
+}
+
+// savePosition stores the playback position of the provided video, so that
+// it can be resumed later. If the video has been watched to near-completion,
+// its stored position is cleared. The oldest positions are removed according
+// to the 'history-limit' option.
+func savePosition(id string, position, duration int64) {
+	if id == "" || duration <= 0 || cmd.GetOptionValue("resume-mode") == "off" {
+		return
+	}
+
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if cmd.Settings.PlaybackPositions == nil {
+		cmd.Settings.PlaybackPositions = make(map[string]cmd.PlaybackPositionSettings)
+	}
+
+	if storePosition(cmd.Settings.PlaybackPositions, id, position, duration, time.Now().Unix()) {
+		cmd.LimitPlaybackPositions(cmd.Settings.PlaybackPositions)
+	}
+}
+
+// storePosition stores the provided position of the video with the provided timestamp,
+// and returns whether it was stored. If the video has been watched to near-completion,
+// its position is removed instead, and positions near the start are not stored.
+func storePosition(positions map[string]cmd.PlaybackPositionSettings, id string, position, duration, timestamp int64) bool {
+	if position*100 >= duration*resumeCompletion {
+		delete(positions, id)
+		return false
+	}
+
+	if position < resumeMinPosition {
+		return false
+	}
+
+	positions[id] = cmd.PlaybackPositionSettings{Position: position, Timestamp: timestamp}
+
+	return true
+}
+
+// savedPosition returns the stored playback position of the provided video.
+func savedPosition(id string) (int64, bool) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	saved, ok := cmd.Settings.PlaybackPositions[id]
+
+	return saved.Position, ok
+}
+
+// resumePosition seeks the currently playing video to its stored playback position.
+// According to the 'resume-mode' option, this is done either automatically, or after
+// the user confirms it.
+func resumePosition() {
+	mode := cmd.GetOptionValue("resume-mode")
+	if mode == "off" {
+		return
+	}
+
+	id := currentVideoID()
+
+	position, ok := savedPosition(id)
+	if !ok {
+		return
+	}
+
+	if mode == "auto" {
+		mp.Player().SeekToPosition(position)
+		app.ShowInfo("Resumed from "+utils.FormatDuration(position), false)
+
+		return
+	}
+
+	app.UI.QueueUpdateDraw(func() {
+		app.UI.Status.SetInput("Resume from "+utils.FormatDuration(position)+"? (y/n)", 1, true, func(reply string) {
+			if reply != "y" || currentVideoID() != id {
+				return
+			}
+
+			mp.Player().SeekToPosition(position)
+			sendPlayerEvents()
+		}, nil)
+	})
+}
@@ -0,0 +1,58 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/darkhz/invidtui/cmd"
+)
+
+func TestStorePosition(t *testing.T) {
+	positions := make(map[string]cmd.PlaybackPositionSettings)
+
+	steps := []struct {
+		id                 string
+		position, duration int64
+		stored             bool
+		want               map[string]int64
+	}{
+		{id: "a", position: 5, duration: 600, stored: false, want: map[string]int64{}},
+		{id: "a", position: 120, duration: 600, stored: true, want: map[string]int64{"a": 120}},
+		{id: "b", position: 30, duration: 300, stored: true, want: map[string]int64{"a": 120, "b": 30}},
+		{id: "a", position: 240, duration: 600, stored: true, want: map[string]int64{"a": 240, "b": 30}},
+		{id: "b", position: 290, duration: 300, stored: false, want: map[string]int64{"a": 240}},
+		{id: "a", position: 5, duration: 600, stored: false, want: map[string]int64{"a": 240}},
+		{id: "a", position: 600, duration: 600, stored: false, want: map[string]int64{}},
+	}
+
+	for i, step := range steps {
+		stored := storePosition(positions, step.id, step.position, step.duration, int64(i))
+		if stored != step.stored {
+			t.Errorf("step %d: stored = %v, want %v", i, stored, step.stored)
+		}
+
+		if len(positions) != len(step.want) {
+			t.Fatalf("step %d: positions = %v, want %v", i, positions, step.want)
+		}
+		for id, position := range step.want {
+			if saved := positions[id]; saved.Position != position {
+				t.Errorf("step %d: position of %s = %d, want %d", i, id, saved.Position, position)
+			}
+		}
+	}
+}
+
+func TestSavedPosition(t *testing.T) {
+	saved := cmd.Settings.PlaybackPositions
+	defer func() { cmd.Settings.PlaybackPositions = saved }()
+
+	cmd.Settings.PlaybackPositions = map[string]cmd.PlaybackPositionSettings{
+		"a": {Position: 120, Timestamp: 1},
+	}
+
+	if position, ok := savedPosition("a"); !ok || position != 120 {
+		t.Errorf("savedPosition(a) = %d, %v, want 120, true", position, ok)
+	}
+	if position, ok := savedPosition("b"); ok {
+		t.Errorf("savedPosition(b) = %d, %v, want no position", position, ok)
+	}
+}
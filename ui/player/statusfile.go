@@ -0,0 +1,176 @@
+package player
+
+import (
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/darkhz/invidtui/cmd"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// StatusFile stores the last status written to the status file.
+type StatusFile struct {
+	last string
+
+	mutex sync.Mutex
+}
+
+// playerStatus describes the status of the player written in the JSON format.
+type playerStatus struct {
+	State         string   `json:"state"`
+	Title         string   `json:"title"`
+	Author        string   `json:"author"`
+	VideoID       string   `json:"videoId"`
+	Position      int64    `json:"position"`
+	Duration      int64    `json:"duration"`
+	QueuePosition int      `json:"queuePosition"`
+	QueueCount    int      `json:"queueCount"`
+	Volume        int      `json:"volume"`
+	States        []string `json:"states"`
+}
+
+// waybarStatus describes the status of the player written in the Waybar custom module format.
+type waybarStatus struct {
+	Text       string `json:"text"`
+	Tooltip    string `json:"tooltip"`
+	Class      string `json:"class"`
+	Percentage int64  `json:"percentage"`
+}
+
+var statusFile StatusFile
+
+// writeStatus writes the status of the player to the configured status file
+// in the configured format. If the status has not changed, it is not written.
+func writeStatus(states []string) {
+	path := cmd.GetOptionValue("status-file")
+	if path == "" {
+		return
+	}
+
+	text, err := formatStatus(cmd.GetOptionValue("status-format"), playingState(states))
+	if err != nil {
+		return
+	}
+
+	statusFile.mutex.Lock()
+	defer statusFile.mutex.Unlock()
+
+	if text == statusFile.last {
+		return
+	}
+
+	if writeStatusFile(path, text+"\n") == nil {
+		statusFile.last = text
+	}
+}
+
+// clearStatus writes an empty status to the status file, once the player has stopped.
+func clearStatus() {
+	path := cmd.GetOptionValue("status-file")
+	if path == "" {
+		return
+	}
+
+	statusFile.mutex.Lock()
+	defer statusFile.mutex.Unlock()
+
+	statusFile.last = ""
+	writeStatusFile(path, "\n")
+}
+
+// playingState returns the status of the currently playing track.
+func playingState(states []string) playerStatus {
+	status := playerStatus{
+		State:         "playing",
+		Position:      mp.Player().Position(),
+		Duration:      mp.Player().Duration(),
+		QueuePosition: mp.Player().QueuePosition(),
+		QueueCount:    mp.Player().QueueCount(),
+		Volume:        mp.Player().Volume(),
+		States:        states,
+	}
+
+	switch {
+	case mp.Player().Paused() && mp.Player().Finished():
+		status.State = "stopped"
+
+	case mp.Player().Paused():
+		status.State = "paused"
+
+	case mp.Player().Buffering():
+		status.State = "buffering"
+	}
+
+	title := mp.Player().Title(status.QueuePosition)
+	status.Title = title
+
+	if data := utils.GetDataFromURL(title); data != nil {
+		status.Title, status.Author = data.Get("title"), data.Get("author")
+		status.VideoID = data.Get("id")
+
+		if length := data.Get("length"); length != "" && length != "Live" {
+			status.Duration = parseDuration(length)
+		}
+	}
+
+	return status
+}
+
+// formatStatus returns the provided status in the provided format.
+func formatStatus(format string, status playerStatus) (string, error) {
+	glyph := map[string]string{
+		"playing":   ">",
+		"paused":    "||",
+		"stopped":   "[]",
+		"buffering": "B",
+	}[status.State]
+
+	progress := utils.FormatDuration(status.Position) + "/" + utils.FormatDuration(status.Duration)
+
+	switch format {
+	case "json":
+		return utils.JSON().MarshalToString(status)
+
+	case "waybar":
+		var percentage int64
+		if status.Duration > 0 {
+			percentage = status.Position * 100 / status.Duration
+		}
+
+		tooltip := status.Title
+		if status.Author != "" {
+			tooltip += "\n" + status.Author
+		}
+
+		return utils.JSON().MarshalToString(waybarStatus{
+			Text:       glyph + " " + status.Title,
+			Tooltip:    tooltip + "\n" + progress,
+			Class:      status.State,
+			Percentage: percentage,
+		})
+	}
+
+	return glyph + " " + status.Title + " " + progress, nil
+}
+
+// writeStatusFile writes the text to the status file. If the file is a named pipe,
+// it is written to only if it is being read from, so that the player is not blocked.
+// Otherwise, the file is replaced atomically, so that it is never read partially written.
+func writeStatusFile(path, text string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		return utils.WriteFileAtomic(path, []byte(text), 0644)
+	}
+
+	fd, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	_, err = fd.WriteString(text)
+
+	return err
+}
@@ -0,0 +1,169 @@
+package player
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// StatusQuery stores the listener of the status query socket.
+//
+// If the 'status-socket' option is set, the player listens on the 'status.sock'
+// unix socket within the config directory, so that scripts can query the status
+// of the running instance. The protocol is line-based:
+//
+//   - The client connects and sends a single query line.
+//   - The player replies with a single line, and closes the connection.
+//
+// The supported queries are:
+//
+//	status       Reply with the tab-separated state, position/duration,
+//	             volume and title, for example "playing\t01:02/03:04\t80\tTitle".
+//	status json  Reply with the status in the same JSON format as the status file.
+//
+// Any other query is answered with an "error: <message>" line.
+// Each connection is served separately, so multiple queries can be made at once.
+type StatusQuery struct {
+	listener net.Listener
+
+	mutex sync.Mutex
+}
+
+// statusQueryTimeout is the duration within which a client must send its query.
+const statusQueryTimeout = 5 * time.Second
+
+var statusQuery StatusQuery
+
+// startStatusQuery starts listening for status queries, if the 'status-socket' option is set.
+func startStatusQuery() {
+	if !cmd.IsOptionEnabled("status-socket") {
+		return
+	}
+
+	path := cmd.StatusSocketPath()
+
+	listener, err := listenStatusSocket(path)
+	if err != nil {
+		utils.LogErrorf("Player: Unable to listen for status queries on %s: %v", path, err)
+		return
+	}
+
+	statusQuery.mutex.Lock()
+	statusQuery.listener = listener
+	statusQuery.mutex.Unlock()
+
+	go acceptStatusQueries(listener)
+}
+
+// stopStatusQuery stops listening for status queries, and removes the socket.
+func stopStatusQuery() {
+	statusQuery.mutex.Lock()
+	defer statusQuery.mutex.Unlock()
+
+	if statusQuery.listener == nil {
+		return
+	}
+
+	statusQuery.listener.Close()
+	statusQuery.listener = nil
+}
+
+// listenStatusSocket listens on the socket at the provided path. If a socket is
+// left over from an instance which exited abruptly, it is removed before listening,
+// but a socket which is still being listened on by another instance is not taken over.
+func listenStatusSocket(path string) (net.Listener, error) {
+	listener, err := net.Listen("unix", path)
+	if err == nil {
+		return listener, nil
+	}
+
+	if _, statErr := os.Stat(path); statErr != nil {
+		return nil, err
+	}
+
+	if conn, dialErr := net.DialTimeout("unix", path, time.Second); dialErr == nil {
+		conn.Close()
+		return nil, errors.New("socket is in use by another instance")
+	}
+
+	if err := os.Remove(path); err != nil {
+		return nil, err
+	}
+
+	return net.Listen("unix", path)
+}
+
+// acceptStatusQueries serves each connection to the listener, until the listener is closed.
+func acceptStatusQueries(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				utils.LogErrorf("Player: Status query socket closed: %v", err)
+			}
+
+			return
+		}
+
+		go serveStatusQuery(conn)
+	}
+}
+
+// serveStatusQuery reads a query from the connection, and replies to it.
+func serveStatusQuery(conn net.Conn) {
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(statusQueryTimeout))
+
+	query, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && query == "" {
+		return
+	}
+
+	conn.Write([]byte(answerStatusQuery(strings.TrimSpace(query)) + "\n"))
+}
+
+// answerStatusQuery returns the reply to the provided query.
+func answerStatusQuery(query string) string {
+	var format string
+
+	switch strings.Join(strings.Fields(query), " ") {
+	case "status":
+
+	case "status json":
+		format = "json"
+
+	default:
+		return "error: unknown query"
+	}
+
+	player.mutex.Lock()
+	states := cmd.Settings.PlayerStates
+	player.mutex.Unlock()
+
+	status := playingState(states)
+
+	if format == "json" {
+		text, err := formatStatus(format, status)
+		if err != nil {
+			return "error: " + err.Error()
+		}
+
+		return text
+	}
+
+	return strings.Join([]string{
+		status.State,
+		utils.FormatDuration(status.Position) + "/" + utils.FormatDuration(status.Duration),
+		strconv.Itoa(status.Volume),
+		status.Title,
+	}, "\t")
+}
@@ -0,0 +1,99 @@
+package player
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/darkhz/invidtui/cmd"
+	"github.com/darkhz/invidtui/ui/app"
+)
+
+// trackHookTimeout is the time after which the track change command is stopped.
+const trackHookTimeout = 30 * time.Second
+
+// setupTrackHook runs the track change command whenever a track starts playing,
+// if the command is configured.
+func setupTrackHook() {
+	if cmd.GetOptionValue("on-track-change") == "" {
+		return
+	}
+
+	Subscribe(TrackStarted, func(data EventData) {
+		if err := runTrackHook(data.Track); err != nil {
+			app.ShowError(err)
+		}
+	})
+}
+
+// testTrackHook runs the track change command for the currently playing track.
+func testTrackHook() {
+	if cmd.GetOptionValue("on-track-change") == "" {
+		app.ShowError(fmt.Errorf("Player: No track change command is set"))
+		return
+	}
+
+	for _, track := range player.queue.getQueueData() {
+		if !track.Playing {
+			continue
+		}
+
+		app.ShowInfo("Player: Running track change command", true)
+
+		if err := runTrackHook(track); err != nil {
+			app.ShowError(err)
+			return
+		}
+
+		app.ShowInfo("Player: Track change command finished", false)
+
+		return
+	}
+
+	app.ShowError(fmt.Errorf("Player: No track is playing"))
+}
+
+// runTrackHook runs the track change command with the information of the provided track.
+// The information is passed via the INVIDTUI_TITLE, INVIDTUI_AUTHOR, INVIDTUI_ID and
+// INVIDTUI_DURATION environment variables, and as arguments in the same order.
+func runTrackHook(track QueueData) error {
+	command := cmd.GetOptionValue("on-track-change")
+	if command == "" {
+		return nil
+	}
+
+	duration := strconv.FormatInt(parseDuration(track.Duration), 10)
+	args := []string{track.Title, track.Author, track.VideoID, duration}
+
+	ctx, cancel := context.WithTimeout(context.Background(), trackHookTimeout)
+	defer cancel()
+
+	var hook *exec.Cmd
+	if runtime.GOOS == "windows" {
+		hook = exec.CommandContext(ctx, "cmd", append([]string{"/C", command}, args...)...)
+	} else {
+		hook = exec.CommandContext(ctx, "sh", append([]string{"-c", command, "invidtui"}, args...)...)
+	}
+
+	hook.Env = append(os.Environ(),
+		"INVIDTUI_TITLE="+track.Title,
+		"INVIDTUI_AUTHOR="+track.Author,
+		"INVIDTUI_ID="+track.VideoID,
+		"INVIDTUI_DURATION="+duration,
+	)
+
+	if output, err := hook.CombinedOutput(); err != nil {
+		if text := strings.TrimSpace(string(output)); text != "" {
+			err = fmt.Errorf("%w: %s", err, text)
+		}
+
+		return fmt.Errorf("Player: Track change command failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package player
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	inv "github.com/darkhz/invidtui/invidious"
+	mp "github.com/darkhz/invidtui/mediaplayer"
+	"github.com/darkhz/invidtui/ui/app"
+	"github.com/darkhz/invidtui/utils"
+)
+
+// queueTrimmed displays an inputbox to enter the positions at which the playback
+// of the currently selected video should start and end, and then queues the video
+// as audio or video.
+func queueTrimmed() {
+	info, err := app.FocusedTableReference()
+	if err != nil {
+		return
+	}
+
+	if info.Type != "video" {
+		app.ShowError(fmt.Errorf("Player: Only videos can be trimmed"))
+		return
+	}
+
+	dofunc := func(text string) {
+		trim, err := parseTrim(text)
+		if err == nil {
+			err = checkTrim(trim, info.LengthSeconds)
+		}
+		if err != nil {
+			app.ShowError(err)
+			return
+		}
+
+		app.UI.Status.SetInput("Queue as (a)udio or (v)ideo:", 1, true, func(reply string) {
+			if reply != "a" && reply != "v" {
+				return
+			}
+
+			setTrim(info.VideoID, trim)
+			Play(reply == "a", false, info)
+		}, nil)
+	}
+
+	app.UI.Status.SetInput("Trim (start-end, e.g. 1:30-5:00):", 0, true, dofunc, nil)
+}
+
+// setTrim stores the trim for the provided video ID, which is applied
+// when the video is next loaded into the media player.
+func setTrim(id string, trim mp.Trim) {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	if player.trims == nil {
+		player.trims = make(map[string]mp.Trim)
+	}
+
+	player.trims[id] = trim
+}
+
+// takeTrim returns and removes the stored trim for the provided video ID.
+func takeTrim(id string) mp.Trim {
+	player.mutex.Lock()
+	defer player.mutex.Unlock()
+
+	trim := player.trims[id]
+	delete(player.trims, id)
+
+	return trim
+}
+
+// videoTrim validates the provided trim against the loaded video.
+func videoTrim(video inv.VideoData, trim mp.Trim) error {
+	if trim == (mp.Trim{}) {
+		return nil
+	}
+
+	if video.LiveNow {
+		return fmt.Errorf("Player: Live streams cannot be trimmed")
+	}
+
+	return checkTrim(trim, video.LengthSeconds)
+}
+
+// checkTrim checks whether the start of the trim is before its end, and whether
+// both are within the provided duration. If the duration is unknown, it is not checked.
+func checkTrim(trim mp.Trim, duration int64) error {
+	if trim.End > 0 && trim.Start >= trim.End {
+		return fmt.Errorf("Player: Trim start must be before its end")
+	}
+
+	if duration > 0 && (trim.Start >= duration || trim.End > duration) {
+		return fmt.Errorf("Player: Trim must be within the video duration (%s)", utils.FormatDuration(duration))
+	}
+
+	return nil
+}
+
+// parseTrim parses a trim in the 'start-end' format, where either of the positions
+// can be omitted, and each position is in seconds or in the '[hh:]mm:ss' format.
+func parseTrim(text string) (mp.Trim, error) {
+	var trim mp.Trim
+
+	var end string
+
+	start := text
+	if index := strings.Index(text, "-"); index >= 0 {
+		start, end = text[:index], text[index+1:]
+	}
+	if strings.TrimSpace(start) == "" && strings.TrimSpace(end) == "" {
+		return mp.Trim{}, fmt.Errorf("Player: No trim positions entered")
+	}
+
+	for _, position := range []struct {
+		text  string
+		value *int64
+	}{
+		{start, &trim.Start},
+		{end, &trim.End},
+	} {
+		text := strings.TrimSpace(position.text)
+		if text == "" {
+			continue
+		}
+
+		seconds, err := parseTimestamp(text)
+		if err != nil {
+			return mp.Trim{}, fmt.Errorf("Player: Invalid trim position %s", text)
+		}
+
+		*position.value = seconds
+	}
+
+	return trim, nil
+}
+
+// parseTimestamp parses a position in seconds or in the '[hh:]mm:ss' format.
+func parseTimestamp(text string) (int64, error) {
+	var seconds int64
+
+	parts := strings.Split(text, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp")
+	}
+
+	for i, part := range parts {
+		value, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || value < 0 || i > 0 && value >= 60 {
+			return 0, fmt.Errorf("invalid timestamp")
+		}
+
+		seconds = seconds*60 + value
+	}
+
+	return seconds, nil
+}
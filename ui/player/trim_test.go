@@ -0,0 +1,62 @@
+package player
+
+import (
+	"testing"
+
+	mp "github.com/darkhz/invidtui/mediaplayer"
+)
+
+func TestParseTrim(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    mp.Trim
+		wantErr bool
+	}{
+		{text: "30-90", want: mp.Trim{Start: 30, End: 90}},
+		{text: " 1:00 - 2:30 ", want: mp.Trim{Start: 60, End: 150}},
+		{text: "1:02:03-", want: mp.Trim{Start: 3723}},
+		{text: "-45", want: mp.Trim{End: 45}},
+		{text: "15", want: mp.Trim{Start: 15}},
+		{text: "", wantErr: true},
+		{text: " - ", wantErr: true},
+		{text: "1:60-2:00", wantErr: true},
+		{text: "1:2:3:4", wantErr: true},
+		{text: "a-b", wantErr: true},
+		{text: "10-20-30", wantErr: true},
+	}
+
+	for _, test := range tests {
+		trim, err := parseTrim(test.text)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseTrim(%q) error = %v, want error %v", test.text, err, test.wantErr)
+			continue
+		}
+		if trim != test.want {
+			t.Errorf("parseTrim(%q) = %+v, want %+v", test.text, trim, test.want)
+		}
+	}
+}
+
+func TestCheckTrim(t *testing.T) {
+	tests := []struct {
+		trim     mp.Trim
+		duration int64
+		wantErr  bool
+	}{
+		{trim: mp.Trim{Start: 10, End: 20}, duration: 60},
+		{trim: mp.Trim{Start: 10}, duration: 60},
+		{trim: mp.Trim{End: 60}, duration: 60},
+		{trim: mp.Trim{Start: 10, End: 20}, duration: 0},
+		{trim: mp.Trim{Start: 500}, duration: 0},
+		{trim: mp.Trim{Start: 20, End: 20}, duration: 60, wantErr: true},
+		{trim: mp.Trim{Start: 30, End: 20}, duration: 60, wantErr: true},
+		{trim: mp.Trim{Start: 60}, duration: 60, wantErr: true},
+		{trim: mp.Trim{End: 61}, duration: 60, wantErr: true},
+	}
+
+	for _, test := range tests {
+		if err := checkTrim(test.trim, test.duration); (err != nil) != test.wantErr {
+			t.Errorf("checkTrim(%+v, %d) error = %v, want error %v", test.trim, test.duration, err, test.wantErr)
+		}
+	}
+}
@@ -1,25 +1,34 @@
 package popup
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/darkhz/invidtui/client"
+	"github.com/darkhz/invidtui/cmd"
 	"github.com/darkhz/invidtui/ui/app"
 	"github.com/darkhz/invidtui/utils"
 	"github.com/darkhz/tview"
 	"github.com/gdamore/tcell/v2"
 )
 
-// ShowInstancesList shows a popup with a list of instances.
+// ShowInstancesList shows a popup with a list of instances. If instances are set
+// in the 'instances' option, they are listed, otherwise the public instances are
+// listed. Each instance is probed, and its latency or status is shown beside it.
 func ShowInstancesList() {
 	var instancesModal *app.Modal
 
 	app.ShowInfo("Loading instance list", true)
 
-	instances, err := client.GetInstances()
-	if err != nil {
-		app.ShowError(err)
-		return
+	instances := cmd.Instances()
+	if instances == nil {
+		var err error
+
+		instances, err = client.GetInstances()
+		if err != nil {
+			app.ShowError(err)
+			return
+		}
 	}
 
 	instancesView := tview.NewTable()
@@ -59,17 +68,67 @@ func ShowInstancesList() {
 			}
 
 			instancesView.SetCell(row, 0, tview.NewTableCell(instance).
+				SetExpansion(1).
 				SetReference(instances[row]).
 				SetTextColor(tcell.ColorBlue).
 				SetSelectedStyle(app.UI.SelectedStyle),
 			)
+
+			instancesView.SetCell(row, 1, tview.NewTableCell("[gray]probing").
+				SetAlign(tview.AlignRight).
+				SetSelectable(false),
+			)
 		}
 
-		instancesModal = app.NewModal("instances", "Available instances", instancesView, len(instances)+4, width+4)
+		instancesModal = app.NewModal("instances", "Available instances", instancesView, len(instances)+4, width+20)
 		instancesModal.Show(false)
 	})
 
 	app.ShowInfo("Instances loaded", false)
+
+	for row, instance := range instances {
+		go probeInstance(row, instance, instancesView)
+	}
+}
+
+// probeInstance probes the instance and shows its latency or status
+// in the provided row of the instance list.
+func probeInstance(row int, instance string, table *tview.Table) {
+	status := "[red]unavailable"
+	if _, latency, err := client.ProbeInstance(instance); err == nil {
+		status = fmt.Sprintf("[green]%dms", latency.Milliseconds())
+	}
+
+	app.UI.QueueUpdateDraw(func() {
+		if cell := table.GetCell(row, 1); cell != nil {
+			cell.SetText(status)
+		}
+	})
+}
+
+// UpdateInstanceStatus displays the current instance along with its health,
+// as observed from the latest request sent to it.
+func UpdateInstanceStatus() {
+	status := utils.GetHostname(client.Instance())
+	if status == "" {
+		app.SetInstanceStatus("")
+		return
+	}
+
+	latency, available, known := client.InstanceHealth()
+
+	switch {
+	case !known:
+		status = "[gray::b]●[-:-:-] " + status
+
+	case available:
+		status = fmt.Sprintf("[green::b]●[-:-:-] %s (%dms)", status, latency.Milliseconds())
+
+	default:
+		status = "[red::b]●[-:-:-] " + status + " (unavailable)"
+	}
+
+	app.SetInstanceStatus(status)
 }
 
 // checkInstance checks the instance.
@@ -111,3 +170,28 @@ func checkInstance(instance string, table *tview.Table) {
 
 	app.ShowInfo("Set client to "+instance, false)
 }
+
+// ProbeInstances probes the instances from the 'instances' option,
+// and switches to the instance with the lowest latency.
+func ProbeInstances() {
+	instances := cmd.Instances()
+	if instances == nil {
+		app.ShowError(fmt.Errorf("Instances: No instances are configured"))
+		return
+	}
+
+	app.ShowInfo("Probing instances", true)
+
+	instance, latency, err := client.ProbeInstances(instances)
+	if err != nil {
+		app.ShowError(err)
+		return
+	}
+
+	client.SetHost(instance)
+
+	app.ShowInfo(fmt.Sprintf(
+		"Instance '%s' selected (%dms)",
+		utils.GetHostname(instance), latency.Milliseconds(),
+	), false)
+}
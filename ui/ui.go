@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/darkhz/invidtui/client"
 	"github.com/darkhz/invidtui/cmd"
 	mp "github.com/darkhz/invidtui/mediaplayer"
@@ -21,6 +24,14 @@ func SetupUI() {
 	app.SetResizeHandler(Resize)
 	app.SetGlobalKeybindings(Keybindings)
 
+	client.SetSwitchHandler(func(instance string) {
+		app.ShowInfo("Instance unavailable, switched to '"+utils.GetHostname(instance)+"'", false)
+	})
+	client.SetHealthHandler(func() {
+		app.UI.QueueUpdateDraw(popup.UpdateInstanceStatus)
+	})
+	popup.UpdateInstanceStatus()
+
 	instance := utils.GetHostname(client.Instance())
 	msg := "Instance '" + instance + "' selected. "
 	msg += "Press / to search."
@@ -95,6 +106,9 @@ func Keybindings(event *tcell.EventKey) *tcell.EventKey {
 	case cmd.KeyInstancesList:
 		go popup.ShowInstancesList()
 
+	case cmd.KeyInstancesProbe:
+		go popup.ProbeInstances()
+
 	case cmd.KeyQuit:
 		StopUI()
 	}
@@ -103,19 +117,28 @@ Event:
 	return event
 }
 
-// detectPlayerClose detects if the player has exited abruptly.
+// detectPlayerClose detects if the player has exited abruptly,
+// and attempts to relaunch it.
 func detectPlayerClose() {
-	mp.Player().WaitClosed()
-	mp.Player().Exit()
+	retries, _ := strconv.Atoi(cmd.GetOptionValue("reconnect-retries"))
+	delay, _ := time.ParseDuration(cmd.GetOptionValue("reconnect-delay"))
 
-	select {
-	case <-app.UI.Closed:
-		return
+	for {
+		mp.Player().WaitClosed()
+		mp.Player().Exit()
 
-	default:
-	}
+		select {
+		case <-app.UI.Closed:
+			return
+
+		default:
+		}
 
-	StopUI(struct{}{})
+		if err := mp.Player().Reconnect(retries, delay); err != nil {
+			StopUI(struct{}{})
+			cmd.PrintError("Player has exited", err)
 
-	cmd.PrintError("Player has exited")
+			return
+		}
+	}
 }
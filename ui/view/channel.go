@@ -160,8 +160,16 @@ func (c *ChannelView) EventHandler(pageType string, justView bool) {
 		return
 	}
 
+	c.Show(info.AuthorID, pageType)
+}
+
+// Show loads and shows the channel view for the provided channel ID,
+// according to the provided page type.
+func (c *ChannelView) Show(id, pageType string) {
+	c.Init()
+
 	c.queueWrite(func() {
-		c.currentID = info.AuthorID
+		c.currentID = id
 		for _, i := range c.Tabs().Info {
 			ct := c.tableMap[i.Title]
 			ct.table.Clear()
@@ -295,6 +295,32 @@ func (d *DashboardView) ModifyHandler(add bool) {
 	}(info, d.modifyMap[info.Type], d.views.HasFocus())
 }
 
+// AddToPlaylist shows a list of the user's playlists, and adds
+// the provided video to the selected playlist.
+func (d *DashboardView) AddToPlaylist(info inv.SearchData) {
+	d.Init()
+
+	if !client.IsAuthInstance() {
+		app.ShowError(fmt.Errorf("View: Dashboard: Authentication is required to add videos to playlists"))
+		return
+	}
+
+	go func(lock *semaphore.Weighted) {
+		if !client.CurrentTokenValid() {
+			app.ShowError(fmt.Errorf("View: Dashboard: The authentication token is invalid"))
+			return
+		}
+
+		if !lock.TryAcquire(1) {
+			app.ShowInfo("Operation in progress for video", false)
+			return
+		}
+		defer lock.Release(1)
+
+		d.modifyVideoInPlaylist(info, true, lock)
+	}(d.modifyMap["video"])
+}
+
 // PlaylistForm displays a form to create/edit a user playlist.
 func (d *DashboardView) PlaylistForm(edit bool) {
 	var modal *app.Modal
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Scores for the characters of a fuzzy match.
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 4
+	fuzzyWordStartBonus   = 3
+	fuzzyGapPenalty       = 1
+)
+
+// FuzzyMatch matches the provided pattern against the text, and returns the match score,
+// the positions of the matched runes within the text, and whether the text matched.
+// Each word of the pattern must be a case-insensitive subsequence of the text, in any order.
+// Matches of consecutive characters and characters at the start of words are scored higher,
+// and gaps between matched characters are scored lower, so that the best match has the
+// highest score.
+func FuzzyMatch(text, pattern string) (int, []int, bool) {
+	var score int
+	var positions []int
+
+	runes := []rune(strings.ToLower(text))
+	if len(runes) != len([]rune(text)) {
+		runes = []rune(text)
+		for i, r := range runes {
+			runes[i] = unicode.ToLower(r)
+		}
+	}
+
+	for _, word := range strings.Fields(strings.ToLower(pattern)) {
+		wordScore, wordPositions, ok := fuzzyMatchWord(runes, []rune(word))
+		if !ok {
+			return 0, nil, false
+		}
+
+		score += wordScore
+		positions = append(positions, wordPositions...)
+	}
+
+	return score, positions, true
+}
+
+// fuzzyMatchWord matches the word against the text. Since the first occurrence of
+// the word's first character may not be the best one, the match is attempted from
+// each occurrence of it, and the best scoring match is returned.
+func fuzzyMatchWord(text, word []rune) (int, []int, bool) {
+	var best []int
+	bestScore, found := 0, false
+
+	for start := range text {
+		if text[start] != word[0] {
+			continue
+		}
+
+		score, positions, ok := fuzzyMatchFrom(text, word, start)
+		if !ok {
+			break
+		}
+
+		if !found || score > bestScore {
+			best, bestScore, found = positions, score, true
+		}
+	}
+
+	return bestScore, best, found
+}
+
+// fuzzyMatchFrom greedily matches the word against the text from the provided position.
+func fuzzyMatchFrom(text, word []rune, start int) (int, []int, bool) {
+	var score int
+
+	positions := make([]int, 0, len(word))
+
+	pos := start
+	for _, r := range word {
+		for pos < len(text) && text[pos] != r {
+			pos++
+		}
+		if pos >= len(text) {
+			return 0, nil, false
+		}
+
+		score += fuzzyMatchScore
+		if pos == 0 || !unicode.IsLetter(text[pos-1]) && !unicode.IsDigit(text[pos-1]) {
+			score += fuzzyWordStartBonus
+		}
+
+		if n := len(positions); n > 0 {
+			if gap := pos - positions[n-1] - 1; gap == 0 {
+				score += fuzzyConsecutiveBonus
+			} else {
+				score -= gap * fuzzyGapPenalty
+			}
+		}
+
+		positions = append(positions, pos)
+		pos++
+	}
+
+	return score, positions, true
+}
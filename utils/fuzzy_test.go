@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		text, pattern string
+		score         int
+		positions     []int
+		matched       bool
+	}{
+		{text: "abc", pattern: "", score: 0, positions: nil, matched: true},
+		{text: "abc", pattern: "abd", matched: false},
+		{text: "abc", pattern: "abcd", matched: false},
+		{text: "Hello World", pattern: "hw", score: 3, positions: []int{0, 6}, matched: true},
+		{text: "Hello World", pattern: "HW", score: 3, positions: []int{0, 6}, matched: true},
+		{text: "Hello World", pattern: "wor hel", score: 28, positions: []int{6, 7, 8, 0, 1, 2}, matched: true},
+		{text: "Hello World", pattern: "hel xyz", matched: false},
+		{text: "a-b-c", pattern: "abc", score: 10, positions: []int{0, 2, 4}, matched: true},
+		{text: "Shadow", pattern: "how", score: 5, positions: []int{1, 4, 5}, matched: true},
+		{text: "the show", pattern: "how", score: 11, positions: []int{5, 6, 7}, matched: true},
+		{text: "ÜBER alles", pattern: "über", score: 19, positions: []int{0, 1, 2, 3}, matched: true},
+		{text: "İstanbul", pattern: "ist", score: 14, positions: []int{0, 1, 2}, matched: true},
+	}
+
+	for _, test := range tests {
+		score, positions, matched := FuzzyMatch(test.text, test.pattern)
+		if matched != test.matched {
+			t.Errorf("FuzzyMatch(%q, %q) matched = %v, want %v", test.text, test.pattern, matched, test.matched)
+			continue
+		}
+		if !matched {
+			continue
+		}
+
+		if score != test.score || !reflect.DeepEqual(positions, test.positions) {
+			t.Errorf("FuzzyMatch(%q, %q) = %d, %v, want %d, %v",
+				test.text, test.pattern, score, positions, test.score, test.positions,
+			)
+		}
+	}
+}
+
+func TestFuzzyMatchBestStart(t *testing.T) {
+	// The first 's' does not start the best match,
+	// which is the consecutive one at the start of a word.
+	score, positions, matched := FuzzyMatch("Sister Show", "sho")
+	if !matched {
+		t.Fatal("FuzzyMatch() did not match")
+	}
+	if want := []int{7, 8, 9}; !reflect.DeepEqual(positions, want) {
+		t.Errorf("positions = %v, want %v", positions, want)
+	}
+	if want := 3*fuzzyMatchScore + fuzzyWordStartBonus + 2*fuzzyConsecutiveBonus; score != want {
+		t.Errorf("score = %d, want %d", score, want)
+	}
+}
+
+func TestFuzzyMatchOrdering(t *testing.T) {
+	tests := []struct {
+		pattern string
+		titles  []string
+		want    []string
+	}{
+		{
+			pattern: "now",
+			titles:  []string{"kNOWn", "Night Owl", "Nowhere", "Unknown"},
+			want:    []string{"Nowhere", "kNOWn", "Unknown", "Night Owl"},
+		},
+		{
+			pattern: "how",
+			titles:  []string{"Shadow", "the show", "how to"},
+			want:    []string{"how to", "the show", "Shadow"},
+		},
+		{
+			pattern: "lofi mix",
+			titles:  []string{"Mix of lofi", "lofi beats", "lofi hip hop mix"},
+			want:    []string{"Mix of lofi", "lofi hip hop mix"},
+		},
+	}
+
+	for _, test := range tests {
+		var matches []string
+
+		scores := make(map[string]int)
+		for _, title := range test.titles {
+			if score, _, ok := FuzzyMatch(title, test.pattern); ok {
+				scores[title] = score
+				matches = append(matches, title)
+			}
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			return scores[matches[i]] > scores[matches[j]]
+		})
+
+		if !reflect.DeepEqual(matches, test.want) {
+			t.Errorf("%q: ordered %v (scores %v), want %v", test.pattern, matches, scores, test.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// LogLevel describes the severity of a log message.
+type LogLevel int
+
+// The different log levels.
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// Logger describes the leveled logger, which writes to the log file.
+type Logger struct {
+	level  LogLevel
+	logger *log.Logger
+
+	mutex sync.Mutex
+}
+
+var (
+	logger Logger
+
+	logLevels = map[string]LogLevel{
+		"debug": LogDebug,
+		"info":  LogInfo,
+		"warn":  LogWarn,
+		"error": LogError,
+	}
+
+	logURLRegex = regexp.MustCompile(`https?://[^\s"']+`)
+
+	// logRedactedParams lists the URL parameters that contain
+	// signatures or tokens, whose values are redacted from the log.
+	logRedactedParams = []string{
+		"sig", "signature", "lsig", "key", "pot",
+		"token", "access_token", "api_key", "api_sig", "sk", "ip",
+	}
+)
+
+// SetupLog opens the log file at the provided path, and sets the minimum level of
+// messages to be logged. The previous log file, if any, is kept with the ".old" suffix.
+func SetupLog(path, level string) error {
+	logLevel, ok := logLevels[level]
+	if !ok {
+		return fmt.Errorf("Log: Invalid log level %s", level)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		os.Rename(path, path+".old")
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("Log: Cannot create log file at %s", path)
+	}
+
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	logger.level = logLevel
+	logger.logger = log.New(file, "", log.LstdFlags)
+
+	return nil
+}
+
+// IsLogLevel returns whether the provided text is a valid log level.
+func IsLogLevel(level string) bool {
+	_, ok := logLevels[level]
+
+	return ok
+}
+
+// LogDebugf logs a debug message.
+func LogDebugf(format string, args ...interface{}) {
+	writeLog(LogDebug, "DEBUG", format, args...)
+}
+
+// LogInfof logs an informational message.
+func LogInfof(format string, args ...interface{}) {
+	writeLog(LogInfo, "INFO", format, args...)
+}
+
+// LogWarnf logs a warning.
+func LogWarnf(format string, args ...interface{}) {
+	writeLog(LogWarn, "WARN", format, args...)
+}
+
+// LogErrorf logs an error.
+func LogErrorf(format string, args ...interface{}) {
+	writeLog(LogError, "ERROR", format, args...)
+}
+
+// writeLog writes the message to the log file, if the level of the message
+// is at least the configured level. Any URLs within the message are redacted.
+func writeLog(level LogLevel, prefix, format string, args ...interface{}) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.logger == nil || level < logger.level {
+		return
+	}
+
+	message := logURLRegex.ReplaceAllStringFunc(fmt.Sprintf(format, args...), redactURL)
+
+	logger.logger.Printf("[%s] %s", prefix, message)
+}
+
+// redactURL returns the provided URL with the values of
+// parameters that contain signatures or tokens redacted.
+func redactURL(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	query := u.Query()
+
+	var redacted bool
+	for _, param := range logRedactedParams {
+		if query.Has(param) {
+			query.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+
+	// Parameters may contain other URLs, for example the audio stream
+	// URL within the options of a queued video stream URL.
+	for _, values := range query {
+		for i, value := range values {
+			if v := logURLRegex.ReplaceAllStringFunc(value, redactURL); v != value {
+				values[i], redacted = v, true
+			}
+		}
+	}
+
+	// Stream URLs may also contain the parameters as path segments.
+	segments := strings.Split(u.Path, "/")
+	for i := 0; i < len(segments)-1; i++ {
+		for _, param := range logRedactedParams {
+			if segments[i] == param {
+				segments[i+1] = "REDACTED"
+				redacted = true
+			}
+		}
+	}
+
+	if !redacted {
+		return uri
+	}
+
+	u.RawQuery = query.Encode()
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+
+	return u.String()
+}
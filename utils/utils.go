@@ -3,7 +3,10 @@ package utils
 import (
 	"fmt"
 	"net/url"
+	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -246,6 +249,10 @@ func GetVPIDFromURL(uri string) (string, string, error) {
 		return u.Query().Get("list"), "playlist", nil
 	}
 
+	if strings.Contains(uri, "/clip/") {
+		return "", "", fmt.Errorf("clip URLs cannot be resolved, use the shared video URL instead")
+	}
+
 	if strings.Contains(uri, "/channel") ||
 		(strings.HasPrefix(uri, "UC") && len(uri) >= 24) {
 		return "", "", fmt.Errorf("the URL or ID is a channel")
@@ -258,6 +265,60 @@ func GetVPIDFromURL(uri string) (string, string, error) {
 	return uri, "video", nil
 }
 
+// GetTimeRangeFromURL gets the positions in seconds at which the playback
+// of a shared video URL should start and end. The start position is read from
+// the 't' parameter or fragment, or the 'start' parameter, and the end position
+// from the 'end' parameter. Positions are in seconds, or in the '1h2m3s' format.
+func GetTimeRangeFromURL(uri string) (int64, int64) {
+	if !strings.HasPrefix(uri, "https://") && !strings.HasPrefix(uri, "http://") {
+		uri = "https://" + uri
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return 0, 0
+	}
+
+	query := u.Query()
+	if fragment, err := url.ParseQuery(u.Fragment); err == nil && query.Get("t") == "" {
+		query.Set("t", fragment.Get("t"))
+	}
+
+	start := ParseTimestamp(query.Get("t"))
+	if start == 0 {
+		start = ParseTimestamp(query.Get("start"))
+	}
+
+	return start, ParseTimestamp(query.Get("end"))
+}
+
+// ParseTimestamp parses a timestamp in seconds, with an optional 's' suffix,
+// or in the '1h2m3s' format. If the timestamp is invalid, zero is returned.
+func ParseTimestamp(timestamp string) int64 {
+	if timestamp == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseInt(strings.TrimSuffix(timestamp, "s"), 10, 64); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+
+		return seconds
+	}
+
+	if strings.ContainsAny(timestamp, ".-+") {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(timestamp)
+	if err != nil {
+		return 0
+	}
+
+	return int64(duration / time.Second)
+}
+
 // GetHostname gets the hostname of the given URL.
 func GetHostname(hostURL string) string {
 	uri, _ := url.Parse(hostURL)
@@ -275,3 +336,108 @@ func GetHostname(hostURL string) string {
 func GetUnixTimeAfter(years int) int64 {
 	return time.Now().AddDate(years, 0, 0).Unix()
 }
+
+// CopyToClipboard copies the provided text to the system clipboard,
+// using the first available clipboard utility.
+func CopyToClipboard(text string) error {
+	var commands [][]string
+
+	switch runtime.GOOS {
+	case "windows":
+		commands = [][]string{{"clip"}}
+
+	case "darwin":
+		commands = [][]string{{"pbcopy"}}
+
+	default:
+		commands = [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	}
+
+	for _, command := range commands {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+
+		clip := exec.Command(command[0], command[1:]...)
+		clip.Stdin = strings.NewReader(text)
+
+		if err := clip.Run(); err != nil {
+			return fmt.Errorf("Clipboard: Could not copy text: %w", err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("Clipboard: No clipboard utility found")
+}
+
+// OpenInBrowser opens the provided URL in the default web browser.
+func OpenInBrowser(uri string) error {
+	var command []string
+
+	switch runtime.GOOS {
+	case "windows":
+		command = []string{"rundll32", "url.dll,FileProtocolHandler"}
+
+	case "darwin":
+		command = []string{"open"}
+
+	default:
+		command = []string{"xdg-open"}
+	}
+
+	if _, err := exec.LookPath(command[0]); err != nil {
+		return fmt.Errorf("Browser: %s was not found", command[0])
+	}
+
+	browser := exec.Command(command[0], append(command[1:], uri)...)
+	if err := browser.Start(); err != nil {
+		return fmt.Errorf("Browser: Could not open %s: %w", uri, err)
+	}
+
+	go browser.Wait()
+
+	return nil
+}
+
+// WriteFileAtomic writes the data to a temporary file in the same directory as
+// the provided path, and renames it to the path once it is fully written, so that
+// the file is never left partially written. If the file exists, its permissions
+// are preserved, otherwise the provided permissions are used.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmp := file.Name()
+	defer os.Remove(tmp)
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp, perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
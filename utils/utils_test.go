@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tempFiles returns the temporary files left in the provided directory.
+func tempFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return files
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+
+	if err := WriteFileAtomic(path, []byte("first"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFile(t, path, "first", 0640)
+
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileAtomic(path, []byte("second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	assertFile(t, path, "second", 0600)
+
+	if files := tempFiles(t, dir); len(files) != 0 {
+		t.Errorf("temporary files were left behind: %v", files)
+	}
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := WriteFileAtomic(filepath.Join(dir, "missing", "file"), []byte("data"), 0644); err == nil {
+		t.Error("writing to a missing directory returned no error")
+	}
+
+	// The rename fails, since the path is a directory which is not empty.
+	path := filepath.Join(dir, "target")
+	if err := os.MkdirAll(filepath.Join(path, "child"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("data"), 0644); err == nil {
+		t.Error("replacing a directory returned no error")
+	}
+
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		t.Errorf("directory was replaced: %v", err)
+	}
+	if files := tempFiles(t, dir); len(files) != 0 {
+		t.Errorf("temporary files were left behind: %v", files)
+	}
+}
+
+func assertFile(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("content = %q, want %q", data, content)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != perm {
+		t.Errorf("permissions = %v, want %v", info.Mode().Perm(), perm)
+	}
+}
+
+func TestGetTimeRangeFromURL(t *testing.T) {
+	tests := []struct {
+		uri        string
+		start, end int64
+	}{
+		{uri: "https://www.youtube.com/watch?v=abc", start: 0, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=90", start: 90, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=90s", start: 90, end: 0},
+		{uri: "https://youtu.be/abc?t=1h2m3s", start: 3723, end: 0},
+		{uri: "youtu.be/abc?t=2m", start: 120, end: 0},
+		{uri: "https://www.youtube.com/embed/abc?start=30&end=60", start: 30, end: 60},
+		{uri: "https://www.youtube.com/embed/abc?end=45", start: 0, end: 45},
+		{uri: "https://www.youtube.com/watch?v=abc#t=75", start: 75, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=10#t=75", start: 10, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=0&start=20", start: 20, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=-5", start: 0, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=1.5s", start: 0, end: 0},
+		{uri: "https://www.youtube.com/watch?v=abc&t=soon", start: 0, end: 0},
+		{uri: "http://[::1", start: 0, end: 0},
+	}
+
+	for _, test := range tests {
+		if start, end := GetTimeRangeFromURL(test.uri); start != test.start || end != test.end {
+			t.Errorf("GetTimeRangeFromURL(%q) = %d, %d, want %d, %d", test.uri, start, end, test.start, test.end)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		timestamp string
+		want      int64
+	}{
+		{timestamp: "", want: 0},
+		{timestamp: "0", want: 0},
+		{timestamp: "42", want: 42},
+		{timestamp: "42s", want: 42},
+		{timestamp: "3m", want: 180},
+		{timestamp: "1h30s", want: 3630},
+		{timestamp: "1h2m3s", want: 3723},
+		{timestamp: "-42", want: 0},
+		{timestamp: "-1m", want: 0},
+		{timestamp: "+1m", want: 0},
+		{timestamp: "1.5m", want: 0},
+		{timestamp: "1d", want: 0},
+		{timestamp: "s", want: 0},
+	}
+
+	for _, test := range tests {
+		if seconds := ParseTimestamp(test.timestamp); seconds != test.want {
+			t.Errorf("ParseTimestamp(%q) = %d, want %d", test.timestamp, seconds, test.want)
+		}
+	}
+}